@@ -11,8 +11,9 @@ import (
 
 // package const
 const (
-	servicePortENV = "SERVICE_PORT_ENV"
-	versionAPIENV  = "VERSION_API_ENV"
+	servicePortENV    = "SERVICE_PORT_ENV"
+	versionAPIENV     = "VERSION_API_ENV"
+	trustedProxiesENV = "SERVICE_TRUSTED_PROXIES_ENV"
 
 	dbNameENV       = "DB_NAME_ENV"
 	dbHostENV       = "DB_HOST_ENV"
@@ -32,14 +33,84 @@ const (
 	notifierTimeOutENV         = "NOTIFIER_TIMEOUT_ENV"
 	notifierClientMaxRetryENV  = "NOTIFIER_CLIENT_MAX_RETRY_ENV"
 	notifierTimeoutIncreaceENV = "NOTIFIER_TIMEOUT_INCREACE_ENV"
+	notifierMaxBackoffENV      = "NOTIFIER_MAX_BACKOFF_ENV"
+	notifierBreakerThreshold   = "NOTIFIER_BREAKER_THRESHOLD_ENV"
+	notifierBreakerTimeout     = "NOTIFIER_BREAKER_TIMEOUT_ENV"
+	notifierSubscriptionsENV   = "NOTIFIER_SUBSCRIPTIONS_REFRESH_ENV"
+	notifierSecretENV          = "NOTIFIER_SIGNING_SECRET_ENV"
+	notifierPreviousSecretENV  = "NOTIFIER_PREVIOUS_SIGNING_SECRET_ENV"
+	notifierSecretRotationENV  = "NOTIFIER_SECRET_ROTATION_TTL_ENV"
+	notifierContentModeENV     = "NOTIFIER_CONTENT_MODE_ENV"
+
+	queueSizeENV        = "QUEUE_SIZE_ENV"
+	goRoutinesSizeENV   = "GO_ROUTINE_SIZE_ENV"
+	queuePersistentENV  = "QUEUE_PERSISTENT_ENV"
+	queueDirENV         = "QUEUE_DIR_ENV"
+	queueSegmentSizeENV = "QUEUE_SEGMENT_SIZE_ENV"
+	queueOrderedENV     = "QUEUE_ORDERED_ENV"
+	queueShutdownENV    = "QUEUE_SHUTDOWN_TIMEOUT_ENV"
+	queueBackendENV     = "QUEUE_BACKEND_ENV"
+	queuePollENV        = "QUEUE_POLL_INTERVAL_ENV"
+	queueSpoolDirENV    = "QUEUE_SPOOL_DIR_ENV"
+	queueStuckENV       = "QUEUE_STUCK_TIMEOUT_ENV"
+
+	outboxPollIntervalENV = "OUTBOX_POLL_INTERVAL_ENV"
+	outboxDelayENV        = "OUTBOX_DELAY_ENV"
+	outboxBatchSizeENV    = "OUTBOX_BATCH_SIZE_ENV"
+
+	purgePollIntervalENV = "PURGE_POLL_INTERVAL_ENV"
+	purgeRetentionENV    = "PURGE_RETENTION_ENV"
+	purgeBatchSizeENV    = "PURGE_BATCH_SIZE_ENV"
+
+	importWorkersENV   = "IMPORT_WORKERS_ENV"
+	importBatchSizeENV = "IMPORT_BATCH_SIZE_ENV"
+	importMaxRowsENV   = "IMPORT_MAX_ROWS_ENV"
+	importTimeoutENV   = "IMPORT_TIMEOUT_ENV"
+	importMaxLineENV   = "IMPORT_MAX_LINE_SIZE_ENV"
+)
+
+// queue backends
+const (
+	QueueBackendMemory   = "memory"
+	QueueBackendPostgres = "postgres"
+	QueueBackendSpool    = "spool"
+)
 
-	queueSizeENV      = "QUEUE_SIZE_ENV"
-	goRoutinesSizeENV = "GO_ROUTINE_SIZE_ENV"
+// CloudEvents content modes of notifications
+const (
+	ContentModeStructured = "structured"
+	ContentModeBinary     = "binary"
 )
 
 var (
-	queueSizeDefault      = 100
-	goRoutinesSizeDefault = 100
+	notifierMaxBackoffDefault       = 30
+	notifierBreakerThresholdDefault = 5
+	notifierBreakerTimeoutDefault   = 30
+	notifierSubscriptionsDefault    = 10
+	notifierSecretRotationDefault   = 24 * 60 * 60
+
+	queueSizeDefault        = 100
+	goRoutinesSizeDefault   = 100
+	queueDirDefault         = "queue_data"
+	queueSegmentSizeDefault = 1000
+	queueShutdownDefault    = 30
+	queuePollDefault        = 5
+	queueSpoolDirDefault    = "queue_spool"
+	queueStuckDefault       = 300
+
+	outboxPollIntervalDefault = 5
+	outboxDelayDefault        = 30
+	outboxBatchSizeDefault    = 100
+
+	purgePollIntervalDefault = 3600
+	purgeRetentionDefault    = 30
+	purgeBatchSizeDefault    = 100
+
+	importWorkersDefault   = 4
+	importBatchSizeDefault = 100
+	importMaxRowsDefault   = 10000
+	importTimeoutDefault   = 600
+	importMaxLineDefault   = 4096
 )
 
 // package errors
@@ -48,9 +119,11 @@ var (
 )
 
 // Service is a struct with service configuration
+// TrustedProxies is a list of IPs and CIDRs of proxies, that are allowed to set X-Actor and X-Forwarded-For headers
 type Service struct {
-	Port       string
-	VersionAPI string
+	Port           string
+	VersionAPI     string
+	TrustedProxies []string
 }
 
 // DB is a struct with database cofiguration
@@ -72,17 +145,48 @@ type Logger struct {
 }
 
 // Notifier is a struct with notifier configuration
+// ClientTimeoutIncrease is a base of exponential backoff between retries, MaxBackoff is it's limit,
+// consumer's circuit breaker opens after BreakerThreshold consecutive failures for BreakerTimeout,
+// stored subscriptions are reloaded every SubscriptionsRefresh,
+// deliveries to consumers from Consumers are signed with SigningSecret and PreviousSigningSecret (if set),
+// previous secret of rotated subscription is active for SecretRotationTTL,
+// ContentMode is a CloudEvents HTTP content mode of notifications (ContentModeStructured or ContentModeBinary),
+// all durations are in seconds
 type Notifier struct {
 	Consumers             Consumers
 	Timeout               int
 	ClientMaxRetry        int
 	ClientTimeoutIncrease int
+	MaxBackoff            int
+	BreakerThreshold      int
+	BreakerTimeout        int
+	SubscriptionsRefresh  int
+	SigningSecret         string
+	PreviousSigningSecret string
+	SecretRotationTTL     int
+	ContentMode           string
 }
 
 // Queue is a queue config struct
+// if Persistent is true, queued messages are journaled into Dir,
+// SegmentSize is a number of records in one journal segment file,
+// if Ordered is true, messages with the same key are delivered one by one in order they were queued,
+// ShutdownTimeout is a time in seconds, given to queue to deliver queued messages on stop,
+// Backend is one of QueueBackendMemory, QueueBackendPostgres and QueueBackendSpool,
+// shared backends are polled every PollInterval seconds, spool is stored in SpoolDir,
+// queue is reported unhealthy, if it's oldest message is waiting for more than StuckTimeout seconds
 type Queue struct {
-	QueueSize      int
-	GoRoutinesSize int
+	QueueSize       int
+	GoRoutinesSize  int
+	Persistent      bool
+	Dir             string
+	SegmentSize     int
+	Ordered         bool
+	ShutdownTimeout int
+	Backend         string
+	PollInterval    int
+	SpoolDir        string
+	StuckTimeout    int
 }
 
 // OnCreate returnes a list of consumers to notify on Create action
@@ -100,6 +204,40 @@ func (n Notifier) OnDelete() []string {
 	return n.Consumers.OnDelete
 }
 
+// Outbox is an outbox relay config struct
+// PollInterval and Delay are in seconds
+// Delay is a time, given to queue to deliver a message, before relay picks it up
+type Outbox struct {
+	PollInterval int
+	Delay        int
+	BatchSize    int
+}
+
+// Purge is a deleted users purger config struct
+// PollInterval is in seconds, Retention is a number of days, deleted user is kept for
+type Purge struct {
+	PollInterval int
+	Retention    int
+	BatchSize    int
+}
+
+// Import is a users import config struct
+// passwords are hashed by Workers goroutines, users are created in transactions of BatchSize users,
+// one import could have at most MaxRows rows of at most MaxLineSize bytes, and must be done in Timeout seconds,
+// default Timeout gives 240 milliseconds to hash one password of MaxRows on every worker
+type Import struct {
+	Workers     int
+	BatchSize   int
+	MaxRows     int
+	Timeout     int
+	MaxLineSize int
+}
+
+// MaxBodySize returns a limit of import body in bytes, it fits MaxRows and csv header of MaxLineSize
+func (i Import) MaxBodySize() int64 {
+	return int64(i.MaxRows+1) * int64(i.MaxLineSize+1)
+}
+
 type Consumers struct {
 	OnCreate []string
 	OnUpdate []string
@@ -114,6 +252,9 @@ type Config struct {
 	logger   Logger
 	notifier Notifier
 	queue    Queue
+	outbox   Outbox
+	purge    Purge
+	imports  Import
 }
 
 // New initiates a new Configuration instance
@@ -143,6 +284,9 @@ func New() (*Config, error) {
 	}
 
 	cfg.setQueue()
+	cfg.setOutbox()
+	cfg.setPurge()
+	cfg.setImport()
 
 	return cfg, nil
 }
@@ -153,8 +297,9 @@ func (c *Config) Service() Service {
 	defer c.mu.RUnlock()
 
 	return Service{
-		Port:       c.service.Port,
-		VersionAPI: c.service.VersionAPI,
+		Port:           c.service.Port,
+		VersionAPI:     c.service.VersionAPI,
+		TrustedProxies: append([]string(nil), c.service.TrustedProxies...),
 	}
 }
 
@@ -192,8 +337,18 @@ func (c *Config) Notifier() Notifier {
 	defer c.mu.RUnlock()
 
 	return Notifier{
-		Consumers: c.notifier.Consumers,
-		Timeout:   c.notifier.Timeout,
+		Consumers:             c.notifier.Consumers,
+		Timeout:               c.notifier.Timeout,
+		ClientMaxRetry:        c.notifier.ClientMaxRetry,
+		ClientTimeoutIncrease: c.notifier.ClientTimeoutIncrease,
+		MaxBackoff:            c.notifier.MaxBackoff,
+		BreakerThreshold:      c.notifier.BreakerThreshold,
+		BreakerTimeout:        c.notifier.BreakerTimeout,
+		SubscriptionsRefresh:  c.notifier.SubscriptionsRefresh,
+		SigningSecret:         c.notifier.SigningSecret,
+		PreviousSigningSecret: c.notifier.PreviousSigningSecret,
+		SecretRotationTTL:     c.notifier.SecretRotationTTL,
+		ContentMode:           c.notifier.ContentMode,
 	}
 }
 
@@ -202,8 +357,55 @@ func (c *Config) Queue() Queue {
 	defer c.mu.RUnlock()
 
 	return Queue{
-		QueueSize:      c.queue.QueueSize,
-		GoRoutinesSize: c.queue.GoRoutinesSize,
+		QueueSize:       c.queue.QueueSize,
+		GoRoutinesSize:  c.queue.GoRoutinesSize,
+		Persistent:      c.queue.Persistent,
+		Dir:             c.queue.Dir,
+		SegmentSize:     c.queue.SegmentSize,
+		Ordered:         c.queue.Ordered,
+		ShutdownTimeout: c.queue.ShutdownTimeout,
+		Backend:         c.queue.Backend,
+		PollInterval:    c.queue.PollInterval,
+		SpoolDir:        c.queue.SpoolDir,
+		StuckTimeout:    c.queue.StuckTimeout,
+	}
+}
+
+// Outbox returns a copy of Outbox config
+func (c *Config) Outbox() Outbox {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Outbox{
+		PollInterval: c.outbox.PollInterval,
+		Delay:        c.outbox.Delay,
+		BatchSize:    c.outbox.BatchSize,
+	}
+}
+
+// Purge returns a copy of Purge config
+func (c *Config) Purge() Purge {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Purge{
+		PollInterval: c.purge.PollInterval,
+		Retention:    c.purge.Retention,
+		BatchSize:    c.purge.BatchSize,
+	}
+}
+
+// Import returns a copy of Import config
+func (c *Config) Import() Import {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Import{
+		Workers:     c.imports.Workers,
+		BatchSize:   c.imports.BatchSize,
+		MaxRows:     c.imports.MaxRows,
+		Timeout:     c.imports.Timeout,
+		MaxLineSize: c.imports.MaxLineSize,
 	}
 }
 
@@ -223,8 +425,9 @@ func (c *Config) setService() error {
 	defer c.mu.Unlock()
 
 	c.service = Service{
-		Port:       port,
-		VersionAPI: versionAPI,
+		Port:           port,
+		VersionAPI:     versionAPI,
+		TrustedProxies: getStringSliceENV(trustedProxiesENV),
 	}
 
 	return nil
@@ -335,33 +538,228 @@ func (c *Config) setNotifier() error {
 		return err
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	maxBackoff, err := getIntENV(notifierMaxBackoffENV)
+	if err != nil {
+		maxBackoff = notifierMaxBackoffDefault
+	}
+
+	breakerThreshold, err := getIntENV(notifierBreakerThreshold)
+	if err != nil {
+		breakerThreshold = notifierBreakerThresholdDefault
+	}
+
+	breakerTimeout, err := getIntENV(notifierBreakerTimeout)
+	if err != nil {
+		breakerTimeout = notifierBreakerTimeoutDefault
+	}
+
+	subscriptionsRefresh, err := getIntENV(notifierSubscriptionsENV)
+	if err != nil {
+		subscriptionsRefresh = notifierSubscriptionsDefault
+	}
+
+	// secrets are optional, deliveries to consumers from config are not signed without them
+	secret, _ := getENV(notifierSecretENV)
+	previousSecret, _ := getENV(notifierPreviousSecretENV)
+
+	secretRotationTTL, err := getIntENV(notifierSecretRotationENV)
+	if err != nil {
+		secretRotationTTL = notifierSecretRotationDefault
+	}
+
+	contentMode, err := getENV(notifierContentModeENV)
+	if err != nil {
+		contentMode = ContentModeStructured
+	}
+
+	if contentMode != ContentModeStructured && contentMode != ContentModeBinary {
+		return fmt.Errorf("invalid %s value %q, expected %s or %s",
+			notifierContentModeENV, contentMode, ContentModeStructured, ContentModeBinary)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	c.notifier = Notifier{
 		Consumers:             consumers,
 		Timeout:               timeOut,
 		ClientMaxRetry:        retry,
 		ClientTimeoutIncrease: timeOutIncreace,
+		MaxBackoff:            maxBackoff,
+		BreakerThreshold:      breakerThreshold,
+		BreakerTimeout:        breakerTimeout,
+		SubscriptionsRefresh:  subscriptionsRefresh,
+		SigningSecret:         secret,
+		PreviousSigningSecret: previousSecret,
+		SecretRotationTTL:     secretRotationTTL,
+		ContentMode:           contentMode,
 	}
 
 	return nil
 }
 
+// setQueue sets Queue config, default values are used for missing ones
 func (c *Config) setQueue() {
 	queueSize, err := getIntENV(queueSizeENV)
-	if err != nil {
+	if err != nil || queueSize < 1 {
 		queueSize = queueSizeDefault
 	}
 
 	goRoutineSize, err := getIntENV(goRoutinesSizeENV)
-	if err != nil {
+	if err != nil || goRoutineSize < 1 {
 		goRoutineSize = goRoutinesSizeDefault
 	}
 
+	persistent, err := getBoolENV(queuePersistentENV)
+	if err != nil {
+		persistent = false
+	}
+
+	dir, err := getENV(queueDirENV)
+	if err != nil {
+		dir = queueDirDefault
+	}
+
+	segmentSize, err := getIntENV(queueSegmentSizeENV)
+	if err != nil || segmentSize < 1 {
+		segmentSize = queueSegmentSizeDefault
+	}
+
+	ordered, err := getBoolENV(queueOrderedENV)
+	if err != nil {
+		ordered = false
+	}
+
+	shutdownTimeout, err := getIntENV(queueShutdownENV)
+	if err != nil || shutdownTimeout < 1 {
+		shutdownTimeout = queueShutdownDefault
+	}
+
+	backend, err := getENV(queueBackendENV)
+	if err != nil {
+		backend = QueueBackendMemory
+	}
+
+	pollInterval, err := getIntENV(queuePollENV)
+	if err != nil || pollInterval < 1 {
+		pollInterval = queuePollDefault
+	}
+
+	spoolDir, err := getENV(queueSpoolDirENV)
+	if err != nil {
+		spoolDir = queueSpoolDirDefault
+	}
+
+	stuckTimeout, err := getIntENV(queueStuckENV)
+	if err != nil || stuckTimeout < 1 {
+		stuckTimeout = queueStuckDefault
+	}
+
 	c.queue = Queue{
-		QueueSize:      queueSize,
-		GoRoutinesSize: goRoutineSize,
+		QueueSize:       queueSize,
+		GoRoutinesSize:  goRoutineSize,
+		Persistent:      persistent,
+		Dir:             dir,
+		SegmentSize:     segmentSize,
+		Ordered:         ordered,
+		ShutdownTimeout: shutdownTimeout,
+		Backend:         backend,
+		PollInterval:    pollInterval,
+		SpoolDir:        spoolDir,
+		StuckTimeout:    stuckTimeout,
+	}
+}
+
+// setOutbox sets Outbox config, default values are used for missing ones
+func (c *Config) setOutbox() {
+	pollInterval, err := getIntENV(outboxPollIntervalENV)
+	if err != nil || pollInterval < 1 {
+		pollInterval = outboxPollIntervalDefault
+	}
+
+	delay, err := getIntENV(outboxDelayENV)
+	if err != nil || delay < 1 {
+		delay = outboxDelayDefault
+	}
+
+	batchSize, err := getIntENV(outboxBatchSizeENV)
+	if err != nil || batchSize < 1 {
+		batchSize = outboxBatchSizeDefault
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.outbox = Outbox{
+		PollInterval: pollInterval,
+		Delay:        delay,
+		BatchSize:    batchSize,
+	}
+}
+
+// setPurge sets Purge config, default values are used for missing ones
+func (c *Config) setPurge() {
+	pollInterval, err := getIntENV(purgePollIntervalENV)
+	if err != nil || pollInterval < 1 {
+		pollInterval = purgePollIntervalDefault
+	}
+
+	retention, err := getIntENV(purgeRetentionENV)
+	if err != nil || retention < 1 {
+		retention = purgeRetentionDefault
+	}
+
+	batchSize, err := getIntENV(purgeBatchSizeENV)
+	if err != nil || batchSize < 1 {
+		batchSize = purgeBatchSizeDefault
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge = Purge{
+		PollInterval: pollInterval,
+		Retention:    retention,
+		BatchSize:    batchSize,
+	}
+}
+
+// setImport sets Import config, default values are used for missing ones
+func (c *Config) setImport() {
+	workers, err := getIntENV(importWorkersENV)
+	if err != nil || workers < 1 {
+		workers = importWorkersDefault
+	}
+
+	batchSize, err := getIntENV(importBatchSizeENV)
+	if err != nil || batchSize < 1 {
+		batchSize = importBatchSizeDefault
+	}
+
+	maxRows, err := getIntENV(importMaxRowsENV)
+	if err != nil || maxRows < 1 {
+		maxRows = importMaxRowsDefault
+	}
+
+	timeout, err := getIntENV(importTimeoutENV)
+	if err != nil || timeout < 1 {
+		timeout = importTimeoutDefault
+	}
+
+	maxLineSize, err := getIntENV(importMaxLineENV)
+	if err != nil || maxLineSize < 1 {
+		maxLineSize = importMaxLineDefault
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.imports = Import{
+		Workers:     workers,
+		BatchSize:   batchSize,
+		MaxRows:     maxRows,
+		Timeout:     timeout,
+		MaxLineSize: maxLineSize,
 	}
 }
 
@@ -403,5 +801,13 @@ func getIntENV(name string) (int, error) {
 }
 
 func getStringSliceENV(name string) []string {
-	return strings.Split(os.Getenv(name), ",")
+	values := []string{}
+
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
 }
@@ -12,6 +12,9 @@ type Faceitstring string
 // package constant
 const (
 	processID Faceitstring = "processID"
+	eventID   Faceitstring = "eventID"
+	actor     Faceitstring = "actor"
+	sourceIP  Faceitstring = "sourceIP"
 )
 
 // SetProcessID generates new uuid and sets it as a processID into the context
@@ -34,3 +37,48 @@ func ProcessID(ctx context.Context) string {
 
 	return value.(string)
 }
+
+// SetEventID sets id of notification event into the context
+func SetEventID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, eventID, id)
+}
+
+// EventID gets an id of notification event from the context
+func EventID(ctx context.Context) string {
+	value := ctx.Value(eventID)
+	if value == nil {
+		return ""
+	}
+
+	return value.(string)
+}
+
+// SetActor sets a name of who made the request into the context
+func SetActor(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, actor, name)
+}
+
+// Actor gets a name of who made the request from the context
+func Actor(ctx context.Context) string {
+	value := ctx.Value(actor)
+	if value == nil {
+		return ""
+	}
+
+	return value.(string)
+}
+
+// SetSourceIP sets an IP address, request came from, into the context
+func SetSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, sourceIP, ip)
+}
+
+// SourceIP gets an IP address, request came from, from the context
+func SourceIP(ctx context.Context) string {
+	value := ctx.Value(sourceIP)
+	if value == nil {
+		return ""
+	}
+
+	return value.(string)
+}
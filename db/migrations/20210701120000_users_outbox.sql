@@ -0,0 +1,18 @@
+-- migrate:up
+
+CREATE TABLE users_outbox (
+    outbox_id SERIAL,
+    event_id varchar(36) NOT NULL,
+    action varchar(10) NOT NULL,
+    payload text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    delivered_at timestamp,
+    PRIMARY KEY (outbox_id)
+);
+
+CREATE INDEX users_outbox_event_id_idx ON users_outbox (event_id);
+CREATE INDEX users_outbox_pending_idx ON users_outbox (created_at) WHERE delivered_at IS NULL;
+
+-- migrate:down
+
+DROP TABLE users_outbox;
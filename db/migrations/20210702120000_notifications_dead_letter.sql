@@ -0,0 +1,18 @@
+-- migrate:up
+
+CREATE TABLE notifications_dead_letter (
+    dead_letter_id SERIAL,
+    consumer varchar(255) NOT NULL,
+    last_error text NOT NULL,
+    attempts INTEGER NOT NULL,
+    payload text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    updated_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    PRIMARY KEY (dead_letter_id)
+);
+
+CREATE INDEX notifications_dead_letter_consumer_idx ON notifications_dead_letter (consumer);
+
+-- migrate:down
+
+DROP TABLE notifications_dead_letter;
@@ -0,0 +1,20 @@
+-- migrate:up
+
+CREATE TABLE notification_subscriptions (
+    subscription_id SERIAL,
+    url varchar(255) NOT NULL,
+    actions varchar(10)[] NOT NULL,
+    country_id INTEGER REFERENCES countries (country_id),
+    enabled boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    updated_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    PRIMARY KEY (subscription_id)
+);
+
+ALTER TABLE users_outbox ADD COLUMN country_id INTEGER;
+
+-- migrate:down
+
+ALTER TABLE users_outbox DROP COLUMN country_id;
+
+DROP TABLE notification_subscriptions;
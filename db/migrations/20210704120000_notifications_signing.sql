@@ -0,0 +1,20 @@
+-- migrate:up
+
+-- existing subscriptions get a random secret, it is expected to be rotated by admin
+ALTER TABLE notification_subscriptions
+    ADD COLUMN secret varchar(128) NOT NULL DEFAULT md5(random()::text || clock_timestamp()::text),
+    ADD COLUMN previous_secret varchar(128),
+    ADD COLUMN previous_secret_expires_at timestamp;
+
+ALTER TABLE notification_subscriptions ALTER COLUMN secret DROP DEFAULT;
+
+ALTER TABLE notifications_dead_letter ADD COLUMN event_id varchar(36) NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE notifications_dead_letter DROP COLUMN event_id;
+
+ALTER TABLE notification_subscriptions
+    DROP COLUMN secret,
+    DROP COLUMN previous_secret,
+    DROP COLUMN previous_secret_expires_at;
@@ -0,0 +1,31 @@
+-- migrate:up
+
+CREATE TABLE notification_queue (
+    queue_id BIGSERIAL,
+    event_id varchar(36) NOT NULL DEFAULT '',
+    ordering_key varchar(64) NOT NULL DEFAULT '',
+    message text NOT NULL,
+    consumers text[] NOT NULL,
+    enqueued_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    PRIMARY KEY (queue_id)
+);
+
+CREATE INDEX notification_queue_key_idx ON notification_queue (ordering_key, queue_id);
+
+-- workers of every replica are woken up on insert
+CREATE FUNCTION notification_queue_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('notification_queue', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_queue_notify_trigger
+    AFTER INSERT ON notification_queue
+    FOR EACH STATEMENT EXECUTE PROCEDURE notification_queue_notify();
+
+-- migrate:down
+
+DROP TRIGGER notification_queue_notify_trigger ON notification_queue;
+DROP FUNCTION notification_queue_notify();
+DROP TABLE notification_queue;
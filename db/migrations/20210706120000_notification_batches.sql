@@ -0,0 +1,14 @@
+-- migrate:up
+
+-- batching is disabled for existing subscriptions
+ALTER TABLE notification_subscriptions
+    ADD COLUMN batch_size INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN batch_window INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN batch_format varchar(10) NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE notification_subscriptions
+    DROP COLUMN batch_size,
+    DROP COLUMN batch_window,
+    DROP COLUMN batch_format;
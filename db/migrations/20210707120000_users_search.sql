@@ -0,0 +1,22 @@
+-- migrate:up
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- trigram indexes serve fuzzy (%) and substring (ILIKE) matches
+CREATE INDEX users_nick_name_trgm_idx ON users USING gin (nick_name gin_trgm_ops);
+CREATE INDEX users_first_name_trgm_idx ON users USING gin (first_name gin_trgm_ops);
+CREATE INDEX users_last_name_trgm_idx ON users USING gin (last_name gin_trgm_ops);
+CREATE INDEX users_email_trgm_idx ON users USING gin (email gin_trgm_ops);
+
+-- expression must be the same as in search query, otherwise index is not used
+CREATE INDEX users_search_idx ON users USING gin (
+    to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '') || ' ' || coalesce(nick_name, ''))
+);
+
+-- migrate:down
+
+DROP INDEX users_search_idx;
+DROP INDEX users_email_trgm_idx;
+DROP INDEX users_last_name_trgm_idx;
+DROP INDEX users_first_name_trgm_idx;
+DROP INDEX users_nick_name_trgm_idx;
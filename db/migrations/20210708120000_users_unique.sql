@@ -0,0 +1,11 @@
+-- migrate:up
+
+-- emails and nick names are unique regardless of case,
+-- migration fails, if there are duplicates already, they have to be resolved manually
+CREATE UNIQUE INDEX users_email_unique_idx ON users (lower(email));
+CREATE UNIQUE INDEX users_nick_name_unique_idx ON users (lower(nick_name));
+
+-- migrate:down
+
+DROP INDEX users_nick_name_unique_idx;
+DROP INDEX users_email_unique_idx;
@@ -0,0 +1,8 @@
+-- migrate:up
+
+-- version is incremented on every update, so concurrent updates of the same user are detected
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- migrate:down
+
+ALTER TABLE users DROP COLUMN version;
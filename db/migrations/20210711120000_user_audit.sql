@@ -0,0 +1,33 @@
+-- migrate:up
+
+-- user_audit is a history of every change of users, it is kept after user is purged, so there is no reference to users
+CREATE TABLE user_audit (
+    audit_id SERIAL,
+    user_id INTEGER NOT NULL,
+    action varchar(10) NOT NULL,
+    actor varchar(100) NOT NULL,
+    process_id varchar(36) NOT NULL,
+    source_ip varchar(45) NOT NULL,
+    old_value text,
+    new_value text,
+    created_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    PRIMARY KEY (audit_id)
+);
+
+CREATE INDEX user_audit_user_id_idx ON user_audit (user_id, audit_id);
+
+-- records could only be added
+CREATE FUNCTION user_audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'user_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_audit_append_only BEFORE UPDATE OR DELETE ON user_audit
+    FOR EACH ROW EXECUTE PROCEDURE user_audit_append_only();
+
+-- migrate:down
+
+DROP TRIGGER user_audit_append_only ON user_audit;
+DROP FUNCTION user_audit_append_only();
+DROP TABLE user_audit;
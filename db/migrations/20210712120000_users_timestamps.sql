@@ -0,0 +1,15 @@
+-- migrate:up
+
+-- existing users get time of migration, as their real time is unknown
+ALTER TABLE users ADD COLUMN created_at timestamp NOT NULL DEFAULT (now() at time zone 'utc');
+ALTER TABLE users ADD COLUMN updated_at timestamp NOT NULL DEFAULT (now() at time zone 'utc');
+
+-- incremental pulls select users updated since last pull
+CREATE INDEX users_updated_at_idx ON users (updated_at, user_id);
+
+-- migrate:down
+
+DROP INDEX users_updated_at_idx;
+
+ALTER TABLE users DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at;
@@ -0,0 +1,11 @@
+-- migrate:up
+
+-- message is leased to a worker until locked_until, so no transaction is held during delivery,
+-- claimed_by is a token of the claim, so worker releases only it's own lease
+ALTER TABLE notification_queue ADD COLUMN locked_until timestamp;
+ALTER TABLE notification_queue ADD COLUMN claimed_by varchar(36) NOT NULL DEFAULT '';
+
+-- migrate:down
+
+ALTER TABLE notification_queue DROP COLUMN claimed_by;
+ALTER TABLE notification_queue DROP COLUMN locked_until;
@@ -0,0 +1,11 @@
+-- migrate:up
+
+-- time of statement is used instead of time of transaction start, so users, created in a long transaction,
+-- get time close to commit, and incremental pulls with overlap window do not miss them
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT (clock_timestamp() at time zone 'utc');
+ALTER TABLE users ALTER COLUMN updated_at SET DEFAULT (clock_timestamp() at time zone 'utc');
+
+-- migrate:down
+
+ALTER TABLE users ALTER COLUMN updated_at SET DEFAULT (now() at time zone 'utc');
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT (now() at time zone 'utc');
@@ -0,0 +1,18 @@
+-- migrate:up
+
+-- emails and nick names are unique among active users only, so deleted user does not hold them until it is purged,
+-- deleted user can't be restored, while it's email or nick name is taken by an other user
+DROP INDEX users_nick_name_unique_idx;
+DROP INDEX users_email_unique_idx;
+
+CREATE UNIQUE INDEX users_email_unique_idx ON users (lower(email)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX users_nick_name_unique_idx ON users (lower(nick_name)) WHERE deleted_at IS NULL;
+
+-- migrate:down
+
+-- migration fails, if deleted users share email or nick name with active ones, they have to be resolved manually
+DROP INDEX users_nick_name_unique_idx;
+DROP INDEX users_email_unique_idx;
+
+CREATE UNIQUE INDEX users_email_unique_idx ON users (lower(email));
+CREATE UNIQUE INDEX users_nick_name_unique_idx ON users (lower(nick_name));
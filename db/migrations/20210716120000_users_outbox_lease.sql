@@ -0,0 +1,8 @@
+-- migrate:up
+
+-- pending record is leased to a relay until locked_until, so every record is relayed by one replica
+ALTER TABLE users_outbox ADD COLUMN locked_until timestamp;
+
+-- migrate:down
+
+ALTER TABLE users_outbox DROP COLUMN locked_until;
@@ -0,0 +1,15 @@
+-- migrate:up
+
+-- number of messages in notification_queue, it is updated by the same statements, that insert and delete messages,
+-- so capacity is checked without counting table, single row is enforced by primary key
+CREATE TABLE notification_queue_depth (
+    id boolean NOT NULL DEFAULT true CHECK (id),
+    depth bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
+
+INSERT INTO notification_queue_depth (depth) SELECT count(*) FROM notification_queue;
+
+-- migrate:down
+
+DROP TABLE notification_queue_depth;
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// audit actions, that are not notified, create, update and delete are the same as notification actions
+const (
+	AuditActionPassword = "PASSWORD"
+	AuditActionRestore  = "RESTORE"
+	AuditActionPurge    = "PURGE"
+)
+
+// Audit is a one change of user
+// Old and New are json snapshots of user before and after change, password is never a part of them,
+// Old is missing for created user, New is missing for deleted one, both are missing for password change
+type Audit struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"userId"`
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	ProcessID string          `json:"processId"`
+	SourceIP  string          `json:"sourceIp"`
+	Old       json.RawMessage `json:"old,omitempty"`
+	New       json.RawMessage `json:"new,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// AuditPage is a page of user's history response struct, the latest change is the first one
+// NextCursor is empty on the last page
+type AuditPage struct {
+	Records    []Audit `json:"records"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetter is a notification, that could not be delivered to a consumer after all retries
+// EventID is kept, so replayed notification has the same event id as the original one
+type DeadLetter struct {
+	ID        int             `json:"id"`
+	EventID   string          `json:"eventId"`
+	Consumer  string          `json:"consumer"`
+	LastError string          `json:"lastError"`
+	Attempts  int             `json:"attempts"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
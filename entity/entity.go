@@ -9,6 +9,12 @@ var (
 	ErrInvalidFilter    = errors.New("invalid filter")
 	ErrInvalidPassword  = errors.New("invalid password")
 	ErrUserExist        = errors.New("user exist")
+	ErrVersionConflict  = errors.New("version conflict")
 	ErrUserDoesNotExist = errors.New("user does not exist")
 	ErrUserIDIsMissing  = errors.New("user id is missing")
+	ErrIDIsMissing      = errors.New("id is missing")
+	ErrVersionIsMissing = errors.New("version is missing")
+	ErrDeliveryFailed   = errors.New("delivery failed")
+	ErrQueueFull        = errors.New("queue is full")
+	ErrQueueClosed      = errors.New("queue is closed")
 )
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// CloudEvents attributes of user notifications
+const (
+	CloudEventsSpecVersion = "1.0"
+	UserEventSource        = "/v1/user"
+	UserEventDataSchema    = "/schemas/user/v1.json"
+	UserChangeDataSchema   = "/schemas/user-change/v1.json"
+	UserEventContentType   = "application/json"
+)
+
+// user event types
+const (
+	EventTypeUserCreated = "user.created"
+	EventTypeUserUpdated = "user.updated"
+	EventTypeUserDeleted = "user.deleted"
+
+	// restored user is sent to consumers of ActionCreate, as it appears again
+	EventTypeUserRestored = "user.restored"
+	// purged user is sent to consumers of ActionDelete, it is the last event of user
+	EventTypeUserPurged = "user.purged"
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON format
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	Subject         string      `json:"subject,omitempty"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	DataSchema      string      `json:"dataschema,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+}
+
+// NewUserEvent creates a user event of action with id
+// subject of event is user's id, and data is a user itself
+func NewUserEvent(id, action string, user UserResponse) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          UserEventSource,
+		Type:            UserEventType(action),
+		Time:            time.Now().UTC(),
+		Subject:         strconv.Itoa(user.ID),
+		DataContentType: UserEventContentType,
+		DataSchema:      UserEventDataSchema,
+		Data:            user,
+	}
+}
+
+// NewUserChangeEvent creates a user.updated event with id
+// subject of event is user's id, and data is a change set of user
+func NewUserChangeEvent(id string, change UserChange) CloudEvent {
+	event := NewUserEvent(id, ActionUpdate, change.Current)
+	event.DataSchema = UserChangeDataSchema
+	event.Data = change
+
+	return event
+}
+
+// NewUserRestoreEvent creates a user.restored event with id
+// subject of event is user's id, and data is a restored user
+func NewUserRestoreEvent(id string, user UserResponse) CloudEvent {
+	event := NewUserEvent(id, ActionCreate, user)
+	event.Type = EventTypeUserRestored
+
+	return event
+}
+
+// NewUserPurgeEvent creates a user.purged event with id
+// subject of event is user's id, and data is a last snapshot of user
+func NewUserPurgeEvent(id string, user UserResponse) CloudEvent {
+	event := NewUserEvent(id, ActionDelete, user)
+	event.Type = EventTypeUserPurged
+
+	return event
+}
+
+// UserEventType returns event type of action
+func UserEventType(action string) string {
+	switch action {
+	case ActionCreate:
+		return EventTypeUserCreated
+	case ActionUpdate:
+		return EventTypeUserUpdated
+	case ActionDelete:
+		return EventTypeUserDeleted
+	default:
+		return ""
+	}
+}
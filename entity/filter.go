@@ -0,0 +1,198 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// user filter operators
+const (
+	FilterEq        = "eq"
+	FilterPrefix    = "prefix"
+	FilterIContains = "icontains"
+)
+
+// user timestamp fields, they are not a part of UserChange, but users could be sorted by them
+const (
+	FieldCreatedAt = "createdAt"
+	FieldUpdatedAt = "updatedAt"
+)
+
+// sortDescPrefix marks descending sort key
+const sortDescPrefix = "-"
+
+var (
+	// filterOperators holds operators, that are allowed for every filtered field
+	// country is filtered by ISO2 code, so only exact match makes sense for it
+	filterOperators = map[string][]string{
+		FieldFirstName: {FilterEq, FilterPrefix, FilterIContains},
+		FieldLastName:  {FilterEq, FilterPrefix, FilterIContains},
+		FieldNickName:  {FilterEq, FilterPrefix, FilterIContains},
+		FieldEmail:     {FilterEq, FilterPrefix, FilterIContains},
+		FieldCountry:   {FilterEq},
+	}
+
+	// sortFields holds fields users could be sorted by
+	sortFields = map[string]struct{}{
+		FieldFirstName: {},
+		FieldLastName:  {},
+		FieldNickName:  {},
+		FieldEmail:     {},
+		FieldCountry:   {},
+		FieldCreatedAt: {},
+		FieldUpdatedAt: {},
+	}
+)
+
+// Condition is a one condition of user filter
+type Condition struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Sort is a sort key of user listing
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// UserFilter is a user listing filter, all conditions are ANDed
+// users are ordered by sort keys and by id after them
+// UpdatedSince selects users, that were updated at that time or later, it is not set, if it is zero
+// deleted users, that are not purged yet, are selected as well, if IncludeDeleted is set
+type UserFilter struct {
+	Conditions     []Condition
+	Sort           []Sort
+	UpdatedSince   time.Time
+	IncludeDeleted bool
+}
+
+// FilterFields returns fields users could be filtered by
+func FilterFields() []string {
+	return []string{FieldFirstName, FieldLastName, FieldNickName, FieldEmail, FieldCountry}
+}
+
+// NewCondition creates filter condition, FilterEq is used, if op is empty
+func NewCondition(field, op, value string) (Condition, error) {
+	if op == "" {
+		op = FilterEq
+	}
+
+	ops, ok := filterOperators[field]
+	if !ok {
+		return Condition{}, fmt.Errorf("%w, users can't be filtered by %q", ErrValidationFailed, field)
+	}
+
+	if !contains(ops, op) {
+		return Condition{}, fmt.Errorf("%w, operator %q is not supported for %s", ErrValidationFailed, op, field)
+	}
+
+	if value == "" {
+		return Condition{}, fmt.Errorf("%w, %s filter must not be empty", ErrValidationFailed, field)
+	}
+
+	if field == FieldCountry {
+		if len(value) != 2 {
+			return Condition{}, fmt.Errorf("%w, country must be an ISO2 code", ErrValidationFailed)
+		}
+
+		value = strings.ToUpper(value)
+	}
+
+	return Condition{Field: field, Op: op, Value: value}, nil
+}
+
+// NewUpdatedSince parses RFC3339 time of updated since filter, zero time is returned, if s is empty
+func NewUpdatedSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w, updated since must be an RFC3339 time", ErrValidationFailed)
+	}
+
+	return t.UTC(), nil
+}
+
+// NewIncludeDeleted parses include deleted flag, false is returned, if s is empty
+func NewIncludeDeleted(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("%w, include deleted must be true or false", ErrValidationFailed)
+	}
+
+	return include, nil
+}
+
+// NewSort parses comma separated list of sort keys, key with "-" prefix is sorted in descending order
+func NewSort(s string) ([]Sort, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	keys := strings.Split(s, ",")
+	sort := make([]Sort, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+
+	for _, k := range keys {
+		key := Sort{Field: strings.TrimPrefix(k, sortDescPrefix), Desc: strings.HasPrefix(k, sortDescPrefix)}
+
+		if _, ok := sortFields[key.Field]; !ok {
+			return nil, fmt.Errorf("%w, users can't be sorted by %q", ErrValidationFailed, key.Field)
+		}
+
+		if _, ok := seen[key.Field]; ok {
+			return nil, fmt.Errorf("%w, duplicated sort key %s", ErrValidationFailed, key.Field)
+		}
+
+		seen[key.Field] = struct{}{}
+		sort = append(sort, key)
+	}
+
+	return sort, nil
+}
+
+// SortKeys returns values of user's sort key fields, they are stored in cursor of the next page
+func (u User) SortKeys(sort []Sort) []string {
+	keys := make([]string, 0, len(sort))
+
+	for _, s := range sort {
+		switch s.Field {
+		case FieldFirstName:
+			keys = append(keys, u.FirstName)
+		case FieldLastName:
+			keys = append(keys, u.LastName)
+		case FieldNickName:
+			keys = append(keys, u.NickName)
+		case FieldEmail:
+			keys = append(keys, u.Email)
+		case FieldCountry:
+			keys = append(keys, u.Country)
+		case FieldCreatedAt:
+			keys = append(keys, u.CreatedAt.UTC().Format(time.RFC3339Nano))
+		case FieldUpdatedAt:
+			keys = append(keys, u.UpdatedAt.UTC().Format(time.RFC3339Nano))
+		}
+	}
+
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
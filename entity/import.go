@@ -0,0 +1,51 @@
+package entity
+
+// import row statuses
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportRow is a one row of users import
+// Row is a row number in imported file, starting from 1, Err is set, if row could not be parsed
+type ImportRow struct {
+	Row  int
+	User UserRequest
+	Err  error
+}
+
+// ImportResult is a result of one imported row
+// ID is set for created user, Reason is set for skipped and failed ones
+type ImportResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport is a users import response struct
+type ImportReport struct {
+	Created int            `json:"created"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Rows    []ImportResult `json:"rows"`
+}
+
+// NewImportReport creates report from row results
+func NewImportReport(rows []ImportResult) ImportReport {
+	report := ImportReport{Rows: rows}
+
+	for _, r := range rows {
+		switch r.Status {
+		case ImportCreated:
+			report.Created++
+		case ImportSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+	}
+
+	return report
+}
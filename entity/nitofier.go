@@ -1,6 +1,25 @@
 package entity
 
+// notification actions
+const (
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+)
+
+// NotifierMessage is a message, that is queued to be sent to consumers
+// ID is an outbox event id, message would be marked as delivered by, once sent,
+// messages with the same Key are delivered in order they were queued, if queue is ordered
 type NotifierMessage struct {
+	ID        string
+	Key       string
 	Message   interface{}
 	Consumers []string
 }
+
+// BatchItem is one message of a batch
+// ID is an event id of message, consumer reports failed items by it
+type BatchItem struct {
+	ID      string
+	Message []byte
+}
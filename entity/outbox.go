@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Outbox is an outbox record definition struct
+// it is written in the same transaction with a change it describes
+// and is relayed to consumers until marked as delivered
+// CountryID is a country of changed user, it is 0 if unknown
+type Outbox struct {
+	ID        int
+	EventID   string
+	Action    string
+	CountryID int
+	Payload   []byte
+	CreatedAt time.Time
+}
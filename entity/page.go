@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// page sizes
+const (
+	PageLimitDefault = 50
+	PageLimitMax     = 500
+)
+
+// Page is a keyset page request
+// page holds up to Limit records, that follow the record with id After and sort key values Keys
+type Page struct {
+	After int
+	Keys  []string
+	Limit int
+}
+
+// cursor is a position of page, it is passed to clients base64 encoded, so they do not rely on it's content
+type cursor struct {
+	After int      `json:"after"`
+	Keys  []string `json:"keys,omitempty"`
+}
+
+// NewPage creates Page from limit and cursor, returned by previous page
+// first page is returned, if cursor is empty, default limit is used, if limit is 0
+func NewPage(limit int, c string) (Page, error) {
+	if limit == 0 {
+		limit = PageLimitDefault
+	}
+
+	if limit < 0 || limit > PageLimitMax {
+		return Page{}, fmt.Errorf("%w, limit must be between 1 and %d", ErrValidationFailed, PageLimitMax)
+	}
+
+	page := Page{Limit: limit}
+
+	if c == "" {
+		return page, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		return Page{}, fmt.Errorf("%w, invalid cursor", ErrValidationFailed)
+	}
+
+	var cur cursor
+
+	err = json.Unmarshal(data, &cur)
+	if err != nil || cur.After < 0 {
+		return Page{}, fmt.Errorf("%w, invalid cursor", ErrValidationFailed)
+	}
+
+	page.After = cur.After
+	page.Keys = cur.Keys
+
+	return page, nil
+}
+
+// Cursor returns cursor of page, that starts after record with id and sort key values keys
+func Cursor(id int, keys ...string) string {
+	data, _ := json.Marshal(cursor{After: id, Keys: keys})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// UserPage is a page of users response struct
+// NextCursor is empty on the last page
+type UserPage struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// QueueStats is a state of notification queue
+// Buffered is a number of messages waiting in queue, for shared backends it is a number of messages
+// in backend, including ones in delivery by other replicas. InFlight is a number of messages in delivery
+// by this instance. OldestAge is an age in seconds of the oldest undelivered message
+type QueueStats struct {
+	Backend       string          `json:"backend"`
+	Buffered      int             `json:"buffered"`
+	InFlight      int             `json:"inFlight"`
+	Capacity      int             `json:"capacity"`
+	OldestAge     float64         `json:"oldestAge"`
+	LastError     string          `json:"lastError,omitempty"`
+	LastErrorTime *time.Time      `json:"lastErrorTime,omitempty"`
+	Consumers     []ConsumerStats `json:"consumers"`
+}
+
+// ConsumerStats are notification counters of one consumer since service start
+// Enqueued is counted by queue, Delivered and Failed are counted by notifier
+type ConsumerStats struct {
+	Consumer      string     `json:"consumer"`
+	Enqueued      int64      `json:"enqueued"`
+	Delivered     int64      `json:"delivered"`
+	Failed        int64      `json:"failed"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// search limits
+const (
+	SearchLimitDefault = 20
+	SearchLimitMax     = 100
+
+	searchQueryMaxLength = 100
+)
+
+// UserSearch is a user search request
+type UserSearch struct {
+	Query string
+	Limit int
+}
+
+// NewUserSearch creates UserSearch from query and limit, default limit is used, if limit is 0
+func NewUserSearch(q string, limit int) (UserSearch, error) {
+	if limit == 0 {
+		limit = SearchLimitDefault
+	}
+
+	if limit < 0 || limit > SearchLimitMax {
+		return UserSearch{}, fmt.Errorf("%w, limit must be between 1 and %d", ErrValidationFailed, SearchLimitMax)
+	}
+
+	q = strings.TrimSpace(q)
+
+	if q == "" {
+		return UserSearch{}, fmt.Errorf("%w, search query must not be empty", ErrValidationFailed)
+	}
+
+	if utf8.RuneCountInString(q) > searchQueryMaxLength {
+		return UserSearch{}, fmt.Errorf("%w, search query must be at most %d charecters long", ErrValidationFailed, searchQueryMaxLength)
+	}
+
+	return UserSearch{Query: q, Limit: limit}, nil
+}
@@ -0,0 +1,179 @@
+package entity
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// minimal length of subscription's signing secret
+const subscriptionSecretMinLen = 16
+
+// batch limits
+const (
+	batchMaxSize   = 1000
+	batchMaxWindow = 60 * 1000
+)
+
+// batch delivery formats
+const (
+	BatchFormatJSON   = "json"
+	BatchFormatNDJSON = "ndjson"
+)
+
+// Batch is a batch delivery settings of subscription
+// messages are grouped up to Size items or Window milliseconds, whatever comes first,
+// and are sent as a JSON array or as a newline delimited JSON, if Format is BatchFormatNDJSON
+type Batch struct {
+	Size   int    `json:"size"`
+	Window int    `json:"window"`
+	Format string `json:"format,omitempty"`
+}
+
+// Enabled reports if messages are grouped into batches
+func (b Batch) Enabled() bool {
+	return b.Size > 1
+}
+
+// Validate validates batch settings
+func (b Batch) Validate() error {
+	if b.Size < 0 || b.Size > batchMaxSize {
+		return fmt.Errorf("%w, batch size must be between 0 and %d", ErrValidationFailed, batchMaxSize)
+	}
+
+	if b.Enabled() && (b.Window <= 0 || b.Window > batchMaxWindow) {
+		return fmt.Errorf("%w, batch window must be between 1 and %d milliseconds", ErrValidationFailed, batchMaxWindow)
+	}
+
+	switch b.Format {
+	case "", BatchFormatJSON, BatchFormatNDJSON:
+	default:
+		return fmt.Errorf("%w, unknown batch format %q", ErrValidationFailed, b.Format)
+	}
+
+	return nil
+}
+
+// Subscription is a consumer subscription to user change notifications
+// CountryID is an optional filter, if it is set, consumer is notified only about users from that country
+// deliveries are signed with Secret, and with PreviousSecret until it expires after rotation,
+// messages are delivered in batches, if Batch is enabled
+type Subscription struct {
+	ID                      int        `json:"id"`
+	URL                     string     `json:"url"`
+	Actions                 []string   `json:"actions"`
+	CountryID               int        `json:"country,omitempty"`
+	Enabled                 bool       `json:"enabled"`
+	Secret                  string     `json:"-"`
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previousSecretExpiresAt,omitempty"`
+	Batch                   *Batch     `json:"batch,omitempty"`
+	CreatedAt               time.Time  `json:"createdAt"`
+	UpdatedAt               time.Time  `json:"updatedAt"`
+}
+
+// SubscriptionSecretResponse is a subscription with it's current signing secret,
+// secret is returned only on create and on rotation, and is never exposed otherwise
+type SubscriptionSecretResponse struct {
+	Subscription
+	Secret string `json:"secret"`
+}
+
+// Secrets returns active signing secrets of subscription, current one goes first
+func (s Subscription) Secrets(now time.Time) []string {
+	secrets := make([]string, 0, 2)
+
+	if s.Secret != "" {
+		secrets = append(secrets, s.Secret)
+	}
+
+	if s.PreviousSecret != "" && s.PreviousSecretExpiresAt != nil && now.Before(*s.PreviousSecretExpiresAt) {
+		secrets = append(secrets, s.PreviousSecret)
+	}
+
+	return secrets
+}
+
+// Match reports if subscription is enabled and wants to be notified about action with user from countryID
+// countryID is 0, if user's country is unknown, such notification matches only subscriptions without country filter
+func (s Subscription) Match(action string, countryID int) bool {
+	if !s.Enabled {
+		return false
+	}
+
+	if s.CountryID != 0 && s.CountryID != countryID {
+		return false
+	}
+
+	for _, a := range s.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubscriptionRequest is a subscription request struct
+// subscription is enabled, if Enabled is missing
+// Secret is generated on create, and is kept on update, if it is missing,
+// messages are delivered one by one, if Batch is missing
+type SubscriptionRequest struct {
+	URL       string   `json:"url"`
+	Actions   []string `json:"actions"`
+	CountryID int      `json:"country,omitempty"`
+	Enabled   *bool    `json:"enabled,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	Batch     *Batch   `json:"batch,omitempty"`
+}
+
+// ToSubscription transformes SubscriptionRequest struct to Subscription struct
+func (sr SubscriptionRequest) ToSubscription() Subscription {
+	enabled := true
+	if sr.Enabled != nil {
+		enabled = *sr.Enabled
+	}
+
+	return Subscription{
+		URL:       sr.URL,
+		Actions:   sr.Actions,
+		CountryID: sr.CountryID,
+		Enabled:   enabled,
+		Secret:    sr.Secret,
+		Batch:     sr.Batch,
+	}
+}
+
+// Validate validates subscription request
+func (sr SubscriptionRequest) Validate() error {
+	u, err := url.Parse(sr.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w, url must be an absolute http(s) url", ErrValidationFailed)
+	}
+
+	if len(sr.Actions) == 0 {
+		return fmt.Errorf("%w, actions must not be empty", ErrValidationFailed)
+	}
+
+	for _, a := range sr.Actions {
+		switch a {
+		case ActionCreate, ActionUpdate, ActionDelete:
+		default:
+			return fmt.Errorf("%w, unknown action %q", ErrValidationFailed, a)
+		}
+	}
+
+	if sr.CountryID < 0 {
+		return fmt.Errorf("%w, country must not be negative", ErrValidationFailed)
+	}
+
+	if sr.Secret != "" && len(sr.Secret) < subscriptionSecretMinLen {
+		return fmt.Errorf("%w, secret must be at least %d charecters long", ErrValidationFailed, subscriptionSecretMinLen)
+	}
+
+	if sr.Batch != nil {
+		return sr.Batch.Validate()
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package entity
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 )
 
 // User is a user definition struct
@@ -16,6 +18,10 @@ type User struct {
 	Salt      string
 	Country   string
 	CountryID int
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
 }
 
 // ToResponse is transforming User struct to UserResponse struct
@@ -27,29 +33,108 @@ func (u User) ToResponse() UserResponse {
 		NickName:  u.NickName,
 		Email:     u.Email,
 		Country:   u.Country,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		DeletedAt: u.DeletedAt,
 	}
 }
 
 // UserResponse is a user response struct
 type UserResponse struct {
-	ID        int    `jspn:"id"`
-	FirstName string `jspn:"firstNmae"`
-	LastName  string `jspn:"lastName"`
-	NickName  string `jspn:"nickName"`
-	Email     string `jspn:"email"`
-	Country   string `jspn:"country"`
+	ID        int        `json:"id"`
+	FirstName string     `json:"firstName"`
+	LastName  string     `json:"lastName"`
+	NickName  string     `json:"nickName"`
+	Email     string     `json:"email"`
+	Country   string     `json:"country"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
-// UserNotification is a user notification struct
-type UserNotification struct {
-	User   UserResponse
-	Action string `json:"action"`
+// user fields, that are reported in UserChange
+const (
+	FieldFirstName = "firstName"
+	FieldLastName  = "lastName"
+	FieldNickName  = "nickName"
+	FieldEmail     = "email"
+	FieldCountry   = "country"
+)
+
+// UserExistError is returned, if value of user's Field is taken by other user
+type UserExistError struct {
+	Field string
+}
+
+// Error returns a field, that is taken
+func (e *UserExistError) Error() string {
+	return fmt.Sprintf("%s, %s is taken", ErrUserExist, e.Field)
+}
+
+// Unwrap returns ErrUserExist
+func (e *UserExistError) Unwrap() error {
+	return ErrUserExist
+}
+
+// VersionConflictError is returned, if user was changed by other request since Version was read
+type VersionConflictError struct {
+	ID      int
+	Version int
+}
+
+// Error returns user's id and outdated version
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s, user %d was changed since version %d", ErrVersionConflict, e.ID, e.Version)
+}
+
+// Unwrap returns ErrVersionConflict
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// ConflictResponse is a conflict response struct, Field is a user field, that caused conflict
+type ConflictResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"`
+}
+
+// UserChange is a change set of updated user
+type UserChange struct {
+	Previous      UserResponse `json:"previous"`
+	Current       UserResponse `json:"current"`
+	ChangedFields []string     `json:"changed_fields"`
+}
+
+// NewUserChange creates a change set of user from previous to current state
+func NewUserChange(previous, current UserResponse) UserChange {
+	changed := make([]string, 0)
+
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{FieldFirstName, previous.FirstName, current.FirstName},
+		{FieldLastName, previous.LastName, current.LastName},
+		{FieldNickName, previous.NickName, current.NickName},
+		{FieldEmail, previous.Email, current.Email},
+		{FieldCountry, previous.Country, current.Country},
+	} {
+		if f.old != f.new {
+			changed = append(changed, f.name)
+		}
+	}
+
+	return UserChange{
+		Previous:      previous,
+		Current:       current,
+		ChangedFields: changed,
+	}
 }
 
 // UserRequest is a user request struct
 type UserRequest struct {
 	ID        int    `json:"id,omitempty"`
-	FirstName string `json:"firstNmae"`
+	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 	NickName  string `json:"nickName"`
 	Email     string `json:"email"`
@@ -57,6 +142,28 @@ type UserRequest struct {
 	CountryID int    `json:"country"`
 }
 
+// UnmarshalJSON decodes user request, misspelled firstNmae is still accepted for old clients, if firstName is not set
+func (ur *UserRequest) UnmarshalJSON(b []byte) error {
+	type request UserRequest
+
+	var r struct {
+		request
+		LegacyFirstName string `json:"firstNmae"`
+	}
+
+	err := json.Unmarshal(b, &r)
+	if err != nil {
+		return err
+	}
+
+	*ur = UserRequest(r.request)
+	if ur.FirstName == "" {
+		ur.FirstName = r.LegacyFirstName
+	}
+
+	return nil
+}
+
 // ToUser transformes UserRequest struct to User struct
 func (ur UserRequest) ToUser() User {
 	return User{
@@ -83,15 +190,24 @@ func (u UserRequest) Validate() error {
 		return fmt.Errorf("%w, email name must not be empty", ErrValidationFailed)
 	}
 
+	if u.CountryID < 1 {
+		return fmt.Errorf("%w, country must be set", ErrValidationFailed)
+	}
+
 	if len(u.Password) < 7 {
 		return fmt.Errorf("%w, password must be at least 7 charecters long", ErrValidationFailed)
 	}
 
+	return validateEmail(u.Email)
+}
+
+// validateEmail validates email format
+func validateEmail(email string) error {
 	pattern := "^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]" +
 		"{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$"
 
 	regexp, err := regexp.Compile(pattern)
-	if err != nil || !regexp.MatchString(u.Email) {
+	if err != nil || !regexp.MatchString(email) {
 		return fmt.Errorf("invalid email format, %w", ErrValidationFailed)
 	}
 
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// user patch members, that are not user fields
+const (
+	patchMemberPassword = "password"
+	patchMemberID       = "id"
+)
+
+// jsonNull is a merge patch value, that removes member
+var jsonNull = []byte("null")
+
+// UserPatch is a JSON merge patch (RFC 7396) of user, nil fields are not changed
+// Password is not patched, it is user's current password, that authorizes patch, like in UserRequest
+// ID and Version are not a part of patch document, they are set by request's path and If-Match header
+type UserPatch struct {
+	ID        int
+	Version   int
+	Password  string
+	FirstName *string
+	LastName  *string
+	NickName  *string
+	Email     *string
+	CountryID *int
+}
+
+// NewUserPatch parses merge patch document, fields are named as in UserResponse, country is set by it's id
+// user fields are required, so they can't be removed with null, read-only and unknown members are rejected
+func NewUserPatch(doc []byte) (UserPatch, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(doc, &members)
+	if err != nil || members == nil {
+		return UserPatch{}, fmt.Errorf("%w, patch must be a JSON object", ErrValidationFailed)
+	}
+
+	var patch UserPatch
+
+	for name, value := range members {
+		var v interface{}
+
+		switch name {
+		case patchMemberPassword:
+			v = &patch.Password
+		case FieldFirstName:
+			v = &patch.FirstName
+		case FieldLastName:
+			v = &patch.LastName
+		case FieldNickName:
+			v = &patch.NickName
+		case FieldEmail:
+			v = &patch.Email
+		case FieldCountry:
+			v = &patch.CountryID
+		case patchMemberID, FieldCreatedAt, FieldUpdatedAt:
+			return UserPatch{}, fmt.Errorf("%w, %s can't be changed", ErrValidationFailed, name)
+		default:
+			return UserPatch{}, fmt.Errorf("%w, user has no %q field", ErrValidationFailed, name)
+		}
+
+		if bytes.Equal(bytes.TrimSpace(value), jsonNull) {
+			return UserPatch{}, fmt.Errorf("%w, %s can't be removed", ErrValidationFailed, name)
+		}
+
+		err = json.Unmarshal(value, v)
+		if err != nil {
+			return UserPatch{}, fmt.Errorf("%w, invalid %s value", ErrValidationFailed, name)
+		}
+	}
+
+	return patch, nil
+}
+
+// Empty checks, if patch does not change any field
+func (p UserPatch) Empty() bool {
+	return p.FirstName == nil && p.LastName == nil && p.NickName == nil && p.Email == nil && p.CountryID == nil
+}
+
+// Validate validates fields, that are set by patch
+func (p UserPatch) Validate() error {
+	for _, f := range []struct {
+		value *string
+		name  string
+	}{
+		{p.FirstName, "first name"},
+		{p.LastName, "last name"},
+		{p.NickName, "nick name"},
+		{p.Email, "email"},
+	} {
+		if f.value != nil && *f.value == "" {
+			return fmt.Errorf("%w, %s must not be empty", ErrValidationFailed, f.name)
+		}
+	}
+
+	if p.Email != nil {
+		return validateEmail(*p.Email)
+	}
+
+	return nil
+}
+
+// Apply returns user with patched fields, country name is not changed, it is set by country id
+func (p UserPatch) Apply(u User) User {
+	if p.FirstName != nil {
+		u.FirstName = *p.FirstName
+	}
+	if p.LastName != nil {
+		u.LastName = *p.LastName
+	}
+	if p.NickName != nil {
+		u.NickName = *p.NickName
+	}
+	if p.Email != nil {
+		u.Email = *p.Email
+	}
+	if p.CountryID != nil {
+		u.CountryID = *p.CountryID
+	}
+
+	return u
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,16 +13,27 @@ import (
 	"github.com/faceit/test/config"
 	"github.com/faceit/test/logger"
 	"github.com/faceit/test/notifier"
+	"github.com/faceit/test/notifier/webhook"
+	"github.com/faceit/test/outbox"
+	"github.com/faceit/test/purger"
 	"github.com/faceit/test/queue"
+	"github.com/faceit/test/services/audit"
 	"github.com/faceit/test/services/country"
+	"github.com/faceit/test/services/deadletter"
 	"github.com/faceit/test/services/hasher"
 	"github.com/faceit/test/services/health"
 	"github.com/faceit/test/services/password"
+	"github.com/faceit/test/services/queuestats"
+	"github.com/faceit/test/services/subscription"
 	"github.com/faceit/test/services/user"
+	"github.com/faceit/test/services/userimport"
 	"github.com/faceit/test/store"
 	countryhandler "github.com/faceit/test/web/country"
+	deadletterhandler "github.com/faceit/test/web/deadletter"
 	healthhandler "github.com/faceit/test/web/health"
 	"github.com/faceit/test/web/middleware"
+	queuehandler "github.com/faceit/test/web/queue"
+	subscriptionhandler "github.com/faceit/test/web/subscription"
 	userhandler "github.com/faceit/test/web/user"
 
 	"github.com/gorilla/handlers"
@@ -31,7 +41,7 @@ import (
 
 	glog "github.com/google/logger"
 
-	_ "github.com/lib/pq" // postgres driver import
+	"github.com/lib/pq" // postgres driver import
 )
 
 func main() {
@@ -80,53 +90,98 @@ func loadService(ctx context.Context) error {
 	userStore := store.NewUser(postgresClient, &sql.TxOptions{Isolation: sql.LevelDefault})
 	passwordStore := store.NewPassword(postgresClient)
 	countryStore := store.NewCountry(postgresClient)
+	outboxStore := store.NewOutbox(postgresClient)
+	deadLetterStore := store.NewDeadLetter(postgresClient)
+	subscriptionStore := store.NewSubscription(postgresClient)
+	auditStore := store.NewAudit(postgresClient)
 
 	hasher := hasher.New()
 	password := password.New(passwordStore, hasher)
-	user := user.New(userStore, hasher, password)
+	user := user.New(userStore, hasher, password, countryStore)
 	country := country.New(countryStore)
-	health := health.New(postgresClient, log)
+	imports := userimport.New(cfg.Import(), userStore, hasher, log)
+	audit := audit.New(auditStore)
 
-	notifier := initNotifier(cfg.Notifier(), log)
-	queue := queue.New(cfg.Queue(), notifier)
+	// subscriptions are reloaded periodically, so changes made through other nodes are picked up
+	subscription := subscription.New(subscriptionStore, cfg.Notifier(), log)
+
+	err = subscription.Refresh(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load subscriptions, error: %w", err)
+	}
+
+	go subscription.Run(ctx)
+
+	notifier := initNotifier(cfg.Notifier(), subscription, deadLetterStore, log)
+
+	// notifier drops state of consumers, that are unsubscribed
+	subscription.OnRefresh(notifier.Retain)
+	deadLetter := deadletter.New(deadLetterStore, notifier)
+
+	queue, err := initQueue(ctx, cfg.Queue(), cfg.DB(), postgresClient, notifier, outboxStore, log)
+	if err != nil {
+		return err
+	}
+
+	// queue drops counters of consumers, that are unsubscribed
+	subscription.OnRefresh(queue.Retain)
+
+	queueStats := queuestats.New(cfg.Queue(), queue, notifier)
+	health := health.New(postgresClient, log, notifier, queueStats)
+
+	// relay is redelivering notifications, that were not delivered by queue (ak service was stopped)
+	relay := outbox.New(cfg.Outbox(), outboxStore, notifier, subscription, log)
+	go relay.Run(ctx)
+
+	// purger removes deleted users after retention period, relay sends their final notifications
+	purger := purger.New(cfg.Purge(), userStore, log)
+	go purger.Run(ctx)
 
 	router := mux.NewRouter().StrictSlash(true)
-	middleware := middleware.New(log)
+	middleware := middleware.New(log, cfg.Service().TrustedProxies)
 
-	userhandler.NewHandler(router, log, middleware, user, country, password, hasher, *queue)
+	userhandler.NewHandler(router, log, middleware, subscription, user, country, password, hasher, queue, imports, cfg.Import(), audit)
 	countryhandler.NewHandler(router, log, middleware, country)
 	healthhandler.NewHandler(router, log, middleware, health)
+	deadletterhandler.NewHandler(router, log, middleware, deadLetter)
+	subscriptionhandler.NewHandler(router, log, middleware, subscription)
+	queuehandler.NewHandler(router, log, middleware, queueStats)
 
 	server := &http.Server{
 		Addr:    cfg.Service().Port,
 		Handler: handlers.CORS()(router),
 	}
 
-	errCh := make(chan error)
+	errCh := make(chan error, 1)
 
 	go startServer(ctx, log, server, errCh)
 
 	select {
 	case <-ctx.Done():
 		log.Infof(context.Background(), "stopping service")
+	case err = <-errCh:
+		log.Errorf(ctx, "service has been stopped with error: %s", err.Error())
+	}
 
-		err = server.Shutdown(ctx)
-		if errors.Is(err, context.Canceled) {
-			return nil
-		}
+	// service context is already cancelled, so shutdown gets it's own deadline
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(),
+		time.Duration(cfg.Queue().ShutdownTimeout)*time.Second)
+	defer shutdownCancel()
+
+	if err == nil {
+		err = server.Shutdown(shutdownCtx)
 		if err != nil {
-			return err
+			log.Errorf(shutdownCtx, "failed to stop HTTP server, error: %s", err)
 		}
-
-		queue.Closed()
-	case err = <-errCh:
-		log.Errorf(ctx, "service has been stopped with error: %s", err.Error())
-		return err
 	}
 
-	queue.Closed()
+	// queue is stopped after server, so no more messages are added
+	abandoned, er := queue.Shutdown(shutdownCtx)
+	if er != nil {
+		log.Warningf(shutdownCtx, "queue has been stopped with %d undelivered message(s), error: %s", abandoned, er)
+	}
 
-	return nil
+	return err
 }
 
 func initLog(cfg config.Logger) (logger.Logger, error) {
@@ -141,7 +196,7 @@ func initLog(cfg config.Logger) (logger.Logger, error) {
 }
 
 func initDBClient(cfg config.DB) (*sql.DB, error) {
-	url := fmt.Sprintf(cfg.PatternURL, cfg.Host, cfg.Port, cfg.UserName, cfg.Password, cfg.Name, cfg.SSLMode)
+	url := dbURL(cfg)
 	log.Printf("connecting to %s", url)
 	db, err := sql.Open(cfg.UserName, url)
 	if err != nil {
@@ -156,14 +211,55 @@ func initDBClient(cfg config.DB) (*sql.DB, error) {
 	return db, nil
 }
 
-func initNotifier(cfg config.Notifier, l logger.Logger) *notifier.Notifier {
+// dbURL returns database connection string
+func dbURL(cfg config.DB) string {
+	return fmt.Sprintf(cfg.PatternURL, cfg.Host, cfg.Port, cfg.UserName, cfg.Password, cfg.Name, cfg.SSLMode)
+}
+
+// initQueue creates queue backend, configured by cfg
+// postgres backend is woken up by notifications from listener, that is closed, once ctx is done
+func initQueue(ctx context.Context, cfg config.Queue, dbCfg config.DB, db *sql.DB,
+	n *notifier.Notifier, o *store.Outbox, l logger.Logger) (queue.Backend, error) {
+	switch cfg.Backend {
+	case config.QueueBackendMemory:
+		return queue.New(cfg, n, o)
+	case config.QueueBackendSpool:
+		return queue.NewSpool(cfg, n, o)
+	case config.QueueBackendPostgres:
+		listener := pq.NewListener(dbURL(dbCfg), time.Second, time.Minute,
+			func(event pq.ListenerEventType, err error) {
+				if err != nil {
+					l.Warningf(ctx, "queue listener event %d, error: %s", event, err)
+				}
+			})
+
+		err := listener.Listen(store.QueueChannel)
+		if err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("failed to listen queue notifications, error: %w", err)
+		}
+
+		go func() {
+			<-ctx.Done()
+			_ = listener.Close()
+		}()
+
+		return queue.NewPostgres(cfg, store.NewQueue(db), listener.Notify, n, o), nil
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q", cfg.Backend)
+	}
+}
+
+// initNotifier creates webhook notifier, deliveries are signed with secrets of subscriptions from s
+// and are grouped into batches by their batch settings
+func initNotifier(cfg config.Notifier, s *subscription.Subscription, d *store.DeadLetter, l logger.Logger) *notifier.Notifier {
 	var consumers []string
 
 	consumers = append(consumers, cfg.OnCreate()...)
 	consumers = append(consumers, cfg.OnUpdate()...)
 	consumers = append(consumers, cfg.OnDelete()...)
 
-	return notifier.New(cfg, nil, consumers, l)
+	return notifier.New(cfg, webhook.New(&http.Client{}, s, cfg.ContentMode == config.ContentModeBinary), consumers, d, s, l)
 }
 
 func startServer(ctx context.Context, l logger.Logger, server *http.Server, errCh chan<- error) {
@@ -0,0 +1,170 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+
+	"github.com/google/uuid"
+)
+
+// batchSender sends batch of items to consumer
+type batchSender func(ctx context.Context, consumer, format string, items []entity.BatchItem) error
+
+// failedItems is an error, that reports which items of a batch were not delivered
+type failedItems interface {
+	error
+	FailedItems() []string
+}
+
+// pending is a batch item, that is waiting for batch to be sent
+type pending struct {
+	item   entity.BatchItem
+	result chan error
+}
+
+// batcher groups messages to one consumer into batches
+// batch is sent, once it has size items, or window is passed since the first item was added
+type batcher struct {
+	consumer string
+	batch    entity.Batch
+	timeout  time.Duration
+	send     batchSender
+	mu       *sync.Mutex
+	pending  []pending
+	timer    *time.Timer
+}
+
+// newBatcher creates new batcher, every batch is sent with timeout
+func newBatcher(consumer string, b entity.Batch, timeout time.Duration, s batchSender) *batcher {
+	return &batcher{
+		consumer: consumer,
+		batch:    b,
+		timeout:  timeout,
+		send:     s,
+		mu:       &sync.Mutex{},
+	}
+}
+
+// add adds item to the current batch and waits until the batch is sent
+// error is returned, if item was not delivered, or ctx is done before item is taken into a batch,
+// item, that is taken already, is waited for, as it is sent regardless of ctx
+func (b *batcher) add(ctx context.Context, item entity.BatchItem) error {
+	p := pending{item: item, result: make(chan error, 1)}
+
+	b.mu.Lock()
+
+	b.pending = append(b.pending, p)
+
+	switch {
+	case len(b.pending) >= b.batch.Size:
+		batch := b.take()
+		b.mu.Unlock()
+
+		go b.flush(batch)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(time.Duration(b.batch.Window)*time.Millisecond, b.expire)
+		b.mu.Unlock()
+	default:
+		b.mu.Unlock()
+	}
+
+	select {
+	case err := <-p.result:
+		return err
+	case <-ctx.Done():
+		if b.remove(p) {
+			return ctx.Err()
+		}
+
+		return <-p.result
+	}
+}
+
+// remove removes item from the current batch, false is returned, if item is taken into a sent batch already
+func (b *batcher) remove(p pending) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for n := range b.pending {
+		if b.pending[n].result != p.result {
+			continue
+		}
+
+		b.pending = append(b.pending[:n], b.pending[n+1:]...)
+
+		// empty batch has no window
+		if len(b.pending) == 0 {
+			b.take()
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// expire sends the current batch, once it's window is passed
+func (b *batcher) expire() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
+
+// take returns pending items and starts a new batch, b.mu must be locked
+func (b *batcher) take() []pending {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	batch := b.pending
+	b.pending = nil
+
+	return batch
+}
+
+// flush sends batch and passes result to every item
+// only items, reported as failed, get an error, so only they are retried
+func (b *batcher) flush(batch []pending) {
+	// batch has it's own event id, items are identified by their ids in body
+	ctx, cancel := context.WithTimeout(cont.SetEventID(context.Background(), uuid.New().String()), b.timeout)
+	defer cancel()
+
+	items := make([]entity.BatchItem, 0, len(batch))
+	for _, p := range batch {
+		items = append(items, p.item)
+	}
+
+	err := b.send(ctx, b.consumer, b.batch.Format, items)
+
+	failed := make(map[string]struct{})
+
+	var f failedItems
+	if errors.As(err, &f) {
+		for _, id := range f.FailedItems() {
+			failed[id] = struct{}{}
+		}
+	}
+
+	for _, p := range batch {
+		_, itemFailed := failed[p.item.ID]
+
+		switch {
+		case err == nil:
+			p.result <- nil
+		case len(failed) == 0 || itemFailed:
+			p.result <- err
+		default:
+			p.result <- nil
+		}
+	}
+}
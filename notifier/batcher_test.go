@@ -0,0 +1,143 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/notifier/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSender records sent batches and returns err for every batch
+type testSender struct {
+	mu      *sync.Mutex
+	batches [][]entity.BatchItem
+	err     error
+}
+
+func (s *testSender) send(ctx context.Context, consumer, format string, items []entity.BatchItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// batch has it's own event id
+	if cont.EventID(ctx) == "" {
+		return errTest
+	}
+
+	s.batches = append(s.batches, items)
+
+	return s.err
+}
+
+func TestBatcher(t *testing.T) {
+	t.Run("positive_flush_by_size", func(t *testing.T) {
+		s := &testSender{mu: &sync.Mutex{}}
+		b := newBatcher(testConsumers[0], entity.Batch{Size: 3, Window: 60 * 1000}, time.Second, s.send)
+
+		wg := &sync.WaitGroup{}
+
+		for _, id := range []string{"1", "2", "3"} {
+			wg.Add(1)
+
+			go func(id string) {
+				defer wg.Done()
+
+				assert.Nil(t, b.add(context.Background(), entity.BatchItem{ID: id, Message: testMessageByte}))
+			}(id)
+		}
+
+		wg.Wait()
+
+		assert.Len(t, s.batches, 1)
+		assert.Len(t, s.batches[0], 3)
+	})
+
+	t.Run("positive_flush_by_window", func(t *testing.T) {
+		s := &testSender{mu: &sync.Mutex{}}
+		b := newBatcher(testConsumers[0], entity.Batch{Size: 10, Window: 10}, time.Second, s.send)
+
+		start := time.Now()
+
+		assert.Nil(t, b.add(context.Background(), entity.BatchItem{ID: "1", Message: testMessageByte}))
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(10*time.Millisecond))
+
+		assert.Equal(t, [][]entity.BatchItem{{{ID: "1", Message: testMessageByte}}}, s.batches)
+	})
+
+	t.Run("negative_failed_items_only", func(t *testing.T) {
+		batchErr := &webhook.BatchError{Consumer: testConsumers[0], Items: []string{"2"}, Err: webhook.ErrItemsRejected}
+
+		s := &testSender{mu: &sync.Mutex{}, err: batchErr}
+		b := newBatcher(testConsumers[0], entity.Batch{Size: 2, Window: 60 * 1000}, time.Second, s.send)
+
+		results := make(map[string]error)
+		mu := &sync.Mutex{}
+		wg := &sync.WaitGroup{}
+
+		for _, id := range []string{"1", "2"} {
+			wg.Add(1)
+
+			go func(id string) {
+				defer wg.Done()
+
+				err := b.add(context.Background(), entity.BatchItem{ID: id, Message: testMessageByte})
+
+				mu.Lock()
+				results[id] = err
+				mu.Unlock()
+			}(id)
+		}
+
+		wg.Wait()
+
+		assert.Nil(t, results["1"])
+		assert.Equal(t, batchErr, results["2"])
+	})
+
+	t.Run("negative_context_done", func(t *testing.T) {
+		s := &testSender{mu: &sync.Mutex{}}
+		b := newBatcher(testConsumers[0], entity.Batch{Size: 10, Window: 60 * 1000}, time.Second, s.send)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.add(ctx, entity.BatchItem{ID: "1", Message: testMessageByte}), context.DeadlineExceeded)
+
+		// item is removed, so it is not sent with the next batch
+		assert.Empty(t, b.pending)
+		assert.Nil(t, b.timer)
+
+		b.expire()
+		assert.Empty(t, s.batches)
+	})
+
+	t.Run("positive_context_done_after_take", func(t *testing.T) {
+		sending, release := make(chan struct{}), make(chan struct{})
+
+		send := func(ctx context.Context, consumer, format string, items []entity.BatchItem) error {
+			close(sending)
+			<-release
+
+			return nil
+		}
+
+		b := newBatcher(testConsumers[0], entity.Batch{Size: 1, Window: 60 * 1000}, time.Second, send)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go func() {
+			<-sending
+			cancel()
+
+			// batch is still in delivery, when ctx is done
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+		}()
+
+		assert.Nil(t, b.add(ctx, entity.BatchItem{ID: "1", Message: testMessageByte}))
+	})
+}
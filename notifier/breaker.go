@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// package errors
+var (
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// breaker is a consumer's circuit breaker
+// it opens after threshold consecutive failures and rejects deliveries for timeout,
+// after that one probe delivery is allowed (half-open), which closes or opens breaker again
+type breaker struct {
+	mu        *sync.Mutex
+	state     string
+	failures  int
+	threshold int
+	timeout   time.Duration
+	openedAt  time.Time
+	probing   bool
+	lastErr   error
+	now       func() time.Time
+}
+
+func newBreaker(threshold int, timeout time.Duration) *breaker {
+	return &breaker{
+		mu:        &sync.Mutex{},
+		state:     StateClosed,
+		threshold: threshold,
+		timeout:   timeout,
+		now:       time.Now,
+	}
+}
+
+// allow reports if delivery to consumer can be made
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.timeout {
+			return false
+		}
+
+		b.state = StateHalfOpen
+		b.probing = true
+
+		return true
+	case StateHalfOpen:
+		// only one probe delivery at a time
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// success closes breaker
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+	b.lastErr = nil
+}
+
+// failure counts failed delivery, and opens breaker if threshold is reached or probe failed
+func (b *breaker) failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastErr = err
+	b.probing = false
+
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// status returns breaker state and it's description
+func (b *breaker) status() (string, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	message := fmt.Sprintf("state: %s, consecutive failures: %d", b.state, b.failures)
+	if b.lastErr != nil {
+		message = fmt.Sprintf("%s, last error: %s", message, b.lastErr)
+	}
+
+	return b.state, message
+}
+
+// backoff returns a random delay in [0, min(max, base * 2^attempt)) ("full jitter")
+// delay is not limited, if max is not positive
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	d := base
+	for i := 0; i < attempt && (max <= 0 || d < max) && d <= math.MaxInt64/2; i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		d = max
+	}
+
+	// jitter is not security sensitive
+	return time.Duration(rand.Int63n(int64(d))) // nolint:gosec
+}
@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Run("positive_open_after_threshold", func(t *testing.T) {
+		b := newBreaker(2, time.Minute)
+
+		assert.True(t, b.allow())
+		b.failure(errTest)
+
+		state, _ := b.status()
+		assert.Equal(t, StateClosed, state)
+		assert.True(t, b.allow())
+
+		b.failure(errTest)
+
+		state, message := b.status()
+		assert.Equal(t, StateOpen, state)
+		assert.Contains(t, message, errTest.Error())
+		assert.False(t, b.allow())
+	})
+
+	t.Run("positive_success_resets_failures", func(t *testing.T) {
+		b := newBreaker(2, time.Minute)
+
+		b.failure(errTest)
+		b.success()
+		b.failure(errTest)
+
+		state, _ := b.status()
+		assert.Equal(t, StateClosed, state)
+	})
+
+	t.Run("positive_half_open_probe_closes", func(t *testing.T) {
+		now := time.Now()
+
+		b := newBreaker(1, time.Minute)
+		b.now = func() time.Time { return now }
+
+		b.failure(errTest)
+		assert.False(t, b.allow())
+
+		now = now.Add(time.Minute)
+
+		// only one probe is allowed
+		assert.True(t, b.allow())
+		assert.False(t, b.allow())
+
+		state, _ := b.status()
+		assert.Equal(t, StateHalfOpen, state)
+
+		b.success()
+
+		state, _ = b.status()
+		assert.Equal(t, StateClosed, state)
+		assert.True(t, b.allow())
+	})
+
+	t.Run("negative_half_open_probe_fails", func(t *testing.T) {
+		now := time.Now()
+
+		b := newBreaker(3, time.Minute)
+		b.now = func() time.Time { return now }
+
+		b.failure(errTest)
+		b.failure(errTest)
+		b.failure(errTest)
+
+		now = now.Add(time.Minute)
+
+		assert.True(t, b.allow())
+		b.failure(errTest)
+
+		state, _ := b.status()
+		assert.Equal(t, StateOpen, state)
+		assert.False(t, b.allow())
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("positive_bounds", func(t *testing.T) {
+		for attempt := 0; attempt < 100; attempt++ {
+			d := backoff(time.Second, 30*time.Second, attempt)
+			assert.True(t, d >= 0)
+			assert.True(t, d < 30*time.Second)
+
+			if attempt < 4 {
+				assert.True(t, d < time.Second<<uint(attempt))
+			}
+		}
+	})
+
+	t.Run("positive_no_max", func(t *testing.T) {
+		d := backoff(time.Second, 0, 100)
+		assert.True(t, d >= 0)
+	})
+
+	t.Run("positive_no_base", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), backoff(0, time.Second, 3))
+	})
+}
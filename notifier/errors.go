@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// consumerErrors are errors of delivery to consumers, nil error means message is delivered
+type consumerErrors map[string]error
+
+// Error returns a list of failed consumers with their errors
+func (e consumerErrors) Error() string {
+	failed := make([]string, 0, len(e))
+
+	for _, c := range e.Failed() {
+		failed = append(failed, fmt.Sprintf("%s: %s", c, e[c]))
+	}
+
+	return fmt.Sprintf("failed to deliver message to %d consumer(s): %s", len(failed), strings.Join(failed, "; "))
+}
+
+// Failed returns sorted list of consumers, delivery to which has failed
+func (e consumerErrors) Failed() []string {
+	failed := make([]string, 0, len(e))
+
+	for c, err := range e {
+		if err != nil {
+			failed = append(failed, c)
+		}
+	}
+
+	sort.Strings(failed)
+
+	return failed
+}
+
+// Err returns an error of delivery to consumer, or nil if it succeeded
+func (e consumerErrors) Err(consumer string) error {
+	return e[consumer]
+}
@@ -6,6 +6,7 @@ package mock_notifier
 
 import (
 	context "context"
+	entity "github.com/faceit/test/entity"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -46,3 +47,157 @@ func (mr *MocknotifierMockRecorder) Send(ctx, consumer, message interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*Mocknotifier)(nil).Send), ctx, consumer, message)
 }
+
+// SendBatch mocks base method
+func (m *Mocknotifier) SendBatch(ctx context.Context, consumer, format string, items []entity.BatchItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, consumer, format, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch
+func (mr *MocknotifierMockRecorder) SendBatch(ctx, consumer, format, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*Mocknotifier)(nil).SendBatch), ctx, consumer, format, items)
+}
+
+// Mockbatches is a mock of batches interface
+type Mockbatches struct {
+	ctrl     *gomock.Controller
+	recorder *MockbatchesMockRecorder
+}
+
+// MockbatchesMockRecorder is the mock recorder for Mockbatches
+type MockbatchesMockRecorder struct {
+	mock *Mockbatches
+}
+
+// NewMockbatches creates a new mock instance
+func NewMockbatches(ctrl *gomock.Controller) *Mockbatches {
+	mock := &Mockbatches{ctrl: ctrl}
+	mock.recorder = &MockbatchesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Mockbatches) EXPECT() *MockbatchesMockRecorder {
+	return m.recorder
+}
+
+// Batch mocks base method
+func (m *Mockbatches) Batch(consumer string) entity.Batch {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", consumer)
+	ret0, _ := ret[0].(entity.Batch)
+	return ret0
+}
+
+// Batch indicates an expected call of Batch
+func (mr *MockbatchesMockRecorder) Batch(consumer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*Mockbatches)(nil).Batch), consumer)
+}
+
+// MockfailedConsumers is a mock of failedConsumers interface
+type MockfailedConsumers struct {
+	ctrl     *gomock.Controller
+	recorder *MockfailedConsumersMockRecorder
+}
+
+// MockfailedConsumersMockRecorder is the mock recorder for MockfailedConsumers
+type MockfailedConsumersMockRecorder struct {
+	mock *MockfailedConsumers
+}
+
+// NewMockfailedConsumers creates a new mock instance
+func NewMockfailedConsumers(ctrl *gomock.Controller) *MockfailedConsumers {
+	mock := &MockfailedConsumers{ctrl: ctrl}
+	mock.recorder = &MockfailedConsumersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockfailedConsumers) EXPECT() *MockfailedConsumersMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method
+func (m *MockfailedConsumers) Err(consumer string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err", consumer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err
+func (mr *MockfailedConsumersMockRecorder) Err(consumer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockfailedConsumers)(nil).Err), consumer)
+}
+
+// Error mocks base method
+func (m *MockfailedConsumers) Error() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Error indicates an expected call of Error
+func (mr *MockfailedConsumersMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockfailedConsumers)(nil).Error))
+}
+
+// Failed mocks base method
+func (m *MockfailedConsumers) Failed() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Failed")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Failed indicates an expected call of Failed
+func (mr *MockfailedConsumersMockRecorder) Failed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failed", reflect.TypeOf((*MockfailedConsumers)(nil).Failed))
+}
+
+// MockdeadLetter is a mock of deadLetter interface
+type MockdeadLetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockdeadLetterMockRecorder
+}
+
+// MockdeadLetterMockRecorder is the mock recorder for MockdeadLetter
+type MockdeadLetterMockRecorder struct {
+	mock *MockdeadLetter
+}
+
+// NewMockdeadLetter creates a new mock instance
+func NewMockdeadLetter(ctrl *gomock.Controller) *MockdeadLetter {
+	mock := &MockdeadLetter{ctrl: ctrl}
+	mock.recorder = &MockdeadLetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockdeadLetter) EXPECT() *MockdeadLetterMockRecorder {
+	return m.recorder
+}
+
+// CreateAll mocks base method
+func (m *MockdeadLetter) CreateAll(ctx context.Context, dls []entity.DeadLetter) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAll", ctx, dls)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAll indicates an expected call of CreateAll
+func (mr *MockdeadLetterMockRecorder) CreateAll(ctx, dls interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAll", reflect.TypeOf((*MockdeadLetter)(nil).CreateAll), ctx, dls)
+}
@@ -5,81 +5,213 @@ package notifier
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
 	"github.com/faceit/test/logger"
+
+	"github.com/google/uuid"
 )
 
 type notifier interface {
 	Send(ctx context.Context, consumer []string, message []byte) error
+	SendBatch(ctx context.Context, consumer, format string, items []entity.BatchItem) error
+}
+
+// batches is a consumers batch settings interface
+type batches interface {
+	Batch(consumer string) entity.Batch
+}
+
+// failedConsumers is an error, that reports which consumers delivery has failed to
+type failedConsumers interface {
+	error
+	Failed() []string
+	Err(consumer string) error
+}
+
+// deadLetter is a dead letter store interface
+type deadLetter interface {
+	CreateAll(ctx context.Context, dls []entity.DeadLetter) ([]int, error)
 }
 
 // Notifier is a notifier struct
 type Notifier struct {
-	notifier              notifier
-	timeout               int
-	clientMaxRetry        int
-	clientTimeoutIncrease int
-	consumers             []string
-	consumersMap          map[string]struct{}
-	log                   logger.Logger
+	notifier         notifier
+	deadLetter       deadLetter
+	timeout          int
+	clientMaxRetry   int
+	backoffBase      time.Duration
+	backoffMax       time.Duration
+	breakerThreshold int
+	breakerTimeout   time.Duration
+	breakersMu       *sync.RWMutex
+	breakers         map[string]*breaker
+	names            map[string]string
+	countersMu       *sync.Mutex
+	counters         map[string]*entity.ConsumerStats
+	batches          batches
+	batchersMu       *sync.Mutex
+	batchers         map[string]*batcher
+	log              logger.Logger
 }
 
-func New(cfg config.Notifier, n notifier, consumers []string, l logger.Logger) *Notifier {
+// New creates new Notifier instance
+// circuit breakers of consumers are created in advance, so they are reported by Health before first delivery,
+// consumers are named by their position, as urls are not exposed by Health,
+// messages, that could not be delivered after all retries are stored into d,
+// messages to consumers with batching enabled in b are grouped into batches, b is optional
+func New(cfg config.Notifier, n notifier, consumers []string, d deadLetter, b batches, l logger.Logger) *Notifier {
 	notifier := &Notifier{
-		notifier:              n,
-		log:                   l,
-		timeout:               cfg.Timeout,
-		clientMaxRetry:        cfg.ClientMaxRetry,
-		clientTimeoutIncrease: cfg.ClientTimeoutIncrease,
+		notifier:         n,
+		deadLetter:       d,
+		batches:          b,
+		log:              l,
+		timeout:          cfg.Timeout,
+		clientMaxRetry:   cfg.ClientMaxRetry,
+		backoffBase:      time.Duration(cfg.ClientTimeoutIncrease) * time.Second,
+		backoffMax:       time.Duration(cfg.MaxBackoff) * time.Second,
+		breakerThreshold: cfg.BreakerThreshold,
+		breakerTimeout:   time.Duration(cfg.BreakerTimeout) * time.Second,
+		breakersMu:       &sync.RWMutex{},
+		breakers:         make(map[string]*breaker),
+		names:            make(map[string]string),
+		countersMu:       &sync.Mutex{},
+		counters:         make(map[string]*entity.ConsumerStats),
+		batchersMu:       &sync.Mutex{},
+		batchers:         make(map[string]*batcher),
 	}
 
-	notifier.consumersMap = make(map[string]struct{})
-
 	for _, c := range consumers {
-		if _, ok := notifier.consumersMap[c]; !ok {
-			notifier.consumersMap[c] = struct{}{}
-			notifier.consumers = append(notifier.consumers, c)
+		if _, ok := notifier.breakers[c]; ok {
+			continue
 		}
+
+		notifier.breakers[c] = newBreaker(notifier.breakerThreshold, notifier.breakerTimeout)
+		notifier.names[c] = fmt.Sprintf("config %d", len(notifier.names)+1)
 	}
 
 	return notifier
 }
 
+// Retain keeps state of consumers of subs and removes state (breakers, batchers and counters) of consumers,
+// that are not subscribed anymore, so it does not grow with every removed subscription. Breakers of new consumers are created in advance,
+// consumers are named by ids of their subscriptions, consumers from config keep their names
+func (n *Notifier) Retain(subs []entity.Subscription) {
+	ids := make(map[string][]string, len(subs))
+
+	for _, s := range subs {
+		if _, ok := ids[s.URL]; !ok {
+			ids[s.URL] = []string{}
+		}
+
+		// subscriptions from config have no id
+		if s.ID != 0 {
+			ids[s.URL] = append(ids[s.URL], strconv.Itoa(s.ID))
+		}
+	}
+
+	n.breakersMu.Lock()
+
+	for c := range n.breakers {
+		if _, ok := ids[c]; !ok {
+			delete(n.breakers, c)
+			delete(n.names, c)
+		}
+	}
+
+	for c, id := range ids {
+		if _, ok := n.breakers[c]; !ok {
+			n.breakers[c] = newBreaker(n.breakerThreshold, n.breakerTimeout)
+		}
+
+		if len(id) > 0 {
+			n.names[c] = "subscription " + strings.Join(id, ",")
+		}
+	}
+
+	n.breakersMu.Unlock()
+
+	n.batchersMu.Lock()
+
+	for c := range n.batchers {
+		if _, ok := ids[c]; !ok {
+			delete(n.batchers, c)
+		}
+	}
+
+	n.batchersMu.Unlock()
+
+	n.countersMu.Lock()
+	defer n.countersMu.Unlock()
+
+	for c := range n.counters {
+		if _, ok := ids[c]; !ok {
+			delete(n.counters, c)
+		}
+	}
+}
+
 // Do sends a messages to one or many consumers
-// if consumers slice is empty, would send messages to all consumers
-func (n *Notifier) Do(ctx context.Context, consumers []string, message interface{}) {
-	ctx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(n.timeout))
+// if message could not be delivered to some consumers after all retries, it is stored
+// as a dead letter for each of them. Error is returned, if dead letter could not be stored
+func (n *Notifier) Do(ctx context.Context, consumers []string, message interface{}) error {
+	ctx = withEventID(ctx)
+
+	sendCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(n.timeout))
 	defer cancel()
 
 	messageByte, err := json.Marshal(message)
 	if err != nil {
 		n.log.Errorf(ctx, "failed to marshal message %#v, error: %w", message, err)
-		return
+		return fmt.Errorf("failed to marshal message, error: %w", err)
 	}
 
 	cc := n.getConsumers(consumers)
 	if len(cc) == 0 {
 		n.log.Warningf(ctx, "no consumers to send message to")
-		return
+		return nil
 	}
 
-	err = n.sendWithRetry(ctx, cc, messageByte)
-	if err != nil {
-		n.log.Errorf(ctx, "after %d retries still failed to send message, error: ", err)
+	failed, attempts := n.sendWithRetry(sendCtx, cc, messageByte)
+	if len(failed) == 0 {
+		return nil
 	}
+
+	n.log.Errorf(ctx, "after retries still failed to send message to %d consumer(s)", len(failed))
+
+	// send context could be already expired, so parent one is used to store dead letters
+	return n.storeDeadLetters(ctx, failed, attempts, messageByte)
 }
 
-func (n *Notifier) getConsumers(cc []string) []string {
-	if len(cc) == 0 {
-		return cc
-	}
+// Redeliver sends message to one consumer with retries and returns number of attempts made
+// message is not stored as a dead letter, if delivery fails, event id is taken from ctx
+func (n *Notifier) Redeliver(ctx context.Context, consumer string, message []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(withEventID(ctx), time.Second*time.Duration(n.timeout))
+	defer cancel()
+
+	failed, attempts := n.sendWithRetry(ctx, []string{consumer}, message)
+
+	return attempts[consumer], failed[consumer]
+}
 
-	consumers := make([]string, 0, len(n.consumers))
+// getConsumers returns unique consumers from cc
+// consumers are resolved by subscriptions, so any of them is accepted
+func (n *Notifier) getConsumers(cc []string) []string {
+	consumers := make([]string, 0, len(cc))
+	seen := make(map[string]struct{}, len(cc))
 
 	for _, c := range cc {
-		if _, ok := n.consumersMap[c]; ok {
+		if _, ok := seen[c]; !ok {
+			seen[c] = struct{}{}
 			consumers = append(consumers, c)
 		}
 	}
@@ -87,25 +219,391 @@ func (n *Notifier) getConsumers(cc []string) []string {
 	return consumers
 }
 
-func (n *Notifier) sendWithRetry(ctx context.Context, consumers []string, message []byte) error {
-	var (
-		i   int
-		err error
-	)
+// Health returns circuit breaker state of every consumer
+// consumer is unhealthy, while it's breaker is open. Consumer is reported by it's name, as url could hold credentials,
+// consumers without name are not subscribed anymore, their breakers are removed on the next Retain
+func (n *Notifier) Health(ctx context.Context) []entity.Response {
+	n.breakersMu.RLock()
+
+	breakers := make(map[string]*breaker, len(n.breakers))
+	urls := make(map[string]string, len(n.breakers))
+	names := make([]string, 0, len(n.breakers))
+
+	for c, b := range n.breakers {
+		name, ok := n.names[c]
+		if !ok {
+			continue
+		}
+
+		breakers[name] = b
+		urls[name] = c
+		names = append(names, name)
+	}
+
+	n.breakersMu.RUnlock()
+
+	sort.Strings(names)
+
+	resp := make([]entity.Response, 0, len(names))
+
+	for _, name := range names {
+		state, message := breakers[name].status()
+
+		resp = append(resp, entity.Response{
+			Name:    "consumer: " + name,
+			Healthy: state != StateOpen,
+			Time:    time.Now().UTC().Format(time.RFC3339),
+			// last error of delivery could hold url
+			Message: strings.ReplaceAll(message, urls[name], name),
+		})
+	}
+
+	return resp
+}
+
+// sendWithRetry returns consumers, message has not been delivered to, with last errors,
+// and numbers of attempts, made to every consumer
+// consumers with open circuit breaker are not retried, so their attempts could be less than ClientMaxRetry, or even zero
+// delay between retries grows exponentially with random jitter and is limited by ctx
+func (n *Notifier) sendWithRetry(ctx context.Context, consumers []string, message []byte) (map[string]error, map[string]int) {
+	failed := make(map[string]error, len(consumers))
+	attempts := make(map[string]int, len(consumers))
+	pending := consumers
+
+	for i := 0; i < n.clientMaxRetry; i++ {
+		allowed := n.allowed(pending, failed)
+		if len(allowed) == 0 {
+			break
+		}
+
+		for _, c := range allowed {
+			attempts[c]++
+		}
+
+		err := n.send(ctx, allowed, message)
+		if err == nil {
+			n.record(allowed, nil, failed)
+			break
+		}
+
+		n.log.Warningf(ctx, "failed to end message to %#v, error: %w", allowed, err)
+
+		// retrying only consumers, that have not received a message yet
+		pending = n.record(allowed, err, failed)
+		if len(pending) == 0 {
+			break
+		}
+
+		if i+1 < n.clientMaxRetry && !n.wait(ctx, i) {
+			break
+		}
+	}
+
+	n.count(consumers, failed)
+
+	return failed, attempts
+}
+
+// count updates delivered and failed counters of consumers with result of delivery
+func (n *Notifier) count(consumers []string, failed map[string]error) {
+	n.countersMu.Lock()
+	defer n.countersMu.Unlock()
+
+	for _, c := range consumers {
+		s, ok := n.counters[c]
+		if !ok {
+			s = &entity.ConsumerStats{Consumer: c}
+			n.counters[c] = s
+		}
+
+		err, ok := failed[c]
+		if !ok {
+			s.Delivered++
+			continue
+		}
+
+		at := time.Now().UTC()
+
+		s.Failed++
+		s.LastError = err.Error()
+		s.LastErrorTime = &at
+	}
+}
+
+// ConsumerStats returns numbers of messages delivered and failed to be delivered to every subscribed consumer
+// message is counted once per consumer, regardless of number of retries.
+// Consumer is reported by it's name, as Health does, url is replaced by name in it's last error as well
+func (n *Notifier) ConsumerStats(ctx context.Context) []entity.ConsumerStats {
+	n.breakersMu.RLock()
+
+	names := make(map[string]string, len(n.names))
+	for c, name := range n.names {
+		names[c] = name
+	}
+
+	n.breakersMu.RUnlock()
+
+	n.countersMu.Lock()
+	defer n.countersMu.Unlock()
+
+	stats := make([]entity.ConsumerStats, 0, len(n.counters))
+
+	for c, s := range n.counters {
+		name, ok := names[c]
+		if !ok {
+			continue
+		}
+
+		stat := *s
+		stat.Consumer = name
+		stat.LastError = strings.ReplaceAll(stat.LastError, c, name)
+
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Consumer < stats[j].Consumer
+	})
+
+	return stats
+}
+
+// ConsumerName returns a name, consumer is reported by,
+// false is returned, if consumer is not subscribed
+func (n *Notifier) ConsumerName(consumer string) (string, bool) {
+	n.breakersMu.RLock()
+	defer n.breakersMu.RUnlock()
+
+	name, ok := n.names[consumer]
 
-	for i < n.clientMaxRetry {
-		err = n.notifier.Send(ctx, consumers, message)
-		if err != nil {
-			n.log.Warningf(ctx, "failed to end message to %#v, error: %w", consumers, err)
+	return name, ok
+}
 
-			time.Sleep(time.Duration(n.clientTimeoutIncrease*i) * time.Second)
+// send sends message to consumers, messages to consumers with batching enabled are added to their batches
+// delivery to every consumer is reported by returned failedConsumers error
+func (n *Notifier) send(ctx context.Context, consumers []string, message []byte) error {
+	direct := make([]string, 0, len(consumers))
+	batched := make(map[string]*batcher)
 
-			i++
+	for _, c := range consumers {
+		if b := n.batcher(c); b != nil {
+			batched[c] = b
 			continue
 		}
 
+		direct = append(direct, c)
+	}
+
+	if len(batched) == 0 {
+		return n.notifier.Send(ctx, direct, message)
+	}
+
+	errs := make(consumerErrors, len(consumers))
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+
+	for c, b := range batched {
+		wg.Add(1)
+
+		go func(c string, b *batcher) {
+			defer wg.Done()
+
+			err := b.add(ctx, entity.BatchItem{ID: cont.EventID(ctx), Message: message})
+
+			mu.Lock()
+			errs[c] = err
+			mu.Unlock()
+		}(c, b)
+	}
+
+	if len(direct) > 0 {
+		err := n.notifier.Send(ctx, direct, message)
+
+		var f failedConsumers
+		if err != nil && !errors.As(err, &f) {
+			f = nil
+		}
+
+		mu.Lock()
+		for _, c := range direct {
+			errs[c] = err
+			if f != nil {
+				errs[c] = f.Err(c)
+			}
+		}
+		mu.Unlock()
+	}
+
+	wg.Wait()
+
+	if len(errs.Failed()) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// batcher returns batcher of consumer, or nil, if batching is disabled for it
+// batcher is replaced, once consumer's batch settings are changed
+func (n *Notifier) batcher(consumer string) *batcher {
+	if n.batches == nil {
+		return nil
+	}
+
+	batch := n.batches.Batch(consumer)
+	if !batch.Enabled() {
 		return nil
 	}
 
-	return err
+	n.batchersMu.Lock()
+	defer n.batchersMu.Unlock()
+
+	b, ok := n.batchers[consumer]
+	if !ok || b.batch != batch {
+		b = newBatcher(consumer, batch, time.Second*time.Duration(n.timeout), n.notifier.SendBatch)
+		n.batchers[consumer] = b
+	}
+
+	return b
+}
+
+// allowed returns consumers, which circuit breakers allow delivery
+// rejected consumers are added to failed
+func (n *Notifier) allowed(consumers []string, failed map[string]error) []string {
+	allowed := make([]string, 0, len(consumers))
+
+	for _, c := range consumers {
+		if !n.breaker(c).allow() {
+			// keeping the last delivery error, if breaker was opened by it
+			if _, ok := failed[c]; !ok {
+				failed[c] = fmt.Errorf("%s, %w", c, ErrCircuitOpen)
+			}
+
+			continue
+		}
+
+		allowed = append(allowed, c)
+	}
+
+	return allowed
+}
+
+// record updates circuit breakers and failed with result of delivery to consumers
+// and returns consumers, delivery to which has failed
+func (n *Notifier) record(consumers []string, err error, failed map[string]error) []string {
+	var f failedConsumers
+	if err != nil && !errors.As(err, &f) {
+		f = nil
+	}
+
+	pending := make([]string, 0, len(consumers))
+
+	for _, c := range consumers {
+		consumerErr := err
+		if f != nil {
+			consumerErr = f.Err(c)
+		}
+
+		if consumerErr == nil {
+			n.breaker(c).success()
+			delete(failed, c)
+
+			continue
+		}
+
+		n.breaker(c).failure(consumerErr)
+		failed[c] = consumerErr
+		pending = append(pending, c)
+	}
+
+	return pending
+}
+
+// wait sleeps for a backoff delay before next attempt
+// false is returned, if ctx is done earlier
+func (n *Notifier) wait(ctx context.Context, attempt int) bool {
+	d := backoff(n.backoffBase, n.backoffMax, attempt)
+	if d == 0 {
+		return ctx.Err() == nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// breaker returns consumer's circuit breaker, creating one if it is missing
+func (n *Notifier) breaker(consumer string) *breaker {
+	n.breakersMu.RLock()
+	b, ok := n.breakers[consumer]
+	n.breakersMu.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	n.breakersMu.Lock()
+	defer n.breakersMu.Unlock()
+
+	if b, ok = n.breakers[consumer]; !ok {
+		b = newBreaker(n.breakerThreshold, n.breakerTimeout)
+		n.breakers[consumer] = b
+	}
+
+	return b
+}
+
+// withEventID sets a new event id into ctx, if it is missing
+// every consumer and every retry gets the same event id, so consumers could deduplicate deliveries
+func withEventID(ctx context.Context) context.Context {
+	if cont.EventID(ctx) != "" {
+		return ctx
+	}
+
+	return cont.SetEventID(ctx, uuid.New().String())
+}
+
+// storeDeadLetters stores message as a dead letter for every failed consumer with attempts, made to it
+// dead letters are stored all at once, so if it fails, retry of the message does not duplicate any of them
+func (n *Notifier) storeDeadLetters(ctx context.Context, failed map[string]error, attempts map[string]int, message []byte) error {
+	if n.deadLetter == nil {
+		return fmt.Errorf("failed to deliver message to %d consumer(s)", len(failed))
+	}
+
+	consumers := make([]string, 0, len(failed))
+	for c := range failed {
+		consumers = append(consumers, c)
+	}
+
+	sort.Strings(consumers)
+
+	dls := make([]entity.DeadLetter, 0, len(consumers))
+
+	for _, c := range consumers {
+		dls = append(dls, entity.DeadLetter{
+			EventID:   cont.EventID(ctx),
+			Consumer:  c,
+			LastError: failed[c].Error(),
+			Attempts:  attempts[c],
+			Payload:   message,
+		})
+	}
+
+	ids, err := n.deadLetter.CreateAll(ctx, dls)
+	if err != nil {
+		n.log.Errorf(ctx, "failed to store dead letters for %d consumer(s), error: %s", len(dls), err)
+		return fmt.Errorf("failed to store dead letters, error: %w", err)
+	}
+
+	for i, id := range ids {
+		n.log.Warningf(ctx, "message for %s is stored as dead letter %d", consumers[i], id)
+	}
+
+	return nil
 }
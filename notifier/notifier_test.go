@@ -4,20 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/logger"
 	mock_logger "github.com/faceit/test/logger/mock"
 	notifier_mock "github.com/faceit/test/notifier/mock"
+	"github.com/faceit/test/notifier/webhook"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 )
 
 var (
 	errTest = errors.New("error_test")
 
 	testID      = 1
+	testEventID = "test_event_id"
 	testMessage = entity.NotifierMessage{Message: entity.UserResponse{
 		ID: testID,
 	},
@@ -27,10 +33,11 @@ var (
 	testMessageByte, _ = json.Marshal(testMessage)
 	testConsumers      = []string{"test_consumer"}
 	testConfig         = config.Notifier{
-		Consumers:             config.Consumers{OnCreate: testConsumers},
-		Timeout:               1,
-		ClientMaxRetry:        3,
-		ClientTimeoutIncrease: 1,
+		Consumers:        config.Consumers{OnCreate: testConsumers},
+		Timeout:          1,
+		ClientMaxRetry:   3,
+		BreakerThreshold: 5,
+		BreakerTimeout:   30,
 	}
 )
 
@@ -40,56 +47,405 @@ func TestDo(t *testing.T) {
 		ctx := context.Background()
 
 		mockNotifier := notifier_mock.NewMocknotifier(ctr)
-		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(nil)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).DoAndReturn(
+			func(ctx context.Context, _ []string, _ []byte) error {
+				// event id is generated, if it is missing
+				assert.NotEmpty(t, cont.EventID(ctx))
+				return nil
+			})
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
 
 		mockLogger := mock_logger.NewMocklog(ctr)
 
-		New(testConfig, mockNotifier, testConsumers, logger.New(mockLogger)).
+		New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
 			Do(ctx, testConsumers, testMessage)
 
 	})
 
 	t.Run("positive_2_tries", func(t *testing.T) {
 		ctr := gomock.NewController(t)
-		ctx := context.Background()
+		ctx := cont.SetEventID(context.Background(), testEventID)
 
 		mockNotifier := notifier_mock.NewMocknotifier(ctr)
 		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(errTest)
 		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(nil)
 
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+
 		mockLogger := mock_logger.NewMocklog(ctr)
 		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
 
-		New(testConfig, mockNotifier, testConsumers, logger.New(mockLogger)).
+		New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
 			Do(ctx, testConsumers, testMessage)
 	})
 
+	t.Run("positive_retry_failed_consumers_only", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		consumers := []string{"first_consumer", "second_consumer"}
+		sendErr := &webhook.Error{Results: []webhook.Result{
+			{Consumer: consumers[0]},
+			{Consumer: consumers[1], Err: errTest},
+		}}
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), consumers, testMessageByte).Return(sendErr)
+		mockNotifier.EXPECT().Send(gomock.Any(), consumers[1:], testMessageByte).Return(nil)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
+
+		New(testConfig, mockNotifier, consumers, mockDeadLetter, nil, logger.New(mockLogger)).
+			Do(ctx, consumers, testMessage)
+	})
+
 	t.Run("positive_noConsumers", func(t *testing.T) {
 		ctr := gomock.NewController(t)
-		ctx := context.Background()
+		ctx := cont.SetEventID(context.Background(), testEventID)
 
 		mockNotifier := notifier_mock.NewMocknotifier(ctr)
 
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+
 		mockLogger := mock_logger.NewMocklog(ctr)
 		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
 
-		New(testConfig, mockNotifier, testConsumers, logger.New(mockLogger)).
+		New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
 			Do(ctx, []string{}, testMessage)
 
 	})
 
 	t.Run("negative", func(t *testing.T) {
 		ctr := gomock.NewController(t)
-		ctx := context.Background()
+		ctx := cont.SetEventID(context.Background(), testEventID)
 
 		mockNotifier := notifier_mock.NewMocknotifier(ctr)
 		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(errTest).Times(3)
 
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockDeadLetter.EXPECT().CreateAll(ctx, []entity.DeadLetter{{
+			EventID:   testEventID,
+			Consumer:  testConsumers[0],
+			LastError: errTest.Error(),
+			Attempts:  testConfig.ClientMaxRetry,
+			Payload:   testMessageByte,
+		}}).Return([]int{testID}, nil)
+
 		mockLogger := mock_logger.NewMocklog(ctr)
-		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(3)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(4)
 		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(1)
 
-		New(testConfig, mockNotifier, testConsumers, logger.New(mockLogger)).
+		err := New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
+			Do(ctx, testConsumers, testMessage)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative_dead_letter_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(errTest).Times(3)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockDeadLetter.EXPECT().CreateAll(ctx, gomock.Any()).Return(nil, errTest)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(3)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(2)
+
+		err := New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
 			Do(ctx, testConsumers, testMessage)
+		assert.True(t, errors.Is(err, errTest))
+	})
+
+	t.Run("negative_dead_letters_stored_at_once", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		consumers := []string{"consumer_b", "consumer_a"}
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), consumers, testMessageByte).Return(errTest).Times(3)
+
+		// dead letters of all failed consumers are stored by one call, so none of them is stored, if it fails
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockDeadLetter.EXPECT().CreateAll(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, dls []entity.DeadLetter) ([]int, error) {
+				assert.Len(t, dls, 2)
+				assert.Equal(t, "consumer_a", dls[0].Consumer)
+				assert.Equal(t, "consumer_b", dls[1].Consumer)
+				return nil, errTest
+			})
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(3)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(2)
+
+		err := New(testConfig, mockNotifier, consumers, mockDeadLetter, nil, logger.New(mockLogger)).
+			Do(ctx, consumers, testMessage)
+		assert.ErrorIs(t, err, errTest)
+	})
+
+	t.Run("negative_circuit_open", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		cfg := testConfig
+		cfg.BreakerThreshold = 2
+
+		// breaker opens after 2nd failure, so the 3rd attempt and the next message are not sent,
+		// dead letters keep attempts, that were actually made
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).
+			Return(fmt.Errorf("%s, %w", testConsumers[0], errTest)).Times(2)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockDeadLetter.EXPECT().CreateAll(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, dls []entity.DeadLetter) ([]int, error) {
+				assert.Len(t, dls, 1)
+				assert.Contains(t, dls[0].LastError, errTest.Error())
+				assert.Equal(t, 2, dls[0].Attempts)
+				return []int{testID}, nil
+			})
+		mockDeadLetter.EXPECT().CreateAll(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, dls []entity.DeadLetter) ([]int, error) {
+				assert.Len(t, dls, 1)
+				assert.Contains(t, dls[0].LastError, ErrCircuitOpen.Error())
+				assert.Zero(t, dls[0].Attempts)
+				return []int{testID}, nil
+			})
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(4)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(2)
+
+		n := New(cfg, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger))
+
+		assert.Nil(t, n.Do(ctx, testConsumers, testMessage))
+		assert.Nil(t, n.Do(ctx, testConsumers, testMessage))
+
+		health := n.Health(ctx)
+		assert.Len(t, health, 1)
+		assert.Equal(t, "consumer: config 1", health[0].Name)
+		assert.False(t, health[0].Healthy)
+		assert.Contains(t, health[0].Message, StateOpen)
+
+		// url in last error is replaced by name
+		assert.Contains(t, health[0].Message, "last error: config 1, "+errTest.Error())
+		assert.NotContains(t, health[0].Message, testConsumers[0])
+	})
+}
+
+func TestDoBatch(t *testing.T) {
+	t.Run("positive_retry_failed_items_only", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		batched := "batch_consumer"
+		consumers := []string{testConsumers[0], batched}
+
+		first := cont.SetEventID(context.Background(), "first_event_id")
+		second := cont.SetEventID(context.Background(), "second_event_id")
+
+		mockBatches := notifier_mock.NewMockbatches(ctr)
+		mockBatches.EXPECT().Batch(testConsumers[0]).Return(entity.Batch{}).AnyTimes()
+		mockBatches.EXPECT().Batch(batched).Return(entity.Batch{Size: 2, Window: 10}).AnyTimes()
+
+		// consumer without batching gets messages one by one
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(nil).Times(2)
+
+		// both messages are sent in one batch, and only the rejected one is sent again
+		gomock.InOrder(
+			mockNotifier.EXPECT().SendBatch(gomock.Any(), batched, "", gomock.Len(2)).DoAndReturn(
+				func(_ context.Context, consumer, _ string, _ []entity.BatchItem) error {
+					return &webhook.BatchError{Consumer: consumer, Items: []string{"second_event_id"}, Err: webhook.ErrItemsRejected}
+				}),
+			mockNotifier.EXPECT().SendBatch(gomock.Any(), batched, "", []entity.BatchItem{
+				{ID: "second_event_id", Message: testMessageByte},
+			}).Return(nil),
+		)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
+
+		n := New(testConfig, mockNotifier, consumers, notifier_mock.NewMockdeadLetter(ctr), mockBatches, logger.New(mockLogger))
+
+		wg := &sync.WaitGroup{}
+
+		for _, ctx := range []context.Context{first, second} {
+			wg.Add(1)
+
+			go func(ctx context.Context) {
+				defer wg.Done()
+
+				assert.Nil(t, n.Do(ctx, consumers, testMessage))
+			}(ctx)
+		}
+
+		wg.Wait()
+
+		stats := n.ConsumerStats(context.Background())
+		assert.Equal(t, int64(2), stats[0].Delivered)
+		assert.Equal(t, int64(2), stats[1].Delivered)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		consumers := []string{"consumer_b", "consumer_a"}
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		health := New(testConfig, mockNotifier, consumers, mockDeadLetter, nil, logger.New(mockLogger)).Health(ctx)
+		assert.Len(t, health, 2)
+
+		// urls are not exposed, consumers from config are named by their position
+		for i, name := range []string{"config 1", "config 2"} {
+			assert.Equal(t, "consumer: "+name, health[i].Name)
+			assert.True(t, health[i].Healthy)
+		}
+	})
+}
+
+func TestRetain(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		static := "http://static.test"
+
+		n := New(testConfig, notifier_mock.NewMocknotifier(ctr), []string{static}, notifier_mock.NewMockdeadLetter(ctr), nil,
+			logger.New(mock_logger.NewMocklog(ctr)))
+
+		n.Retain([]entity.Subscription{
+			{URL: static},
+			{ID: 2, URL: "http://consumer.test"},
+			{ID: 3, URL: "http://consumer.test"},
+			{ID: 4, URL: "http://removed.test"},
+		})
+
+		// consumer, that was unsubscribed during delivery, is not reported
+		n.breaker("http://unsubscribed.test")
+
+		health := n.Health(ctx)
+		assert.Len(t, health, 3)
+		assert.Equal(t, "consumer: config 1", health[0].Name)
+		assert.Equal(t, "consumer: subscription 2,3", health[1].Name)
+		assert.Equal(t, "consumer: subscription 4", health[2].Name)
+
+		n.Retain([]entity.Subscription{{URL: static}})
+
+		health = n.Health(ctx)
+		assert.Len(t, health, 1)
+		assert.Equal(t, "consumer: config 1", health[0].Name)
+		assert.Len(t, n.breakers, 1)
+	})
+}
+
+func TestConsumerStats(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		consumers := []string{"consumer_b", "consumer_a"}
+		sendErr := &webhook.Error{Results: []webhook.Result{
+			{Consumer: consumers[0], Err: errTest},
+			{Consumer: consumers[1]},
+		}}
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), consumers, testMessageByte).Return(sendErr)
+		mockNotifier.EXPECT().Send(gomock.Any(), consumers[:1], testMessageByte).
+			Return(fmt.Errorf("%s, %w", consumers[0], errTest)).Times(2)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+		mockDeadLetter.EXPECT().CreateAll(ctx, gomock.Any()).Return([]int{testID}, nil)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(4)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(1)
+
+		n := New(testConfig, mockNotifier, consumers, mockDeadLetter, nil, logger.New(mockLogger))
+
+		err := n.Do(ctx, consumers, testMessage)
+		assert.Nil(t, err)
+
+		// message is counted once per consumer, retries are not counted,
+		// consumers are reported by names, urls are not exposed
+		stats := n.ConsumerStats(ctx)
+		assert.Len(t, stats, 2)
+
+		assert.Equal(t, "config 1", stats[0].Consumer)
+		assert.Zero(t, stats[0].Delivered)
+		assert.Equal(t, int64(1), stats[0].Failed)
+		assert.Equal(t, "config 1, "+errTest.Error(), stats[0].LastError)
+		assert.NotNil(t, stats[0].LastErrorTime)
+
+		assert.Equal(t, "config 2", stats[1].Consumer)
+		assert.Equal(t, int64(1), stats[1].Delivered)
+		assert.Zero(t, stats[1].Failed)
+		assert.Nil(t, stats[1].LastErrorTime)
+
+		name, ok := n.ConsumerName(consumers[1])
+		assert.True(t, ok)
+		assert.Equal(t, "config 2", name)
+
+		// counters of unsubscribed consumer are removed
+		n.Retain([]entity.Subscription{{URL: consumers[0]}})
+
+		stats = n.ConsumerStats(ctx)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, "config 1", stats[0].Consumer)
+		assert.Len(t, n.counters, 1)
+
+		_, ok = n.ConsumerName(consumers[1])
+		assert.False(t, ok)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(nil)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		attempts, err := New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
+			Redeliver(ctx, testConsumers[0], testMessageByte)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), testEventID)
+
+		mockNotifier := notifier_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Send(gomock.Any(), testConsumers, testMessageByte).Return(errTest).Times(3)
+
+		mockDeadLetter := notifier_mock.NewMockdeadLetter(ctr)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(3)
+
+		attempts, err := New(testConfig, mockNotifier, testConsumers, mockDeadLetter, nil, logger.New(mockLogger)).
+			Redeliver(ctx, testConsumers[0], testMessageByte)
+		assert.Equal(t, errTest, err)
+		assert.Equal(t, testConfig.ClientMaxRetry, attempts)
 	})
 }
@@ -0,0 +1,127 @@
+// Package signature signs webhook deliveries and verifies them on consumer side
+//
+// every delivery carries HeaderEventID, HeaderTimestamp (unix seconds) and HeaderSignature headers.
+// Signature is a hex encoded HMAC-SHA256 over "<timestamp>.<body>", keyed by consumer's secret.
+// During secret rotation delivery is signed with both active secrets, so HeaderSignature
+// holds comma separated "v1=<signature>" entries, and it is valid if any of them matches.
+//
+// Consumer verifies delivery with
+//
+//	body, err := ioutil.ReadAll(r.Body)
+//	...
+//	err = signature.Verify(r.Header, body, 5*time.Minute, secret)
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// delivery headers
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEventID   = "X-Webhook-Event-Id"
+)
+
+const (
+	version    = "v1"
+	secretSize = 32
+)
+
+// package errors
+var (
+	ErrMissingHeader    = errors.New("missing signature header")
+	ErrInvalidTimestamp = errors.New("invalid signature timestamp")
+	ErrExpired          = errors.New("signature timestamp is out of tolerance")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Sign returns a hex encoded HMAC-SHA256 of timestamp and body
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Header returns a HeaderSignature value with signatures by every secret
+func Header(secrets []string, timestamp int64, body []byte) string {
+	signatures := make([]string, 0, len(secrets))
+
+	for _, s := range secrets {
+		signatures = append(signatures, version+"="+Sign(s, timestamp, body))
+	}
+
+	return strings.Join(signatures, ",")
+}
+
+// SetHeaders sets event id, timestamp and signature headers of a delivery
+// signature header is not set, if there are no secrets
+func SetHeaders(h http.Header, eventID string, secrets []string, timestamp int64, body []byte) {
+	h.Set(HeaderEventID, eventID)
+	h.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+
+	if len(secrets) != 0 {
+		h.Set(HeaderSignature, Header(secrets, timestamp, body))
+	}
+}
+
+// Verify checks, that body was signed by one of secrets no longer than tolerance ago
+// timestamp is not checked, if tolerance is not positive
+func Verify(h http.Header, body []byte, tolerance time.Duration, secrets ...string) error {
+	header := h.Get(HeaderSignature)
+	ts := h.Get(HeaderTimestamp)
+
+	if header == "" || ts == "" {
+		return ErrMissingHeader
+	}
+
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w, %s", ErrInvalidTimestamp, err)
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrExpired
+		}
+	}
+
+	for _, s := range secrets {
+		expected := []byte(Sign(s, timestamp, body))
+
+		for _, sig := range strings.Split(header, ",") {
+			v := strings.TrimPrefix(strings.TrimSpace(sig), version+"=")
+			if hmac.Equal(expected, []byte(v)) {
+				return nil
+			}
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// NewSecret returns a new random hex encoded secret
+func NewSecret() (string, error) {
+	b := make([]byte, secretSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate secret, error: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
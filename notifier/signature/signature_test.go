@@ -0,0 +1,96 @@
+package signature
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testBody     = []byte(`{"action":"CREATE"}`)
+	testSecret   = "secret"
+	testPrevious = "previous_secret"
+	testEventID  = "event_id"
+)
+
+func TestVerify(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Unix(), testBody)
+
+		assert.Equal(t, testEventID, h.Get(HeaderEventID))
+		assert.Nil(t, Verify(h, testBody, time.Minute, testSecret))
+	})
+
+	t.Run("positive_rotation", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret, testPrevious}, time.Now().Unix(), testBody)
+
+		assert.Len(t, strings.Split(h.Get(HeaderSignature), ","), 2)
+
+		// consumer, that did not rotate secret yet
+		assert.Nil(t, Verify(h, testBody, time.Minute, testPrevious))
+		// consumer, that already rotated it
+		assert.Nil(t, Verify(h, testBody, time.Minute, testSecret))
+	})
+
+	t.Run("negative_missing_signature", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, nil, time.Now().Unix(), testBody)
+
+		assert.Equal(t, ErrMissingHeader, Verify(h, testBody, time.Minute, testSecret))
+	})
+
+	t.Run("negative_wrong_secret", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Unix(), testBody)
+
+		assert.Equal(t, ErrInvalidSignature, Verify(h, testBody, time.Minute, testPrevious))
+	})
+
+	t.Run("negative_modified_body", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Unix(), testBody)
+
+		assert.Equal(t, ErrInvalidSignature, Verify(h, []byte(`{"action":"DELETE"}`), time.Minute, testSecret))
+	})
+
+	t.Run("negative_modified_timestamp", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Unix(), testBody)
+		h.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix()+1, 10))
+
+		assert.Equal(t, ErrInvalidSignature, Verify(h, testBody, time.Minute, testSecret))
+	})
+
+	t.Run("negative_expired", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Add(-time.Hour).Unix(), testBody)
+
+		assert.Equal(t, ErrExpired, Verify(h, testBody, time.Minute, testSecret))
+		assert.Nil(t, Verify(h, testBody, 0, testSecret))
+	})
+
+	t.Run("negative_invalid_timestamp", func(t *testing.T) {
+		h := http.Header{}
+		SetHeaders(h, testEventID, []string{testSecret}, time.Now().Unix(), testBody)
+		h.Set(HeaderTimestamp, "now")
+
+		assert.True(t, errors.Is(Verify(h, testBody, time.Minute, testSecret), ErrInvalidTimestamp))
+	})
+}
+
+func TestNewSecret(t *testing.T) {
+	s1, err := NewSecret()
+	assert.Nil(t, err)
+	assert.Len(t, s1, secretSize*2)
+
+	s2, err := NewSecret()
+	assert.Nil(t, err)
+	assert.NotEqual(t, s1, s2)
+}
@@ -0,0 +1,353 @@
+//go:generate mockgen -source ../webhook/webhook.go -destination ../webhook/mock/mock_webhook.go
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/notifier/signature"
+)
+
+// header constants
+const (
+	contentTypeKey        = "Content-Type"
+	contentTypeValue      = "application/json; charset=UTF-8"
+	contentTypeCloudEvent = "application/cloudevents+json; charset=UTF-8"
+	contentTypeBatch      = "application/cloudevents-batch+json; charset=UTF-8"
+	contentTypeNDJSON     = "application/x-ndjson; charset=UTF-8"
+
+	ceHeaderPrefix = "ce-"
+)
+
+// maxResponseSize is a maximal size of consumer's response, that is read
+const maxResponseSize = 1 << 20
+
+// package errors
+var (
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	ErrItemsRejected    = errors.New("batch items rejected by consumer")
+)
+
+// Result is a result of a delivery to one consumer
+type Result struct {
+	Consumer   string
+	StatusCode int
+	Err        error
+}
+
+// Error is returned by Send, if delivery failed for at least one consumer
+// it holds results for every consumer, message was sent to
+type Error struct {
+	Results []Result
+}
+
+// Error returns a list of failed consumers with their errors
+func (e *Error) Error() string {
+	failed := make([]string, 0, len(e.Results))
+
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", r.Consumer, r.Err))
+		}
+	}
+
+	return fmt.Sprintf("failed to deliver message to %d consumer(s): %s", len(failed), strings.Join(failed, "; "))
+}
+
+// Failed returns a list of consumers, delivery to which has failed
+func (e *Error) Failed() []string {
+	failed := make([]string, 0, len(e.Results))
+
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed = append(failed, r.Consumer)
+		}
+	}
+
+	return failed
+}
+
+// Err returns an error of delivery to consumer, or nil if it succeeded
+func (e *Error) Err(consumer string) error {
+	for _, r := range e.Results {
+		if r.Consumer == consumer {
+			return r.Err
+		}
+	}
+
+	return nil
+}
+
+// BatchError is returned by SendBatch, if some items of batch were not delivered
+// Items are ids of failed items, Err is an error of the whole batch, or ErrItemsRejected,
+// if consumer accepted batch, but reported some items as failed
+type BatchError struct {
+	Consumer string
+	Items    []string
+	Err      error
+}
+
+// Error returns a number of failed items with an error
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%s, failed to deliver %d item(s) of batch, error: %s", e.Consumer, len(e.Items), e.Err)
+}
+
+// Unwrap returns an error of batch
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// FailedItems returns ids of items, that were not delivered
+func (e *BatchError) FailedItems() []string {
+	return e.Items
+}
+
+// batchResponse is an optional body of consumer's response to batch
+// Failed are ids of items, consumer failed to process, the rest of items are delivered
+type batchResponse struct {
+	Failed []string `json:"failed"`
+}
+
+// batchItem is a batch item, that is not a CloudEvent
+type batchItem struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// secrets is a consumers signing secrets interface
+type secrets interface {
+	Secrets(consumer string) []string
+}
+
+// cloudEvent is a CloudEvents envelope, data is kept as is
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time"`
+	Subject         string          `json:"subject"`
+	DataContentType string          `json:"datacontenttype"`
+	DataSchema      string          `json:"dataschema"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Webhook is a notifier transport, that is POSTing messages to consumers urls
+type Webhook struct {
+	client  *http.Client
+	secrets secrets
+	binary  bool
+}
+
+// New creates new Webhook instance
+// messages are signed with consumer's secrets from s (see package signature), if s is not nil,
+// CloudEvents are sent in binary content mode, if binary is true, and in structured one otherwise
+func New(c *http.Client, s secrets, binary bool) *Webhook {
+	return &Webhook{
+		client:  c,
+		secrets: s,
+		binary:  binary,
+	}
+}
+
+// Send POSTs message to every consumer concurrently
+// request is bound to ctx, so ctx deadline is a deadline for the whole delivery
+// event id header is taken from ctx
+// if delivery to at least one consumer failed, *Error will be returned
+func (w *Webhook) Send(ctx context.Context, consumers []string, message []byte) error {
+	header, body := w.encode(message)
+
+	results := make([]Result, len(consumers))
+
+	wg := &sync.WaitGroup{}
+
+	for i := range consumers {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = w.post(ctx, consumers[i], header, body)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			return &Error{Results: results}
+		}
+	}
+
+	return nil
+}
+
+// SendBatch POSTs items to consumer as one request
+// batch is a JSON array of structured CloudEvents, or newline delimited JSON, if format is entity.BatchFormatNDJSON,
+// items, that are not CloudEvents, are wrapped into {"id": ..., "data": ...}. Event id header is taken from ctx.
+// *BatchError is returned, if request failed, or consumer reported some items as failed in response body
+func (w *Webhook) SendBatch(ctx context.Context, consumer, format string, items []entity.BatchItem) error {
+	header, body := encodeBatch(format, items)
+
+	result, resp := w.request(ctx, consumer, header, body)
+	if result.Err != nil {
+		ids := make([]string, 0, len(items))
+		for _, i := range items {
+			ids = append(ids, i.ID)
+		}
+
+		return &BatchError{Consumer: consumer, Items: ids, Err: result.Err}
+	}
+
+	var br batchResponse
+
+	// response body is optional, batch is delivered, if it could not be decoded
+	if err := json.Unmarshal(resp, &br); err != nil || len(br.Failed) == 0 {
+		return nil
+	}
+
+	return &BatchError{Consumer: consumer, Items: br.Failed, Err: ErrItemsRejected}
+}
+
+// encodeBatch returns headers and body of a request for batch items
+func encodeBatch(format string, items []entity.BatchItem) (http.Header, []byte) {
+	header := http.Header{}
+	events := true
+
+	encoded := make([][]byte, 0, len(items))
+
+	for _, i := range items {
+		var event cloudEvent
+		if err := json.Unmarshal(i.Message, &event); err == nil && event.SpecVersion != "" {
+			encoded = append(encoded, i.Message)
+			continue
+		}
+
+		events = false
+
+		// message is marshalled by notifier, so it is a valid JSON
+		item, _ := json.Marshal(batchItem{ID: i.ID, Data: i.Message})
+
+		encoded = append(encoded, item)
+	}
+
+	if format == entity.BatchFormatNDJSON {
+		header.Set(contentTypeKey, contentTypeNDJSON)
+
+		return header, append(bytes.Join(encoded, []byte("\n")), '\n')
+	}
+
+	header.Set(contentTypeKey, contentTypeValue)
+	if events {
+		header.Set(contentTypeKey, contentTypeBatch)
+	}
+
+	body := append([]byte("["), bytes.Join(encoded, []byte(","))...)
+
+	return header, append(body, ']')
+}
+
+// encode returns headers and body of a request for message
+// message, that is not a CloudEvent, is sent as plain JSON
+func (w *Webhook) encode(message []byte) (http.Header, []byte) {
+	header := http.Header{}
+
+	var event cloudEvent
+	if err := json.Unmarshal(message, &event); err != nil || event.SpecVersion == "" {
+		header.Set(contentTypeKey, contentTypeValue)
+		return header, message
+	}
+
+	if !w.binary {
+		header.Set(contentTypeKey, contentTypeCloudEvent)
+		return header, message
+	}
+
+	// in binary mode attributes are sent as headers, and data is a body
+	for name, value := range map[string]string{
+		"specversion": event.SpecVersion,
+		"id":          event.ID,
+		"source":      event.Source,
+		"type":        event.Type,
+		"time":        event.Time,
+		"subject":     event.Subject,
+		"dataschema":  event.DataSchema,
+	} {
+		if value != "" {
+			header.Set(ceHeaderPrefix+name, value)
+		}
+	}
+
+	contentType := event.DataContentType
+	if contentType == "" {
+		contentType = contentTypeValue
+	}
+
+	header.Set(contentTypeKey, contentType)
+
+	return header, event.Data
+}
+
+// post sends message to one consumer
+func (w *Webhook) post(ctx context.Context, consumer string, header http.Header, message []byte) Result {
+	result, _ := w.request(ctx, consumer, header, message)
+
+	return result
+}
+
+// request sends message to one consumer and returns result with a body of successful response
+func (w *Webhook) request(ctx context.Context, consumer string, header http.Header, message []byte) (Result, []byte) {
+	result := Result{Consumer: consumer}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, consumer, bytes.NewReader(message))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create request, error: %w", err)
+		return result, nil
+	}
+
+	req.Header = header.Clone()
+	signature.SetHeaders(req.Header, cont.EventID(ctx), w.consumerSecrets(consumer), time.Now().Unix(), message)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		result.Err = fmt.Errorf("request failed, error: %w", err)
+		return result, nil
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	result.StatusCode = resp.StatusCode
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		result.Err = fmt.Errorf("%w, status code: %d", ErrUnexpectedStatus, resp.StatusCode)
+		return result, nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+
+	return result, body
+}
+
+// consumerSecrets returns signing secrets of consumer
+func (w *Webhook) consumerSecrets(consumer string) []string {
+	if w.secrets == nil {
+		return nil
+	}
+
+	return w.secrets.Secrets(consumer)
+}
@@ -0,0 +1,287 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/notifier/signature"
+	mock_webhook "github.com/faceit/test/notifier/webhook/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testMessage = []byte(`{"action":"CREATE"}`)
+	testData    = []byte(`{"id":1,"firstName":"David"}`)
+	testEvent   = []byte(`{"specversion":"1.0","id":"event_id","source":"/v1/user","type":"user.created",` +
+		`"time":"2021-07-05T12:00:00Z","subject":"1","datacontenttype":"application/json",` +
+		`"dataschema":"/schemas/user/v1.json","data":` + string(testData) + `}`)
+)
+
+func newTestServer(t *testing.T, status int, received chan<- []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, contentTypeValue, r.Header.Get(contentTypeKey))
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+
+		if received != nil {
+			received <- body
+		}
+
+		w.WriteHeader(status)
+	}))
+}
+
+func TestSend(t *testing.T) {
+	t.Run("positive_signed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := cont.SetEventID(context.Background(), "event_id")
+
+		secrets := []string{"current_secret", "previous_secret"}
+		headers := make(chan http.Header, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			// consumer knows only one of secrets during rotation
+			assert.Nil(t, signature.Verify(r.Header, body, time.Minute, secrets[1]))
+
+			headers <- r.Header
+		}))
+		defer server.Close()
+
+		mockSecrets := mock_webhook.NewMocksecrets(ctr)
+		mockSecrets.EXPECT().Secrets(server.URL).Return(secrets)
+
+		err := New(http.DefaultClient, mockSecrets, false).Send(ctx, []string{server.URL}, testMessage)
+		assert.Nil(t, err)
+
+		h := <-headers
+		assert.Equal(t, "event_id", h.Get(signature.HeaderEventID))
+		assert.NotEmpty(t, h.Get(signature.HeaderTimestamp))
+	})
+
+	t.Run("positive_cloudevent_structured", func(t *testing.T) {
+		requests := make(chan *http.Request, 1)
+		received := make(chan []byte, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			requests <- r
+			received <- body
+		}))
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, false).Send(context.Background(), []string{server.URL}, testEvent)
+		assert.Nil(t, err)
+
+		r := <-requests
+		assert.Equal(t, contentTypeCloudEvent, r.Header.Get(contentTypeKey))
+		assert.Empty(t, r.Header.Get("ce-id"))
+		assert.Equal(t, testEvent, <-received)
+	})
+
+	t.Run("positive_cloudevent_binary", func(t *testing.T) {
+		requests := make(chan *http.Request, 1)
+		received := make(chan []byte, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			requests <- r
+			received <- body
+		}))
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, true).Send(context.Background(), []string{server.URL}, testEvent)
+		assert.Nil(t, err)
+
+		r := <-requests
+		assert.Equal(t, "application/json", r.Header.Get(contentTypeKey))
+
+		for name, value := range map[string]string{
+			"ce-specversion": "1.0",
+			"ce-id":          "event_id",
+			"ce-source":      "/v1/user",
+			"ce-type":        "user.created",
+			"ce-time":        "2021-07-05T12:00:00Z",
+			"ce-subject":     "1",
+			"ce-dataschema":  "/schemas/user/v1.json",
+		} {
+			assert.Equal(t, value, r.Header.Get(name), name)
+		}
+
+		assert.Equal(t, testData, <-received)
+	})
+
+	t.Run("positive_binary_plain_json", func(t *testing.T) {
+		received := make(chan []byte, 1)
+
+		server := newTestServer(t, http.StatusOK, received)
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, true).Send(context.Background(), []string{server.URL}, testMessage)
+		assert.Nil(t, err)
+
+		assert.Equal(t, testMessage, <-received)
+	})
+
+	t.Run("positive_all_consumers", func(t *testing.T) {
+		received := make(chan []byte, 2)
+
+		first := newTestServer(t, http.StatusOK, received)
+		defer first.Close()
+
+		second := newTestServer(t, http.StatusNoContent, received)
+		defer second.Close()
+
+		err := New(http.DefaultClient, nil, false).Send(context.Background(), []string{first.URL, second.URL}, testMessage)
+		assert.Nil(t, err)
+
+		assert.Equal(t, testMessage, <-received)
+		assert.Equal(t, testMessage, <-received)
+	})
+
+	t.Run("positive_no_consumers", func(t *testing.T) {
+		err := New(http.DefaultClient, nil, false).Send(context.Background(), nil, testMessage)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative_one_consumer_failed", func(t *testing.T) {
+		ok := newTestServer(t, http.StatusOK, nil)
+		defer ok.Close()
+
+		failed := newTestServer(t, http.StatusInternalServerError, nil)
+		defer failed.Close()
+
+		err := New(http.DefaultClient, nil, false).Send(context.Background(), []string{ok.URL, failed.URL}, testMessage)
+
+		var sendErr *Error
+		assert.True(t, errors.As(err, &sendErr))
+		assert.Equal(t, []string{failed.URL}, sendErr.Failed())
+		assert.Equal(t, http.StatusOK, sendErr.Results[0].StatusCode)
+		assert.Nil(t, sendErr.Results[0].Err)
+		assert.Equal(t, http.StatusInternalServerError, sendErr.Results[1].StatusCode)
+		assert.True(t, errors.Is(sendErr.Results[1].Err, ErrUnexpectedStatus))
+	})
+
+	t.Run("negative_unreachable_consumer", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, nil)
+		server.Close()
+
+		err := New(http.DefaultClient, nil, false).Send(context.Background(), []string{server.URL}, testMessage)
+
+		var sendErr *Error
+		assert.True(t, errors.As(err, &sendErr))
+		assert.Equal(t, []string{server.URL}, sendErr.Failed())
+	})
+
+	t.Run("negative_context_deadline", func(t *testing.T) {
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := New(http.DefaultClient, nil, false).Send(ctx, []string{server.URL}, testMessage)
+
+		var sendErr *Error
+		assert.True(t, errors.As(err, &sendErr))
+		assert.True(t, errors.Is(sendErr.Results[0].Err, context.DeadlineExceeded))
+	})
+}
+
+func TestSendBatch(t *testing.T) {
+	items := []entity.BatchItem{
+		{ID: "event_id", Message: testEvent},
+		{ID: "other_id", Message: testMessage},
+	}
+
+	t.Run("positive_json", func(t *testing.T) {
+		requests := make(chan *http.Request, 1)
+		received := make(chan []byte, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			requests <- r
+			received <- body
+		}))
+		defer server.Close()
+
+		// batch is a JSON array of structured events
+		err := New(http.DefaultClient, nil, true).SendBatch(context.Background(), server.URL, entity.BatchFormatJSON, items[:1])
+		assert.Nil(t, err)
+
+		r := <-requests
+		assert.Equal(t, contentTypeBatch, r.Header.Get(contentTypeKey))
+		assert.Equal(t, "["+string(testEvent)+"]", string(<-received))
+	})
+
+	t.Run("positive_ndjson", func(t *testing.T) {
+		requests := make(chan *http.Request, 1)
+		received := make(chan []byte, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			requests <- r
+			received <- body
+		}))
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, false).SendBatch(context.Background(), server.URL, entity.BatchFormatNDJSON, items)
+		assert.Nil(t, err)
+
+		// message, that is not an event, is wrapped with it's id
+		r := <-requests
+		assert.Equal(t, contentTypeNDJSON, r.Header.Get(contentTypeKey))
+		assert.Equal(t, string(testEvent)+"\n"+`{"id":"other_id","data":{"action":"CREATE"}}`+"\n", string(<-received))
+	})
+
+	t.Run("negative_items_rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"failed":["other_id"]}`))
+		}))
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, false).SendBatch(context.Background(), server.URL, entity.BatchFormatJSON, items)
+
+		var batchErr *BatchError
+		assert.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, []string{"other_id"}, batchErr.FailedItems())
+		assert.True(t, errors.Is(err, ErrItemsRejected))
+	})
+
+	t.Run("negative_batch_failed", func(t *testing.T) {
+		server := newTestServer(t, http.StatusInternalServerError, nil)
+		defer server.Close()
+
+		err := New(http.DefaultClient, nil, false).SendBatch(context.Background(), server.URL, entity.BatchFormatJSON, items[1:])
+
+		var batchErr *BatchError
+		assert.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, []string{"other_id"}, batchErr.FailedItems())
+		assert.True(t, errors.Is(err, ErrUnexpectedStatus))
+	})
+}
@@ -0,0 +1,110 @@
+//go:generate mockgen -source ../outbox/relay.go -destination ../outbox/mock/mock_relay.go
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+)
+
+// deliveryTimeout is a timeout of one record delivery
+const deliveryTimeout = time.Minute
+
+// claimLease is a time, record is claimed by a relay for,
+// after it, record claimed by failed or stopped relay is claimed again
+var claimLease = 2 * deliveryTimeout
+
+// store is an outbox store interface
+type store interface {
+	Claim(ctx context.Context, delay int, lease time.Duration, deliver func(entity.Outbox) error) (bool, error)
+}
+
+type notifier interface {
+	Do(ctx context.Context, consumers []string, message interface{}) error
+}
+
+// subscriptions resolves consumers of a notification
+type subscriptions interface {
+	Consumers(action string, countryID int) []string
+}
+
+// Relay is an outbox relay worker
+// it reads not delivered outbox records and hands them to notifier
+type Relay struct {
+	store         store
+	notifier      notifier
+	subscriptions subscriptions
+	interval      time.Duration
+	delay         int
+	batchSize     int
+	log           logger.Logger
+}
+
+// New creates new Relay instance
+func New(cfg config.Outbox, s store, n notifier, sub subscriptions, l logger.Logger) *Relay {
+	return &Relay{
+		store:         s,
+		notifier:      n,
+		subscriptions: sub,
+		interval:      time.Duration(cfg.PollInterval) * time.Second,
+		delay:         cfg.Delay,
+		batchSize:     cfg.BatchSize,
+		log:           l,
+	}
+}
+
+// Run relays pending outbox records every poll interval until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Relay(ctx)
+		}
+	}
+}
+
+// Relay sends one batch of pending outbox records
+// every record is claimed for claimLease, so records are relayed by one replica at a time,
+// record is marked as delivered only if notifier succeeded,
+// otherwise it would be picked up again after lease expires
+func (r *Relay) Relay(ctx context.Context) {
+	for i := 0; i < r.batchSize; i++ {
+		var relayErr error
+
+		claimed, err := r.store.Claim(ctx, r.delay, claimLease, func(record entity.Outbox) error {
+			relayErr = r.relay(ctx, record)
+			return relayErr
+		})
+		if err != nil && relayErr == nil {
+			r.log.Errorf(ctx, "failed to claim outbox record, error: %s", err)
+		}
+
+		if !claimed {
+			return
+		}
+	}
+}
+
+// relay hands one outbox record to notifier
+func (r *Relay) relay(ctx context.Context, record entity.Outbox) error {
+	ctx, cancel := context.WithTimeout(cont.SetEventID(ctx, record.EventID), deliveryTimeout)
+	defer cancel()
+
+	err := r.notifier.Do(ctx, r.subscriptions.Consumers(record.Action, record.CountryID), json.RawMessage(record.Payload))
+	if err != nil {
+		r.log.Warningf(ctx, "failed to relay outbox event %s, error: %s", record.EventID, err)
+	}
+
+	return err
+}
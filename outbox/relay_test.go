@@ -0,0 +1,179 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	mock_outbox "github.com/faceit/test/outbox/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testConfig = config.Outbox{
+		PollInterval: 1,
+		Delay:        30,
+		BatchSize:    10,
+	}
+
+	testCreateConsumers = []string{"create_consumer"}
+	testDeleteConsumers = []string{"delete_consumer"}
+
+	testCreateRecord = entity.Outbox{
+		ID:        1,
+		EventID:   "create_event",
+		Action:    entity.ActionCreate,
+		CountryID: 2,
+		Payload:   []byte(`{"action":"CREATE"}`),
+	}
+
+	testDeleteRecord = entity.Outbox{
+		ID:      2,
+		EventID: "delete_event",
+		Action:  entity.ActionDelete,
+		Payload: []byte(`{"action":"DELETE"}`),
+	}
+)
+
+// claimRecord returns a Claim stub, that passes record to deliver, as store does
+func claimRecord(record entity.Outbox, storeErr error) func(context.Context, int, time.Duration, func(entity.Outbox) error) (bool, error) {
+	return func(_ context.Context, _ int, _ time.Duration, deliver func(entity.Outbox) error) (bool, error) {
+		err := deliver(record)
+		if err != nil {
+			return true, err
+		}
+
+		return true, storeErr
+	}
+}
+
+func TestRelay(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_outbox.NewMockstore(ctr)
+		gomock.InOrder(
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testCreateRecord, nil)),
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testDeleteRecord, nil)),
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).Return(false, nil),
+		)
+
+		mockNotifier := mock_outbox.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testCreateConsumers, json.RawMessage(testCreateRecord.Payload)).DoAndReturn(
+			func(ctx context.Context, _ []string, _ interface{}) error {
+				assert.Equal(t, testCreateRecord.EventID, cont.EventID(ctx))
+				return nil
+			})
+		mockNotifier.EXPECT().Do(gomock.Any(), testDeleteConsumers, json.RawMessage(testDeleteRecord.Payload)).DoAndReturn(
+			func(ctx context.Context, _ []string, _ interface{}) error {
+				assert.Equal(t, testDeleteRecord.EventID, cont.EventID(ctx))
+				return nil
+			})
+
+		mockSubscriptions := mock_outbox.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionCreate, testCreateRecord.CountryID).Return(testCreateConsumers)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionDelete, 0).Return(testDeleteConsumers)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		New(testConfig, mockStore, mockNotifier, mockSubscriptions, logger.New(mockLogger)).Relay(ctx)
+	})
+
+	t.Run("positive_batch_size", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		cfg := testConfig
+		cfg.BatchSize = 1
+
+		// the next record is left for the next run
+		mockStore := mock_outbox.NewMockstore(ctr)
+		mockStore.EXPECT().Claim(ctx, cfg.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testCreateRecord, nil))
+
+		mockNotifier := mock_outbox.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testCreateConsumers, json.RawMessage(testCreateRecord.Payload)).Return(nil)
+
+		mockSubscriptions := mock_outbox.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionCreate, testCreateRecord.CountryID).Return(testCreateConsumers)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		New(cfg, mockStore, mockNotifier, mockSubscriptions, logger.New(mockLogger)).Relay(ctx)
+	})
+
+	t.Run("negative_notifier_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		// failed record stays leased, so the next one is claimed
+		mockStore := mock_outbox.NewMockstore(ctr)
+		gomock.InOrder(
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testCreateRecord, nil)),
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testDeleteRecord, nil)),
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).Return(false, nil),
+		)
+
+		mockNotifier := mock_outbox.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testCreateConsumers, json.RawMessage(testCreateRecord.Payload)).Return(errTest)
+		mockNotifier.EXPECT().Do(gomock.Any(), testDeleteConsumers, json.RawMessage(testDeleteRecord.Payload)).Return(nil)
+
+		mockSubscriptions := mock_outbox.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionCreate, testCreateRecord.CountryID).Return(testCreateConsumers)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionDelete, 0).Return(testDeleteConsumers)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(1)
+
+		New(testConfig, mockStore, mockNotifier, mockSubscriptions, logger.New(mockLogger)).Relay(ctx)
+	})
+
+	t.Run("negative_store_claim_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_outbox.NewMockstore(ctr)
+		mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).Return(false, errTest)
+
+		mockNotifier := mock_outbox.NewMocknotifier(ctr)
+
+		mockSubscriptions := mock_outbox.NewMocksubscriptions(ctr)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(1)
+
+		New(testConfig, mockStore, mockNotifier, mockSubscriptions, logger.New(mockLogger)).Relay(ctx)
+	})
+
+	t.Run("negative_store_delivered_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_outbox.NewMockstore(ctr)
+		gomock.InOrder(
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).DoAndReturn(claimRecord(testCreateRecord, errTest)),
+			mockStore.EXPECT().Claim(ctx, testConfig.Delay, claimLease, gomock.Any()).Return(false, nil),
+		)
+
+		mockNotifier := mock_outbox.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testCreateConsumers, json.RawMessage(testCreateRecord.Payload)).Return(nil)
+
+		mockSubscriptions := mock_outbox.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(entity.ActionCreate, testCreateRecord.CountryID).Return(testCreateConsumers)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(1)
+
+		New(testConfig, mockStore, mockNotifier, mockSubscriptions, logger.New(mockLogger)).Relay(ctx)
+	})
+}
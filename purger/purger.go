@@ -0,0 +1,85 @@
+//go:generate mockgen -source ../purger/purger.go -destination ../purger/mock/mock_purger.go
+
+package purger
+
+import (
+	"context"
+	"time"
+
+	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+)
+
+const (
+	// secondsInDay converts retention from config into seconds
+	secondsInDay = 24 * 60 * 60
+
+	// actor is written into audit of purged users
+	actor = "purger"
+)
+
+// store is a user store interface
+type store interface {
+	Purge(ctx context.Context, retention, limit int) ([]entity.User, error)
+}
+
+// Purger is a deleted users purger worker
+// it removes users, that were deleted more than retention period ago,
+// final notification of every purged user is sent by outbox relay
+type Purger struct {
+	store     store
+	interval  time.Duration
+	retention int
+	batchSize int
+	log       logger.Logger
+}
+
+// New creates new Purger instance
+func New(cfg config.Purge, s store, l logger.Logger) *Purger {
+	return &Purger{
+		store:     s,
+		interval:  time.Duration(cfg.PollInterval) * time.Second,
+		retention: cfg.Retention * secondsInDay,
+		batchSize: cfg.BatchSize,
+		log:       l,
+	}
+}
+
+// Run purges users every poll interval until ctx is done
+func (p *Purger) Run(ctx context.Context) {
+	ctx = cont.SetActor(ctx, actor)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Purge(ctx)
+		}
+	}
+}
+
+// Purge removes users past retention period in batches, until there are no more of them
+// every batch is removed in it's own transaction, so failed batch does not roll back the previous ones
+func (p *Purger) Purge(ctx context.Context) {
+	for ctx.Err() == nil {
+		users, err := p.store.Purge(ctx, p.retention, p.batchSize)
+		if err != nil {
+			p.log.Errorf(ctx, "failed to purge deleted users, error: %s", err)
+			return
+		}
+
+		if len(users) > 0 {
+			p.log.Infof(ctx, "purged %d deleted user(s)", len(users))
+		}
+
+		if len(users) < p.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package purger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	mock_purger "github.com/faceit/test/purger/mock"
+	"github.com/golang/mock/gomock"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testConfig = config.Purge{
+		PollInterval: 1,
+		Retention:    30,
+		BatchSize:    2,
+	}
+
+	// retention is passed to store in seconds
+	testRetention = 30 * 24 * 60 * 60
+
+	testUsers = []entity.User{{ID: 1}, {ID: 2}}
+)
+
+func TestPurge(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_purger.NewMockstore(ctr)
+		mockStore.EXPECT().Purge(ctx, testRetention, testConfig.BatchSize).Return(testUsers[:1], nil)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+		New(testConfig, mockStore, logger.New(mockLogger)).Purge(ctx)
+	})
+
+	t.Run("positive_several_batches", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		// full batch means, that there could be more users to purge
+		mockStore := mock_purger.NewMockstore(ctr)
+		gomock.InOrder(
+			mockStore.EXPECT().Purge(ctx, testRetention, testConfig.BatchSize).Return(testUsers, nil),
+			mockStore.EXPECT().Purge(ctx, testRetention, testConfig.BatchSize).Return([]entity.User{}, nil),
+		)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+		New(testConfig, mockStore, logger.New(mockLogger)).Purge(ctx)
+	})
+
+	t.Run("positive_nothing_to_purge", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_purger.NewMockstore(ctr)
+		mockStore.EXPECT().Purge(ctx, testRetention, testConfig.BatchSize).Return([]entity.User{}, nil)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		New(testConfig, mockStore, logger.New(mockLogger)).Purge(ctx)
+	})
+
+	t.Run("negative_store_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockStore := mock_purger.NewMockstore(ctr)
+		mockStore.EXPECT().Purge(ctx, testRetention, testConfig.BatchSize).Return(nil, errTest)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+
+		New(testConfig, mockStore, logger.New(mockLogger)).Purge(ctx)
+	})
+
+	t.Run("negative_context_done", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		mockStore := mock_purger.NewMockstore(ctr)
+		mockLogger := mock_logger.NewMocklog(ctr)
+
+		New(testConfig, mockStore, logger.New(mockLogger)).Purge(ctx)
+	})
+}
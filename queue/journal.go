@@ -0,0 +1,381 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/faceit/test/entity"
+)
+
+// journal constants
+const (
+	opAdd = "add"
+	opAck = "ack"
+
+	segmentExt         = ".log"
+	segmentNamePattern = "%020d" + segmentExt
+	maxRecordSize      = 10 * 1024 * 1024
+)
+
+// record is a journal line
+type record struct {
+	Op        string          `json:"op"`
+	Seq       uint64          `json:"seq"`
+	ID        string          `json:"id,omitempty"`
+	Key       string          `json:"key,omitempty"`
+	Message   json.RawMessage `json:"message,omitempty"`
+	Consumers []string        `json:"consumers,omitempty"`
+}
+
+// item is a queued message with it's journal sequence number
+// seq is 0, if message was not journaled
+type item struct {
+	seq     uint64
+	tracked uint64
+	message entity.NotifierMessage
+}
+
+// journal is an append only segment log on local disk
+// every added message is written as "add" record and every processed one as "ack" record into the same segment,
+// so ack is never removed earlier, than it's add. Segment is removed, once all messages added into it are acknowledged
+type journal struct {
+	mu          *sync.Mutex
+	dir         string
+	segmentSize int
+	seq         uint64
+	file        *os.File
+	segment     string
+	written     int
+	pending     map[string]map[uint64]struct{}
+	segments    map[uint64]string
+}
+
+// openJournal opens journal in dir, and returns all not acknowledged messages
+// in order, they were added
+func openJournal(dir string, segmentSize int) (*journal, []item, error) {
+	err := os.MkdirAll(dir, 0750)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create journal dir, error: %w", err)
+	}
+
+	j := &journal{
+		mu:          &sync.Mutex{},
+		dir:         dir,
+		segmentSize: segmentSize,
+		pending:     make(map[string]map[uint64]struct{}),
+		segments:    make(map[uint64]string),
+	}
+
+	items, err := j.replay()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = j.rotate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return j, items, nil
+}
+
+// append journals message and returns it's sequence number
+func (j *journal) append(message entity.NotifierMessage) (uint64, error) {
+	body, err := json.Marshal(message.Message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal message, error: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.written >= j.segmentSize {
+		err = j.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	seq := j.seq + 1
+
+	err = j.write(record{Op: opAdd, Seq: seq, ID: message.ID, Key: message.Key, Message: body, Consumers: message.Consumers})
+	if err != nil {
+		return 0, err
+	}
+
+	j.seq = seq
+	j.track(seq, j.segment)
+
+	return seq, nil
+}
+
+// ack acknowledges message with seq, so it would not be replayed
+// ack is written into segment, message was added into, the last ack of previous segment is not written,
+// as segment is removed instead
+func (j *journal) ack(seq uint64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	segment, ok := j.segments[seq]
+	if !ok {
+		return nil
+	}
+
+	// all messages from previous segment are processed, so segment is not needed anymore
+	if len(j.pending[segment]) == 1 && segment != j.segment {
+		err := os.Remove(filepath.Join(j.dir, segment))
+		if err != nil {
+			return fmt.Errorf("failed to remove segment %s, error: %w", segment, err)
+		}
+
+		delete(j.segments, seq)
+		delete(j.pending, segment)
+
+		return nil
+	}
+
+	var err error
+
+	if segment == j.segment {
+		err = j.write(record{Op: opAck, Seq: seq})
+	} else {
+		err = j.writeClosed(segment, record{Op: opAck, Seq: seq})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	delete(j.segments, seq)
+	delete(j.pending[segment], seq)
+
+	return nil
+}
+
+// close closes current segment
+func (j *journal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
+
+// replay reads all segments and returns not acknowledged messages
+// segments without not acknowledged messages are removed
+func (j *journal) replay() ([]item, error) {
+	files, err := ioutil.ReadDir(j.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal dir, error: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), segmentExt) {
+			names = append(names, f.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	added := make(map[uint64]record)
+
+	for _, name := range names {
+		j.pending[name] = make(map[uint64]struct{})
+
+		err = j.read(name, added)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]item, 0, len(added))
+
+	for seq, r := range added {
+		items = append(items, item{
+			seq: seq,
+			message: entity.NotifierMessage{
+				ID:        r.ID,
+				Key:       r.Key,
+				Message:   r.Message,
+				Consumers: r.Consumers,
+			},
+		})
+	}
+
+	sort.Slice(items, func(a, b int) bool {
+		return items[a].seq < items[b].seq
+	})
+
+	for _, name := range names {
+		if len(j.pending[name]) != 0 {
+			continue
+		}
+
+		delete(j.pending, name)
+
+		err = os.Remove(filepath.Join(j.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove segment %s, error: %w", name, err)
+		}
+	}
+
+	return items, nil
+}
+
+// read reads one segment into added, removing acknowledged messages from it
+// incomplete last record (ak service crashed during write) is skipped
+func (j *journal) read(name string, added map[uint64]record) error {
+	f, err := os.Open(filepath.Join(j.dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to open segment %s, error: %w", name, err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxRecordSize)
+
+	for scanner.Scan() {
+		var r record
+
+		err = json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil {
+			continue
+		}
+
+		if r.Seq > j.seq {
+			j.seq = r.Seq
+		}
+
+		switch r.Op {
+		case opAdd:
+			added[r.Seq] = r
+			j.track(r.Seq, name)
+		case opAck:
+			delete(added, r.Seq)
+
+			if segment, ok := j.segments[r.Seq]; ok {
+				delete(j.pending[segment], r.Seq)
+				delete(j.segments, r.Seq)
+			}
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read segment %s, error: %w", name, err)
+	}
+
+	return nil
+}
+
+// rotate closes current segment and starts a new one
+func (j *journal) rotate() error {
+	if j.file != nil {
+		err := j.file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to close segment %s, error: %w", j.segment, err)
+		}
+
+		// all messages from closed segment could be already processed
+		if len(j.pending[j.segment]) == 0 {
+			delete(j.pending, j.segment)
+
+			err = os.Remove(filepath.Join(j.dir, j.segment))
+			if err != nil {
+				return fmt.Errorf("failed to remove segment %s, error: %w", j.segment, err)
+			}
+		}
+	}
+
+	name := fmt.Sprintf(segmentNamePattern, j.seq+1)
+
+	f, err := os.OpenFile(filepath.Join(j.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open segment %s, error: %w", name, err)
+	}
+
+	j.file = f
+	j.segment = name
+	j.written = 0
+
+	if _, ok := j.pending[name]; !ok {
+		j.pending[name] = make(map[uint64]struct{})
+	}
+
+	return nil
+}
+
+// write appends a record to current segment and syncs it to disk
+func (j *journal) write(r record) error {
+	err := writeRecord(j.file, r, false)
+	if err != nil {
+		return err
+	}
+
+	j.written++
+
+	return nil
+}
+
+// writeClosed appends a record to previous segment and syncs it to disk
+// segment could end with incomplete record, if service crashed during write, so record starts from a new line
+func (j *journal) writeClosed(segment string, r record) error {
+	f, err := os.OpenFile(filepath.Join(j.dir, segment), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open segment %s, error: %w", segment, err)
+	}
+
+	err = writeRecord(f, r, true)
+
+	if er := f.Close(); err == nil && er != nil {
+		err = fmt.Errorf("failed to close segment %s, error: %w", segment, er)
+	}
+
+	return err
+}
+
+// writeRecord writes record as a line into f and syncs it to disk, empty line is written before record, if newLine is set,
+// empty and incomplete lines are skipped on replay
+func writeRecord(f *os.File, r record, newLine bool) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal record, error: %w", err)
+	}
+
+	if newLine {
+		line = append([]byte{'\n'}, line...)
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write journal record, error: %w", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync journal, error: %w", err)
+	}
+
+	return nil
+}
+
+// track remembers, that message seq was added into segment and is not acknowledged yet
+func (j *journal) track(seq uint64, segment string) {
+	if _, ok := j.pending[segment]; !ok {
+		j.pending[segment] = make(map[uint64]struct{})
+	}
+
+	j.pending[segment][seq] = struct{}{}
+	j.segments[seq] = segment
+}
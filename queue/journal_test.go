@@ -0,0 +1,187 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testConsumers = []string{"test_consumer"}
+)
+
+func testMessage(id string) entity.NotifierMessage {
+	return entity.NotifierMessage{
+		ID:        id,
+		Message:   map[string]string{"id": id},
+		Consumers: testConsumers,
+	}
+}
+
+func segments(t *testing.T, dir string) []string {
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+
+	return names
+}
+
+func TestJournal(t *testing.T) {
+	t.Run("positive_replay_not_acknowledged", func(t *testing.T) {
+		dir := t.TempDir()
+
+		j, items, err := openJournal(dir, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, items)
+
+		first, err := j.append(testMessage("first"))
+		assert.Nil(t, err)
+
+		_, err = j.append(testMessage("second"))
+		assert.Nil(t, err)
+
+		_, err = j.append(testMessage("third"))
+		assert.Nil(t, err)
+
+		assert.Nil(t, j.ack(first))
+		assert.Nil(t, j.close())
+
+		j, items, err = openJournal(dir, 10)
+		assert.Nil(t, err)
+
+		assert.Len(t, items, 2)
+		assert.Equal(t, uint64(2), items[0].seq)
+		assert.Equal(t, "second", items[0].message.ID)
+		assert.Equal(t, testConsumers, items[0].message.Consumers)
+		assert.Equal(t, json.RawMessage(`{"id":"second"}`), items[0].message.Message)
+		assert.Equal(t, uint64(3), items[1].seq)
+		assert.Equal(t, "third", items[1].message.ID)
+
+		// sequence continues after replay
+		seq, err := j.append(testMessage("fourth"))
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(4), seq)
+		assert.Nil(t, j.close())
+	})
+
+	t.Run("positive_processed_segments_removed", func(t *testing.T) {
+		dir := t.TempDir()
+
+		j, _, err := openJournal(dir, 2)
+		assert.Nil(t, err)
+
+		for _, id := range []string{"first", "second", "third"} {
+			_, err = j.append(testMessage(id))
+			assert.Nil(t, err)
+		}
+
+		assert.Len(t, segments(t, dir), 2)
+
+		assert.Nil(t, j.ack(1))
+		assert.Nil(t, j.ack(2))
+
+		assert.Len(t, segments(t, dir), 1)
+
+		assert.Nil(t, j.ack(3))
+		assert.Nil(t, j.close())
+
+		_, items, err := openJournal(dir, 2)
+		assert.Nil(t, err)
+		assert.Empty(t, items)
+		assert.Len(t, segments(t, dir), 1)
+	})
+
+	t.Run("positive_ack_kept_with_add", func(t *testing.T) {
+		dir := t.TempDir()
+
+		j, _, err := openJournal(dir, 2)
+		assert.Nil(t, err)
+
+		for _, id := range []string{"first", "second", "third"} {
+			_, err = j.append(testMessage(id))
+			assert.Nil(t, err)
+		}
+
+		// ack of the first message goes into the first segment, so it is not removed with the second one
+		assert.Nil(t, j.ack(1))
+		assert.Nil(t, j.ack(3))
+
+		_, err = j.append(testMessage("fourth"))
+		assert.Nil(t, err)
+		assert.Nil(t, j.close())
+
+		_, items, err := openJournal(dir, 2)
+		assert.Nil(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, "second", items[0].message.ID)
+		assert.Equal(t, "fourth", items[1].message.ID)
+	})
+
+	t.Run("positive_ack_after_incomplete_record", func(t *testing.T) {
+		dir := t.TempDir()
+
+		j, _, err := openJournal(dir, 10)
+		assert.Nil(t, err)
+
+		_, err = j.append(testMessage("first"))
+		assert.Nil(t, err)
+
+		_, err = j.append(testMessage("second"))
+		assert.Nil(t, err)
+		assert.Nil(t, j.close())
+
+		f, err := os.OpenFile(filepath.Join(dir, j.segment), os.O_WRONLY|os.O_APPEND, 0600)
+		assert.Nil(t, err)
+
+		_, err = f.WriteString(`{"op":"add","seq":3,"id":"thi`)
+		assert.Nil(t, err)
+		assert.Nil(t, f.Close())
+
+		j, items, err := openJournal(dir, 10)
+		assert.Nil(t, err)
+		assert.Len(t, items, 2)
+
+		assert.Nil(t, j.ack(items[0].seq))
+		assert.Nil(t, j.close())
+
+		_, items, err = openJournal(dir, 10)
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, "second", items[0].message.ID)
+	})
+
+	t.Run("positive_incomplete_record_skipped", func(t *testing.T) {
+		dir := t.TempDir()
+
+		j, _, err := openJournal(dir, 10)
+		assert.Nil(t, err)
+
+		_, err = j.append(testMessage("first"))
+		assert.Nil(t, err)
+		assert.Nil(t, j.close())
+
+		f, err := os.OpenFile(filepath.Join(dir, j.segment), os.O_WRONLY|os.O_APPEND, 0600)
+		assert.Nil(t, err)
+
+		_, err = f.WriteString(`{"op":"add","seq":2,"id":"sec`)
+		assert.Nil(t, err)
+		assert.Nil(t, f.Close())
+
+		_, items, err := openJournal(dir, 10)
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, "first", items[0].message.ID)
+	})
+}
@@ -6,6 +6,7 @@ package mock_queue
 
 import (
 	context "context"
+	entity "github.com/faceit/test/entity"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -34,9 +35,11 @@ func (m *Mocknotifier) EXPECT() *MocknotifierMockRecorder {
 }
 
 // Do mocks base method
-func (m *Mocknotifier) Do(ctx context.Context, consumers []string, message interface{}) {
+func (m *Mocknotifier) Do(ctx context.Context, consumers []string, message interface{}) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Do", ctx, consumers, message)
+	ret := m.ctrl.Call(m, "Do", ctx, consumers, message)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Do indicates an expected call of Do
@@ -44,3 +47,119 @@ func (mr *MocknotifierMockRecorder) Do(ctx, consumers, message interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*Mocknotifier)(nil).Do), ctx, consumers, message)
 }
+
+// Mockacknowledger is a mock of acknowledger interface
+type Mockacknowledger struct {
+	ctrl     *gomock.Controller
+	recorder *MockacknowledgerMockRecorder
+}
+
+// MockacknowledgerMockRecorder is the mock recorder for Mockacknowledger
+type MockacknowledgerMockRecorder struct {
+	mock *Mockacknowledger
+}
+
+// NewMockacknowledger creates a new mock instance
+func NewMockacknowledger(ctrl *gomock.Controller) *Mockacknowledger {
+	mock := &Mockacknowledger{ctrl: ctrl}
+	mock.recorder = &MockacknowledgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Mockacknowledger) EXPECT() *MockacknowledgerMockRecorder {
+	return m.recorder
+}
+
+// Delivered mocks base method
+func (m *Mockacknowledger) Delivered(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delivered", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delivered indicates an expected call of Delivered
+func (mr *MockacknowledgerMockRecorder) Delivered(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delivered", reflect.TypeOf((*Mockacknowledger)(nil).Delivered), ctx, id)
+}
+
+// MockBackend is a mock of Backend interface
+type MockBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackendMockRecorder
+}
+
+// MockBackendMockRecorder is the mock recorder for MockBackend
+type MockBackendMockRecorder struct {
+	mock *MockBackend
+}
+
+// NewMockBackend creates a new mock instance
+func NewMockBackend(ctrl *gomock.Controller) *MockBackend {
+	mock := &MockBackend{ctrl: ctrl}
+	mock.recorder = &MockBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBackend) EXPECT() *MockBackendMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method
+func (m *MockBackend) Add(ctx context.Context, message entity.NotifierMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add
+func (mr *MockBackendMockRecorder) Add(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBackend)(nil).Add), ctx, message)
+}
+
+// Retain mocks base method
+func (m *MockBackend) Retain(subs []entity.Subscription) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Retain", subs)
+}
+
+// Retain indicates an expected call of Retain
+func (mr *MockBackendMockRecorder) Retain(subs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockBackend)(nil).Retain), subs)
+}
+
+// Shutdown mocks base method
+func (m *MockBackend) Shutdown(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockBackendMockRecorder) Shutdown(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockBackend)(nil).Shutdown), ctx)
+}
+
+// Stats mocks base method
+func (m *MockBackend) Stats(ctx context.Context) (entity.QueueStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(entity.QueueStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats
+func (mr *MockBackendMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockBackend)(nil).Stats), ctx)
+}
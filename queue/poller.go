@@ -0,0 +1,205 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/faceit/test/entity"
+)
+
+// errAbandoned is returned by poller.deliver, if message was not delivered because of shutdown
+var errAbandoned = errors.New("delivery abandoned")
+
+// claimer claims one message from a shared backend and passes it to deliver
+// message stays in backend, if deliver fails. false is returned, if there is no message to claim
+type claimer func(ctx context.Context, deliver func(entity.NotifierMessage) error) (bool, error)
+
+// poller is a pool of workers, delivering messages from a backend shared by many replicas
+// workers claim messages one by one, and wait for wake up or poll interval, when backend is empty
+type poller struct {
+	mu        *sync.RWMutex
+	closed    bool
+	stop      chan struct{}
+	wake      chan struct{}
+	interval  time.Duration
+	workers   *sync.WaitGroup
+	ctx       context.Context
+	cancel    context.CancelFunc
+	abandoned *int64
+	claim     claimer
+	notifier  notifier
+	ack       acknowledger
+	counters  *counters
+}
+
+// newPoller creates poller and starts workers
+func newPoller(workers int, interval time.Duration, c claimer, n notifier, a acknowledger) *poller {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &poller{
+		mu:        &sync.RWMutex{},
+		stop:      make(chan struct{}),
+		wake:      make(chan struct{}, 1),
+		interval:  interval,
+		workers:   &sync.WaitGroup{},
+		ctx:       ctx,
+		cancel:    cancel,
+		abandoned: new(int64),
+		claim:     c,
+		notifier:  n,
+		ack:       a,
+		counters:  newCounters(),
+	}
+
+	for w := 0; w < workers; w++ {
+		p.workers.Add(1)
+
+		go p.work()
+	}
+
+	return p
+}
+
+// add calls enqueue for message, unless poller is closed, and wakes up a worker
+func (p *poller) add(ctx context.Context, message entity.NotifierMessage, enqueue func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return entity.ErrQueueClosed
+	}
+
+	err := enqueue()
+	if err != nil {
+		return err
+	}
+
+	p.counters.added(message)
+	p.notify()
+
+	return nil
+}
+
+// notify wakes up one waiting worker
+func (p *poller) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Retain removes enqueued counters of consumers, that are not subscribed by subs anymore
+func (p *poller) Retain(subs []entity.Subscription) {
+	p.counters.retain(subs)
+}
+
+// Shutdown stops workers, waiting for deliveries in progress until ctx is done
+// queued messages stay in backend for other replicas, so only cancelled deliveries are abandoned
+func (p *poller) Shutdown(ctx context.Context) (int, error) {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+		return 0, entity.ErrQueueClosed
+	}
+
+	p.closed = true
+	close(p.stop)
+
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+
+		p.cancel()
+		<-done
+	}
+
+	p.cancel()
+
+	return int(atomic.LoadInt64(p.abandoned)), err
+}
+
+// work claims and delivers messages until poller is stopped
+func (p *poller) work() {
+	defer p.workers.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		found, err := p.claim(p.ctx, p.deliver)
+		if found && err == nil {
+			// there could be more messages, so one more worker is woken up
+			p.notify()
+			continue
+		}
+
+		if err != nil && !errors.Is(err, errAbandoned) {
+			p.counters.failed(err)
+		}
+
+		// backend is empty or failed, waiting before next attempt
+		t := time.NewTimer(p.interval)
+
+		select {
+		case <-p.stop:
+			t.Stop()
+			return
+		case <-p.wake:
+		case <-t.C:
+		}
+
+		t.Stop()
+	}
+}
+
+// deliver sends message to notifier
+// errAbandoned is returned, if delivery is cancelled by shutdown, so message is kept in backend
+func (p *poller) deliver(message entity.NotifierMessage) error {
+	if p.ctx.Err() != nil {
+		atomic.AddInt64(p.abandoned, 1)
+		return errAbandoned
+	}
+
+	p.counters.started()
+
+	err := send(p.ctx, p.notifier, p.ack, message)
+	p.counters.finished(err)
+
+	if err != nil && p.ctx.Err() != nil {
+		atomic.AddInt64(p.abandoned, 1)
+		return errAbandoned
+	}
+
+	return nil
+}
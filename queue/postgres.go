@@ -0,0 +1,93 @@
+//go:generate mockgen -source ../queue/postgres.go -destination ../queue/mock/mock_postgres.go
+
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+
+	"github.com/lib/pq"
+)
+
+// storage is a shared queue store interface
+type storage interface {
+	Enqueue(ctx context.Context, message entity.NotifierMessage, capacity int) error
+	Claim(ctx context.Context, ordered bool, lease time.Duration, deliver func(entity.NotifierMessage) error) (bool, error)
+	Stats(ctx context.Context) (int, time.Time, error)
+}
+
+// Postgres is a queue, stored in PostgreSQL table and shared by all replicas
+// messages are leased to workers with SKIP LOCKED, so every message is delivered by one worker,
+// workers are woken up by LISTEN/NOTIFY and poll table every PollInterval, in case notification is missed
+type Postgres struct {
+	*poller
+	store    storage
+	capacity int
+}
+
+// NewPostgres creates new Postgres queue and starts GoRoutinesSize workers
+// wake is a channel of notifications about inserted messages (see pq.Listener), it could be nil
+func NewPostgres(cfg config.Queue, s storage, wake <-chan *pq.Notification, n notifier, a acknowledger) *Postgres {
+	q := &Postgres{
+		store:    s,
+		capacity: cfg.QueueSize,
+	}
+
+	claim := func(ctx context.Context, deliver func(entity.NotifierMessage) error) (bool, error) {
+		return s.Claim(ctx, cfg.Ordered, claimLease, deliver)
+	}
+
+	q.poller = newPoller(cfg.GoRoutinesSize, time.Duration(cfg.PollInterval)*time.Second, claim, n, a)
+
+	if wake != nil {
+		go q.listen(wake)
+	}
+
+	return q
+}
+
+// Add inserts message into queue table
+// entity.ErrQueueFull is returned, if there are QueueSize messages in table already
+func (q *Postgres) Add(ctx context.Context, message entity.NotifierMessage) error {
+	return q.add(ctx, message, func() error {
+		return q.store.Enqueue(ctx, message, q.capacity)
+	})
+}
+
+// Stats returns number of messages in queue table, including ones in delivery by other replicas,
+// and age of the oldest one
+func (q *Postgres) Stats(ctx context.Context) (entity.QueueStats, error) {
+	count, oldest, err := q.store.Stats(ctx)
+	if err != nil {
+		return entity.QueueStats{}, err
+	}
+
+	s := q.counters.stats(oldest)
+
+	s.Backend = config.QueueBackendPostgres
+	s.Buffered = count
+	s.Capacity = q.capacity
+
+	return s, nil
+}
+
+// listen wakes up workers on every notification
+// nil notification is sent by pq.Listener after reconnect, when notifications could be missed,
+// so it wakes up workers as well
+func (q *Postgres) listen(wake <-chan *pq.Notification) {
+	for {
+		select {
+		case <-q.stop:
+			return
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+
+			q.notify()
+		}
+	}
+}
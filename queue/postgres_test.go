@@ -0,0 +1,136 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	queue_mock "github.com/faceit/test/queue/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgres(t *testing.T) {
+	t.Run("positive_wake_on_notification", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		message := testMessage("1")
+		message.Message = json.RawMessage(`{"id":"1"}`)
+
+		wake := make(chan *pq.Notification)
+		delivered := make(chan struct{})
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, message.Message).Return(nil)
+
+		mockAck := queue_mock.NewMockacknowledger(ctr)
+		mockAck.EXPECT().Delivered(gomock.Any(), message.ID).DoAndReturn(
+			func(context.Context, string) error {
+				close(delivered)
+				return nil
+			})
+
+		// table is empty on start, message is claimed after notification
+		mockStorage := queue_mock.NewMockstorage(ctr)
+		mockStorage.EXPECT().Claim(gomock.Any(), true, claimLease, gomock.Any()).Return(false, nil)
+		mockStorage.EXPECT().Claim(gomock.Any(), true, claimLease, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ bool, _ time.Duration, deliver func(entity.NotifierMessage) error) (bool, error) {
+				return true, deliver(message)
+			})
+		mockStorage.EXPECT().Claim(gomock.Any(), true, claimLease, gomock.Any()).Return(false, nil).AnyTimes()
+
+		q := NewPostgres(config.Queue{GoRoutinesSize: 1, PollInterval: 60, Ordered: true}, mockStorage, wake, mockNotifier, mockAck)
+
+		wake <- &pq.Notification{Channel: "notification_queue"}
+
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("message is not delivered")
+		}
+
+		abandoned, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, abandoned)
+	})
+
+	t.Run("negative_queue_full", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		mockStorage := queue_mock.NewMockstorage(ctr)
+		mockStorage.EXPECT().Claim(gomock.Any(), false, claimLease, gomock.Any()).Return(false, nil).AnyTimes()
+		mockStorage.EXPECT().Enqueue(gomock.Any(), testMessage("1"), 10).Return(entity.ErrQueueFull)
+
+		q := NewPostgres(config.Queue{QueueSize: 10, GoRoutinesSize: 1, PollInterval: 60}, mockStorage, nil,
+			queue_mock.NewMocknotifier(ctr), nil)
+
+		assert.ErrorIs(t, q.Add(context.Background(), testMessage("1")), entity.ErrQueueFull)
+
+		_, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive_stats", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		mockStorage := queue_mock.NewMockstorage(ctr)
+		mockStorage.EXPECT().Claim(gomock.Any(), false, claimLease, gomock.Any()).Return(false, nil).AnyTimes()
+		mockStorage.EXPECT().Enqueue(gomock.Any(), testMessage("1"), 10).Return(nil)
+		mockStorage.EXPECT().Stats(gomock.Any()).Return(3, time.Now().Add(-time.Minute), nil)
+
+		q := NewPostgres(config.Queue{QueueSize: 10, GoRoutinesSize: 1, PollInterval: 60}, mockStorage, nil,
+			queue_mock.NewMocknotifier(ctr), nil)
+
+		assert.Nil(t, q.Add(context.Background(), testMessage("1")))
+
+		// buffered messages are counted by table, enqueued ones by this replica
+		stats, err := q.Stats(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, config.QueueBackendPostgres, stats.Backend)
+		assert.Equal(t, 3, stats.Buffered)
+		assert.Equal(t, 10, stats.Capacity)
+		assert.InDelta(t, 60, stats.OldestAge, 5)
+		assert.Equal(t, []entity.ConsumerStats{{Consumer: testConsumers[0], Enqueued: 1}}, stats.Consumers)
+
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative_abandoned_on_deadline", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		started := make(chan struct{})
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ []string, _ interface{}) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		// abandoned message is returned to table
+		mockStorage := queue_mock.NewMockstorage(ctr)
+		mockStorage.EXPECT().Claim(gomock.Any(), false, claimLease, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ bool, _ time.Duration, deliver func(entity.NotifierMessage) error) (bool, error) {
+				err := deliver(testMessage("1"))
+				assert.ErrorIs(t, err, errAbandoned)
+				return true, err
+			})
+
+		q := NewPostgres(config.Queue{GoRoutinesSize: 1, PollInterval: 60}, mockStorage, nil, mockNotifier, nil)
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		abandoned, err := q.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, abandoned)
+	})
+}
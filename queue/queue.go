@@ -4,78 +4,335 @@ package queue
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/faceit/test/config"
+	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/entity"
 )
 
+// deliveryTimeout is a timeout of one message delivery
+const deliveryTimeout = time.Minute
+
+// claimLease is a time, message is claimed by a worker of shared backend for,
+// after it, message claimed by stopped replica is returned to queue
+var claimLease = 2 * deliveryTimeout
+
 type notifier interface {
-	Do(ctx context.Context, consumers []string, message interface{})
+	Do(ctx context.Context, consumers []string, message interface{}) error
+}
+
+// acknowledger marks message as delivered by it's id
+type acknowledger interface {
+	Delivered(ctx context.Context, id string) error
+}
+
+// Backend is a notification queue
+// Add queues message without waiting, Shutdown stops intake and waits for queued messages
+// to be delivered until ctx is done, number of abandoned messages is returned,
+// Stats returns queue state and counters of this instance,
+// Retain removes counters of consumers, that are not subscribed by subs anymore
+type Backend interface {
+	Add(ctx context.Context, message entity.NotifierMessage) error
+	Shutdown(ctx context.Context) (int, error)
+	Stats(ctx context.Context) (entity.QueueStats, error)
+	Retain(subs []entity.Subscription)
 }
 
-// Queue is a queue implementation
+// Queue is an in-process queue implementation
+// queued messages are delivered by a fixed pool of GoRoutinesSize workers
 type Queue struct {
-	addCh      chan struct{}
-	popCh      chan struct{}
-	addMessage chan entity.NotifierMessage
-	popMessage chan entity.NotifierMessage
+	mu         *sync.RWMutex
+	closed     bool
+	stop       chan struct{}
+	slots      chan struct{}
+	partitions []chan item
+	next       *uint32
+	abandoned  *int64
+	workers    *sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
 	notifier   notifier
+	ack        acknowledger
+	journal    *journal
+	counters   *counters
 }
 
 // New creates new queue
-func New(cfg config.Queue, n notifier) *Queue {
+// a is optional and is called for every successfully delivered message with an id
+// in persistent mode every message is journaled on disk, and messages,
+// that were not processed before service stopped, are queued again
+// in ordered mode messages are partitioned by key between GoRoutinesSize workers,
+// so messages with the same key are delivered one by one, and different keys are delivered in parallel
+func New(cfg config.Queue, n notifier, a acknowledger) (*Queue, error) {
+	workers := cfg.GoRoutinesSize
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	q := &Queue{
-		addCh:      make(chan struct{}, cfg.QueueSize),
-		popCh:      make(chan struct{}, cfg.GoRoutinesSize),
-		addMessage: make(chan entity.NotifierMessage, 1),
-		popMessage: make(chan entity.NotifierMessage, 1),
-		notifier:   n,
+		mu:        &sync.RWMutex{},
+		stop:      make(chan struct{}),
+		slots:     make(chan struct{}, cfg.QueueSize),
+		next:      new(uint32),
+		abandoned: new(int64),
+		workers:   &sync.WaitGroup{},
+		ctx:       ctx,
+		cancel:    cancel,
+		notifier:  n,
+		ack:       a,
+		counters:  newCounters(),
+	}
+
+	// every partition could hold the whole queue, so sending to it never blocks, once slot is taken
+	partitions := 1
+	if cfg.Ordered {
+		partitions = workers
+	}
+
+	q.partitions = make([]chan item, partitions)
+	for p := range q.partitions {
+		q.partitions[p] = make(chan item, cfg.QueueSize)
+	}
+
+	var replay []item
+
+	if cfg.Persistent {
+		j, items, err := openJournal(cfg.Dir, cfg.SegmentSize)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to open queue journal, error: %w", err)
+		}
+
+		q.journal = j
+		replay = items
+	}
+
+	for w := 0; w < workers; w++ {
+		q.workers.Add(1)
+
+		go q.work(q.partitions[w%partitions])
+	}
+
+	go q.replay(replay)
+
+	return q, nil
+}
+
+// Add adds message to queue
+// it does not wait for a free slot: entity.ErrQueueFull is returned, if queue is full,
+// and entity.ErrQueueClosed, if queue is shutting down
+// in persistent mode message is journaled before it is queued
+func (q *Queue) Add(ctx context.Context, message entity.NotifierMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return entity.ErrQueueClosed
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		return entity.ErrQueueFull
+	}
+
+	i := item{message: message}
+
+	if q.journal != nil {
+		// if journaling failed, message is still queued, but would not survive a restart
+		seq, err := q.journal.append(message)
+		if err == nil {
+			i.seq = seq
+		}
+	}
+
+	q.push(i)
+
+	return nil
+}
+
+// Shutdown stops accepting new messages and waits until queued ones are delivered
+// if ctx is done earlier, deliveries in progress are cancelled, and the rest of messages are not sent,
+// number of such abandoned messages is returned with ctx error.
+// Abandoned messages are left in journal and outbox, so they would be delivered later
+func (q *Queue) Shutdown(ctx context.Context) (int, error) {
+	q.mu.Lock()
+
+	if q.closed {
+		q.mu.Unlock()
+		return 0, entity.ErrQueueClosed
+	}
+
+	q.closed = true
+	close(q.stop)
+
+	// workers stop, once partitions are drained
+	for _, p := range q.partitions {
+		close(p)
+	}
+
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+
+		// cancelling deliveries, workers skip the rest of messages
+		q.cancel()
+		<-done
+	}
+
+	q.cancel()
+
+	if q.journal != nil {
+		_ = q.journal.close()
+	}
+
+	return int(atomic.LoadInt64(q.abandoned)), err
+}
+
+// Retain removes enqueued counters of consumers, that are not subscribed by subs anymore
+func (q *Queue) Retain(subs []entity.Subscription) {
+	q.counters.retain(subs)
+}
+
+// Stats returns number of queued messages, that are not taken by workers yet,
+// and age of the oldest undelivered message, including ones in delivery
+func (q *Queue) Stats(ctx context.Context) (entity.QueueStats, error) {
+	s := q.counters.stats(time.Time{})
+
+	s.Backend = config.QueueBackendMemory
+	s.Buffered = len(q.slots)
+	s.Capacity = cap(q.slots)
+
+	return s, nil
+}
+
+// replay queues messages, that were not processed before restart
+// there could be more of them, than queue size, so it waits for free slots
+func (q *Queue) replay(items []item) {
+	for _, i := range items {
+		// slot is awaited without the lock, so Shutdown is not blocked by full queue
+		select {
+		case q.slots <- struct{}{}:
+		case <-q.stop:
+			return
+		}
+
+		q.mu.RLock()
+
+		if q.closed {
+			q.mu.RUnlock()
+			<-q.slots
+
+			return
+		}
+
+		q.push(i)
+		q.mu.RUnlock()
 	}
+}
 
-	go q.pop()
-	go q.add()
+// push passes item with a taken slot to partition
+// message is passed synchronously, so messages of one key keep their order
+func (q *Queue) push(i item) {
+	q.counters.added(i.message)
+	i.tracked = q.counters.track(time.Now())
 
-	return q
+	q.partitions[q.partition(i.message.Key)] <- i
 }
 
-// Add adds new notifyReq to queue
-func (q Queue) Add(message entity.NotifierMessage) {
-	q.addCh <- struct{}{}
+// work delivers messages of partition p one by one
+func (q *Queue) work(p chan item) {
+	defer q.workers.Done()
+
+	for i := range p {
+		<-q.slots
 
-	go func(message entity.NotifierMessage) {
-		q.addMessage <- message
-	}(message)
+		q.deliver(i)
+	}
 }
 
-func (q Queue) add() {
-	for message := range q.addMessage {
-		<-q.addCh
+// partition returns partition of key
+// messages without key are spread between partitions evenly
+func (q *Queue) partition(key string) int {
+	n := uint32(len(q.partitions))
+	if n == 1 {
+		return 0
+	}
 
-		q.popMessage <- message
+	if key == "" {
+		return int(atomic.AddUint32(q.next, 1) % n)
 	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % n)
 }
 
-// pop pops item from queue
-func (q Queue) pop() {
-	for message := range q.popMessage {
-		q.popCh <- struct{}{}
+// deliver sends message to notifier and acknowledges it
+// message is abandoned, if queue shutdown deadline is exceeded before or during delivery
+func (q *Queue) deliver(i item) {
+	defer q.counters.untrack(i.tracked)
+
+	if q.ctx.Err() != nil {
+		atomic.AddInt64(q.abandoned, 1)
+		return
+	}
+
+	q.counters.started()
 
-		go func(message entity.NotifierMessage) {
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Minute))
-			defer cancel()
-			q.notifier.Do(ctx, message.Consumers, message.Message)
-			<-q.popCh
-		}(message)
+	err := send(q.ctx, q.notifier, q.ack, i.message)
+	q.counters.finished(err)
+
+	if err != nil && q.ctx.Err() != nil {
+		atomic.AddInt64(q.abandoned, 1)
+		return
+	}
+
+	if q.journal != nil && i.seq != 0 {
+		// if ack fails, message would be delivered once again after restart
+		_ = q.journal.ack(i.seq)
 	}
 }
 
-// Close closing queue add channel
-func (q Queue) Closed() {
-	for len(q.addCh) != 0 && len(q.popCh) != 0 {
+// send delivers message to notifier and marks it as delivered in ack
+func send(ctx context.Context, n notifier, a acknowledger, message entity.NotifierMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	// outbox event id is used as notification event id, so relayed duplicate has the same one
+	if message.ID != "" {
+		ctx = cont.SetEventID(ctx, message.ID)
+	}
 
+	err := n.Do(ctx, message.Consumers, message.Message)
+	if err == nil && a != nil && message.ID != "" {
+		// if ack fails, message would be redelivered by outbox relay
+		_ = a.Delivered(ctx, message.ID)
 	}
 
-	close(q.addMessage)
-	close(q.popMessage)
+	return err
 }
@@ -0,0 +1,248 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	queue_mock "github.com/faceit/test/queue/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdered(t *testing.T) {
+	t.Run("positive_fifo_per_key", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		keys := []string{"1", "2", "3"}
+		perKey := 10
+
+		mu := &sync.Mutex{}
+		delivered := make(map[string][]int)
+		inFlight := make(map[string]bool)
+		wg := &sync.WaitGroup{}
+		wg.Add(len(keys) * perKey)
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ []string, message interface{}) error {
+				m := message.(map[string]interface{})
+				key := m["key"].(string)
+
+				mu.Lock()
+				// the same key is never delivered concurrently
+				assert.False(t, inFlight[key])
+				inFlight[key] = true
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				inFlight[key] = false
+				delivered[key] = append(delivered[key], m["n"].(int))
+				mu.Unlock()
+
+				wg.Done()
+
+				return nil
+			}).Times(len(keys) * perKey)
+
+		q, err := New(config.Queue{QueueSize: 100, GoRoutinesSize: 4, Ordered: true}, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		for n := 0; n < perKey; n++ {
+			for _, key := range keys {
+				err = q.Add(context.Background(), entity.NotifierMessage{
+					ID:        fmt.Sprintf("%s_%d", key, n),
+					Key:       key,
+					Message:   map[string]interface{}{"key": key, "n": n},
+					Consumers: testConsumers,
+				})
+				assert.Nil(t, err)
+			}
+		}
+
+		wg.Wait()
+
+		abandoned, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, abandoned)
+
+		for _, key := range keys {
+			assert.Len(t, delivered[key], perKey)
+
+			for n, got := range delivered[key] {
+				assert.Equal(t, n, got, key)
+			}
+		}
+	})
+
+	t.Run("positive_partition_by_key", func(t *testing.T) {
+		q := Queue{partitions: make([]chan item, 4), next: new(uint32)}
+
+		// the same key always gets the same partition
+		assert.Equal(t, q.partition("user_1"), q.partition("user_1"))
+
+		// messages without key are spread between partitions
+		seen := make(map[int]struct{})
+		for i := 0; i < len(q.partitions); i++ {
+			seen[q.partition("")] = struct{}{}
+		}
+
+		assert.Len(t, seen, len(q.partitions))
+	})
+}
+
+func TestAdd(t *testing.T) {
+	t.Run("negative_queue_full", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		release := make(chan struct{})
+
+		// the only worker is busy with the first message, the second one fills the queue
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(context.Context, []string, interface{}) error {
+				<-release
+				return nil
+			}).Times(2)
+
+		q, err := New(config.Queue{QueueSize: 1, GoRoutinesSize: 1}, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		assert.Nil(t, q.Add(context.Background(), testMessage("1")))
+
+		// waiting for worker to take the first message
+		assert.Eventually(t, func() bool { return len(q.slots) == 0 }, time.Second, time.Millisecond)
+
+		assert.Nil(t, q.Add(context.Background(), testMessage("2")))
+		assert.ErrorIs(t, q.Add(context.Background(), testMessage("3")), entity.ErrQueueFull)
+
+		close(release)
+
+		abandoned, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, abandoned)
+
+		assert.ErrorIs(t, q.Add(context.Background(), testMessage("4")), entity.ErrQueueClosed)
+	})
+
+	t.Run("negative_context_done", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		q, err := New(config.Queue{QueueSize: 1, GoRoutinesSize: 1}, queue_mock.NewMocknotifier(ctr), nil)
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, q.Add(ctx, testMessage("1")), context.Canceled)
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("positive_drain", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).Return(nil).Times(3)
+
+		mockAck := queue_mock.NewMockacknowledger(ctr)
+		mockAck.EXPECT().Delivered(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+		q, err := New(config.Queue{QueueSize: 3, GoRoutinesSize: 1}, mockNotifier, mockAck)
+		assert.Nil(t, err)
+
+		for _, id := range []string{"1", "2", "3"} {
+			assert.Nil(t, q.Add(context.Background(), testMessage(id)))
+		}
+
+		abandoned, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, abandoned)
+	})
+
+	t.Run("negative_deadline_exceeded", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		// delivery is in progress until it's context is cancelled
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ []string, _ interface{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		q, err := New(config.Queue{QueueSize: 3, GoRoutinesSize: 1}, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		for _, id := range []string{"1", "2", "3"} {
+			assert.Nil(t, q.Add(context.Background(), testMessage(id)))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		abandoned, err := q.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 3, abandoned)
+	})
+}
+
+func TestStats(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+
+		// the only worker is busy with the first message, the second one is buffered
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(context.Context, []string, interface{}) error {
+				started <- struct{}{}
+				<-release
+				return errTest
+			}).Times(2)
+
+		q, err := New(config.Queue{QueueSize: 5, GoRoutinesSize: 1}, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		assert.Nil(t, q.Add(context.Background(), testMessage("1")))
+		<-started
+		assert.Nil(t, q.Add(context.Background(), testMessage("2")))
+
+		stats, err := q.Stats(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, config.QueueBackendMemory, stats.Backend)
+		assert.Equal(t, 1, stats.Buffered)
+		assert.Equal(t, 1, stats.InFlight)
+		assert.Equal(t, 5, stats.Capacity)
+		assert.Greater(t, stats.OldestAge, float64(0))
+		assert.Equal(t, []entity.ConsumerStats{{Consumer: testConsumers[0], Enqueued: 2}}, stats.Consumers)
+
+		close(release)
+
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		stats, err = q.Stats(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, stats.Buffered)
+		assert.Zero(t, stats.InFlight)
+		assert.Zero(t, stats.OldestAge)
+		assert.Equal(t, errTest.Error(), stats.LastError)
+		assert.NotNil(t, stats.LastErrorTime)
+
+		// counters of unsubscribed consumer are removed
+		q.Retain([]entity.Subscription{{URL: "http://other.test"}})
+
+		stats, err = q.Stats(context.Background())
+		assert.Nil(t, err)
+		assert.Empty(t, stats.Consumers)
+	})
+}
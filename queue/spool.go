@@ -0,0 +1,466 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+
+	"github.com/google/uuid"
+)
+
+// spool directories and file name parts
+const (
+	spoolTmp = "tmp"
+	spoolNew = "new"
+	spoolCur = "cur"
+
+	// spoolLock is a file, replicas lock spool directory with
+	spoolLock = "lock"
+
+	spoolExt       = ".json"
+	spoolSeparator = "_"
+)
+
+// Spool is a queue, stored as files in a directory, that could be shared by replicas
+// message is written into tmp and moved into new, once it is complete,
+// worker claims message by moving it into cur, and removes it after delivery.
+// Rename is atomic, so every message is claimed by one worker.
+// capacity is checked under flock of lock file, so it holds for replicas, that share directory.
+// file name starts with spool's sequence number, so messages are claimed in order they were added
+type Spool struct {
+	*poller
+	dir      string
+	capacity int
+	ordered  bool
+	mu       *sync.Mutex
+	seq      int64
+}
+
+// NewSpool creates new Spool in cfg.SpoolDir and starts GoRoutinesSize workers
+func NewSpool(cfg config.Queue, n notifier, a acknowledger) (*Spool, error) {
+	for _, d := range []string{spoolTmp, spoolNew, spoolCur} {
+		err := os.MkdirAll(filepath.Join(cfg.SpoolDir, d), 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spool directory, error: %w", err)
+		}
+	}
+
+	q := &Spool{
+		dir:      cfg.SpoolDir,
+		capacity: cfg.QueueSize,
+		ordered:  cfg.Ordered,
+		mu:       &sync.Mutex{},
+	}
+
+	// sequence continues after messages, that are in spool already, even if clock went back since they were added
+	for _, d := range []string{spoolNew, spoolCur} {
+		n, err := names(filepath.Join(cfg.SpoolDir, d))
+		if err != nil {
+			return nil, err
+		}
+
+		if seq, ok := nameSeq(lastName(n)); ok && seq > q.seq {
+			q.seq = seq
+		}
+	}
+
+	q.poller = newPoller(cfg.GoRoutinesSize, time.Duration(cfg.PollInterval)*time.Second, q.claim, n, a)
+
+	return q, nil
+}
+
+// Add writes message into spool
+// entity.ErrQueueFull is returned, if there are QueueSize messages in spool already
+func (q *Spool) Add(ctx context.Context, message entity.NotifierMessage) error {
+	return q.add(ctx, message, func() error {
+		return q.write(message)
+	})
+}
+
+// write writes message into tmp and moves it into new
+// messages are counted and moved under spool lock, so concurrent writes never exceed capacity
+func (q *Spool) write(message entity.NotifierMessage) error {
+	body, err := json.Marshal(message.Message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message, error: %w", err)
+	}
+
+	data, err := json.Marshal(record{Op: opAdd, ID: message.ID, Key: message.Key, Message: body, Consumers: message.Consumers})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spool record, error: %w", err)
+	}
+
+	name := strings.Join([]string{
+		fmt.Sprintf("%020d", q.next()),
+		uuid.New().String(),
+		keyHash(message.Key),
+	}, spoolSeparator) + spoolExt
+
+	tmp := filepath.Join(q.dir, spoolTmp, name)
+
+	// file is written before lock is taken, so other writers do not wait for sync
+	err = writeFileSync(tmp, data)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write spool file, error: %w", err)
+	}
+
+	unlock, err := q.lock()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	defer unlock()
+
+	queued, err := q.count()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if queued >= q.capacity {
+		_ = os.Remove(tmp)
+		return entity.ErrQueueFull
+	}
+
+	err = os.Rename(tmp, filepath.Join(q.dir, spoolNew, name))
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to move spool file, error: %w", err)
+	}
+
+	return nil
+}
+
+// claim moves the oldest message from new into cur and passes it to deliver
+// message is removed, if deliver succeeds, and is moved back into new otherwise
+// in ordered mode message is not claimed, while there is an earlier one with the same key
+func (q *Spool) claim(ctx context.Context, deliver func(entity.NotifierMessage) error) (bool, error) {
+	name, err := q.take()
+	if err != nil || name == "" {
+		return false, err
+	}
+
+	return true, q.process(name, deliver)
+}
+
+// take moves the oldest message, that could be claimed, from new into cur and returns it's name,
+// empty name is returned, if there is no such message.
+// new and cur are listed and changed only under spool lock, so message, that is moved between them,
+// is always seen in one of them, and it's key keeps the next messages waiting
+func (q *Spool) take() (string, error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	err = q.recover()
+	if err != nil {
+		return "", err
+	}
+
+	queued, err := names(filepath.Join(q.dir, spoolNew))
+	if err != nil {
+		return "", err
+	}
+
+	busy := make(map[string]struct{})
+
+	if q.ordered {
+		claimed, err := names(filepath.Join(q.dir, spoolCur))
+		if err != nil {
+			return "", err
+		}
+
+		for _, name := range claimed {
+			busy[nameKey(name)] = struct{}{}
+		}
+	}
+
+	for _, name := range queued {
+		key := nameKey(name)
+
+		if q.ordered && key != "" {
+			if _, ok := busy[key]; ok {
+				continue
+			}
+		}
+
+		cur := filepath.Join(q.dir, spoolCur, name)
+
+		err = os.Rename(filepath.Join(q.dir, spoolNew, name), cur)
+		if err != nil {
+			return "", fmt.Errorf("failed to claim spool file, error: %w", err)
+		}
+
+		// lease starts now, rename keeps modification time of file
+		now := time.Now()
+		_ = os.Chtimes(cur, now, now)
+
+		return name, nil
+	}
+
+	return "", nil
+}
+
+// release moves message, that failed to be delivered, from cur back into new
+func (q *Spool) release(name string) {
+	unlock, err := q.lock()
+	if err != nil {
+		// message is returned by recover after lease expires
+		return
+	}
+	defer unlock()
+
+	_ = os.Rename(filepath.Join(q.dir, spoolCur, name), filepath.Join(q.dir, spoolNew, name))
+}
+
+// process delivers claimed message
+func (q *Spool) process(name string, deliver func(entity.NotifierMessage) error) error {
+	cur := filepath.Join(q.dir, spoolCur, name)
+
+	data, err := ioutil.ReadFile(cur)
+	if err != nil {
+		return fmt.Errorf("failed to read spool file, error: %w", err)
+	}
+
+	var r record
+
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		// broken message would never be delivered
+		_ = os.Remove(cur)
+		return fmt.Errorf("failed to unmarshal spool file %s, error: %w", name, err)
+	}
+
+	err = deliver(entity.NotifierMessage{
+		ID:        r.ID,
+		Key:       r.Key,
+		Message:   r.Message,
+		Consumers: r.Consumers,
+	})
+	if err != nil {
+		q.release(name)
+		return err
+	}
+
+	err = os.Remove(cur)
+	if err != nil {
+		return fmt.Errorf("failed to remove spool file, error: %w", err)
+	}
+
+	return nil
+}
+
+// Stats returns number of messages in spool, including ones in delivery by other replicas,
+// and age of the oldest one, taken from it's file name
+func (q *Spool) Stats(ctx context.Context) (entity.QueueStats, error) {
+	var (
+		count  int
+		oldest time.Time
+	)
+
+	for _, d := range []string{spoolNew, spoolCur} {
+		n, err := names(filepath.Join(q.dir, d))
+		if err != nil {
+			return entity.QueueStats{}, err
+		}
+
+		count += len(n)
+
+		if len(n) == 0 {
+			continue
+		}
+
+		if t, ok := nameTime(n[0]); ok && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+
+	s := q.counters.stats(oldest)
+
+	s.Backend = config.QueueBackendSpool
+	s.Buffered = count
+	s.Capacity = q.capacity
+
+	return s, nil
+}
+
+// next returns sequence number of the next message, it is time of add in nanoseconds, unless clock went back,
+// or the previous message was added in the same nanosecond, then it is the previous number plus one,
+// so sequence always grows, and it still orders messages of replicas, that share spool, by time
+func (q *Spool) next() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seq := time.Now().UTC().UnixNano()
+	if seq <= q.seq {
+		seq = q.seq + 1
+	}
+
+	q.seq = seq
+
+	return seq
+}
+
+// recover moves messages, that were claimed by stopped replicas, back into new
+// it should be called under spool lock
+func (q *Spool) recover() error {
+	dir := filepath.Join(q.dir, spoolCur)
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory, error: %w", err)
+	}
+
+	for _, f := range files {
+		if time.Since(f.ModTime()) < claimLease {
+			continue
+		}
+
+		_ = os.Rename(filepath.Join(dir, f.Name()), filepath.Join(q.dir, spoolNew, f.Name()))
+	}
+
+	return nil
+}
+
+// lock takes exclusive flock of spool lock file and returns a function, that releases it
+// flock is held by open file, so it excludes workers of this replica, as well as other replicas
+func (q *Spool) lock() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(q.dir, spoolLock), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool lock, error: %w", err)
+	}
+
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock spool, error: %w", err)
+	}
+
+	return func() {
+		// closing file releases lock
+		_ = f.Close()
+	}, nil
+}
+
+// count returns number of queued and claimed messages
+func (q *Spool) count() (int, error) {
+	total := 0
+
+	for _, d := range []string{spoolNew, spoolCur} {
+		n, err := names(filepath.Join(q.dir, d))
+		if err != nil {
+			return 0, err
+		}
+
+		total += len(n)
+	}
+
+	return total, nil
+}
+
+// names returns sorted names of spool files in dir
+func names(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory, error: %w", err)
+	}
+
+	n := make([]string, 0, len(files))
+
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), spoolExt) {
+			n = append(n, f.Name())
+		}
+	}
+
+	sort.Strings(n)
+
+	return n, nil
+}
+
+// keyHash returns a file name safe hash of ordering key, or empty string, if key is empty
+func keyHash(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// nameKey returns key hash from spool file name
+func nameKey(name string) string {
+	parts := strings.Split(strings.TrimSuffix(name, spoolExt), spoolSeparator)
+	if len(parts) != 3 {
+		return ""
+	}
+
+	return parts[2]
+}
+
+// nameSeq returns sequence number of message from spool file name
+func nameSeq(name string) (int64, bool) {
+	seq, err := strconv.ParseInt(strings.SplitN(name, spoolSeparator, 2)[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return seq, true
+}
+
+// nameTime returns time, message was added at, from spool file name
+// it is approximate, as sequence number is ahead of time, if clock went back
+func nameTime(name string) (time.Time, bool) {
+	seq, ok := nameSeq(name)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, seq), true
+}
+
+// lastName returns the last of sorted names, or empty string, if there are none
+func lastName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	return names[len(names)-1]
+}
+
+// writeFileSync writes data into a new file and syncs it to disk
+func writeFileSync(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if er := f.Close(); err == nil {
+		err = er
+	}
+
+	return err
+}
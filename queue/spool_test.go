@@ -0,0 +1,372 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	queue_mock "github.com/faceit/test/queue/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpool(t *testing.T) {
+	t.Run("positive_deliver", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		delivered := make(chan interface{}, 1)
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ []string, message interface{}) error {
+				delivered <- message
+				return nil
+			})
+
+		mockAck := queue_mock.NewMockacknowledger(ctr)
+		mockAck.EXPECT().Delivered(gomock.Any(), "1").Return(nil)
+
+		q, err := NewSpool(config.Queue{QueueSize: 10, GoRoutinesSize: 2, SpoolDir: dir, PollInterval: 60}, mockNotifier, mockAck)
+		assert.Nil(t, err)
+
+		assert.Nil(t, q.Add(context.Background(), testMessage("1")))
+
+		select {
+		case message := <-delivered:
+			assert.JSONEq(t, `{"id":"1"}`, string(message.(json.RawMessage)))
+		case <-time.After(time.Second):
+			t.Fatal("message is not delivered")
+		}
+
+		abandoned, err := q.Shutdown(context.Background())
+		assert.Nil(t, err)
+		assert.Zero(t, abandoned)
+
+		// delivered message is removed from spool
+		for _, d := range []string{spoolNew, spoolCur, spoolTmp} {
+			assert.Empty(t, segments(t, filepath.Join(dir, d)))
+		}
+	})
+
+	t.Run("positive_shared_by_replicas", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		mu := &sync.Mutex{}
+		delivered := make(map[string]int)
+		wg := &sync.WaitGroup{}
+		wg.Add(10)
+
+		mockNotifier := queue_mock.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Do(gomock.Any(), testConsumers, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ []string, message interface{}) error {
+				mu.Lock()
+				delivered[string(message.(json.RawMessage))]++
+				mu.Unlock()
+
+				wg.Done()
+
+				return nil
+			}).Times(10)
+
+		cfg := config.Queue{QueueSize: 10, GoRoutinesSize: 2, SpoolDir: dir, PollInterval: 1}
+
+		first, err := NewSpool(cfg, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		second, err := NewSpool(cfg, mockNotifier, nil)
+		assert.Nil(t, err)
+
+		for _, id := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"} {
+			assert.Nil(t, first.Add(context.Background(), testMessage(id)))
+		}
+
+		wg.Wait()
+
+		_, err = first.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		_, err = second.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		// every message is delivered once
+		assert.Len(t, delivered, 10)
+
+		for _, n := range delivered {
+			assert.Equal(t, 1, n)
+		}
+	})
+
+	t.Run("positive_recover_stale", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		q, err := NewSpool(config.Queue{QueueSize: 10, GoRoutinesSize: 1, SpoolDir: dir, PollInterval: 60}, queue_mock.NewMocknotifier(ctr), nil)
+		assert.Nil(t, err)
+
+		// stopping workers, so recovered message stays in spool
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		// message was claimed by replica, that stopped long ago
+		name := "00000000000000000001_id_.json"
+		cur := filepath.Join(dir, spoolCur, name)
+		assert.Nil(t, writeFileSync(cur, []byte(`{"op":"add"}`)))
+
+		stale := time.Now().Add(-claimLease - time.Minute)
+		assert.Nil(t, os.Chtimes(cur, stale, stale))
+
+		assert.Nil(t, q.recover())
+		assert.Equal(t, []string{name}, segments(t, filepath.Join(dir, spoolNew)))
+	})
+
+	t.Run("positive_ordered_key_waits", func(t *testing.T) {
+		dir := t.TempDir()
+
+		q := &Spool{dir: dir, capacity: 10, ordered: true, mu: &sync.Mutex{}}
+		for _, d := range []string{spoolTmp, spoolNew, spoolCur} {
+			assert.Nil(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+		}
+
+		first := testMessage("1")
+		first.Key = "user_1"
+		second := testMessage("2")
+		second.Key = "user_1"
+		other := testMessage("3")
+		other.Key = "user_2"
+
+		for _, m := range []entity.NotifierMessage{first, second, other} {
+			assert.Nil(t, q.write(m))
+		}
+
+		claimed := []string{}
+
+		// the first message of user_1 is in delivery, while others are claimed
+		found, err := q.claim(context.Background(), func(m entity.NotifierMessage) error {
+			claimed = append(claimed, m.ID)
+
+			for {
+				found, err := q.claim(context.Background(), func(m entity.NotifierMessage) error {
+					claimed = append(claimed, m.ID)
+					return nil
+				})
+				assert.Nil(t, err)
+
+				if !found {
+					return nil
+				}
+			}
+		})
+		assert.True(t, found)
+		assert.Nil(t, err)
+
+		// second message of user_1 is claimed only after the first one is delivered
+		assert.Equal(t, []string{"1", "3"}, claimed)
+
+		found, err = q.claim(context.Background(), func(m entity.NotifierMessage) error {
+			claimed = append(claimed, m.ID)
+			return nil
+		})
+		assert.True(t, found)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "3", "2"}, claimed)
+	})
+
+	t.Run("positive_ordered_key_waits_for_failed", func(t *testing.T) {
+		dir := t.TempDir()
+
+		// two replicas share spool directory
+		replicas := make([]*Spool, 2)
+		for i := range replicas {
+			replicas[i] = &Spool{dir: dir, capacity: 100, ordered: true, mu: &sync.Mutex{}}
+		}
+		for _, d := range []string{spoolTmp, spoolNew, spoolCur} {
+			assert.Nil(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+		}
+
+		for i := 0; i < 20; i++ {
+			m := testMessage(fmt.Sprint(i))
+			m.Key = "user_1"
+			assert.Nil(t, replicas[0].write(m))
+		}
+
+		mu := &sync.Mutex{}
+		inFlight := 0
+		attempts := make(map[string]int)
+		delivered := []string{}
+
+		// every message fails once and is moved back into new, while other workers claim
+		deliver := func(m entity.NotifierMessage) error {
+			mu.Lock()
+			inFlight++
+			assert.Equal(t, 1, inFlight, "message %s is claimed, while earlier one is pending", m.ID)
+			attempts[m.ID]++
+			failed := attempts[m.ID] == 1
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			inFlight--
+
+			if failed {
+				return errTest
+			}
+
+			delivered = append(delivered, m.ID)
+
+			return nil
+		}
+
+		wg := &sync.WaitGroup{}
+
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+
+			go func(q *Spool) {
+				defer wg.Done()
+
+				for {
+					mu.Lock()
+					done := len(delivered) == 20
+					mu.Unlock()
+
+					if done {
+						return
+					}
+
+					_, _ = q.claim(context.Background(), deliver)
+				}
+			}(replicas[w%2])
+		}
+
+		wg.Wait()
+
+		for i, id := range delivered {
+			assert.Equal(t, fmt.Sprint(i), id)
+		}
+	})
+
+	t.Run("positive_stats", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		q, err := NewSpool(config.Queue{QueueSize: 10, GoRoutinesSize: 1, SpoolDir: dir, PollInterval: 60}, queue_mock.NewMocknotifier(ctr), nil)
+		assert.Nil(t, err)
+
+		// stopping workers, so messages stay in spool
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		// message claimed by another replica a minute ago
+		name := fmt.Sprintf("%020d_id_.json", time.Now().Add(-time.Minute).UnixNano())
+		assert.Nil(t, writeFileSync(filepath.Join(dir, spoolCur, name), []byte(`{"op":"add"}`)))
+
+		assert.Nil(t, q.write(testMessage("1")))
+
+		stats, err := q.Stats(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, config.QueueBackendSpool, stats.Backend)
+		assert.Equal(t, 2, stats.Buffered)
+		assert.Equal(t, 10, stats.Capacity)
+		assert.InDelta(t, 60, stats.OldestAge, 5)
+	})
+
+	t.Run("negative_queue_full", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		q, err := NewSpool(config.Queue{QueueSize: 1, GoRoutinesSize: 1, SpoolDir: dir, PollInterval: 60}, queue_mock.NewMocknotifier(ctr), nil)
+		assert.Nil(t, err)
+
+		// stopping workers, so message stays in spool
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		assert.Nil(t, q.write(testMessage("1")))
+		assert.ErrorIs(t, q.write(testMessage("2")), entity.ErrQueueFull)
+		assert.ErrorIs(t, q.Add(context.Background(), testMessage("3")), entity.ErrQueueClosed)
+	})
+
+	t.Run("negative_queue_full_concurrent", func(t *testing.T) {
+		dir := t.TempDir()
+
+		// two replicas share spool directory
+		replicas := make([]*Spool, 2)
+		for i := range replicas {
+			replicas[i] = &Spool{dir: dir, capacity: 5, mu: &sync.Mutex{}}
+		}
+		for _, d := range []string{spoolTmp, spoolNew, spoolCur} {
+			assert.Nil(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+		}
+
+		mu := &sync.Mutex{}
+		added, full := 0, 0
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func(q *Spool, id string) {
+				defer wg.Done()
+
+				err := q.write(testMessage(id))
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if errors.Is(err, entity.ErrQueueFull) {
+					full++
+					return
+				}
+
+				assert.Nil(t, err)
+				added++
+			}(replicas[i%2], fmt.Sprint(i))
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 5, added)
+		assert.Equal(t, 15, full)
+		assert.Len(t, segments(t, filepath.Join(dir, spoolNew)), 5)
+		assert.Empty(t, segments(t, filepath.Join(dir, spoolTmp)))
+	})
+
+	t.Run("positive_sequence_grows", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		dir := t.TempDir()
+
+		// message, added before clock went back
+		ahead := time.Now().Add(time.Hour).UnixNano()
+
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, spoolNew), 0o755))
+		assert.Nil(t, writeFileSync(filepath.Join(dir, spoolNew, fmt.Sprintf("%020d_id_%s", ahead, spoolExt)), []byte(`{}`)))
+
+		q, err := NewSpool(config.Queue{QueueSize: 10, GoRoutinesSize: 1, SpoolDir: dir, PollInterval: 60}, queue_mock.NewMocknotifier(ctr), nil)
+		assert.Nil(t, err)
+
+		_, err = q.Shutdown(context.Background())
+		assert.Nil(t, err)
+
+		previous := ahead
+
+		for i := 0; i < 100; i++ {
+			seq := q.next()
+			assert.Greater(t, seq, previous)
+
+			previous = seq
+		}
+	})
+}
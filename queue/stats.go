@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/faceit/test/entity"
+)
+
+// counters are delivery counters of a queue backend, kept by this instance
+// pending messages are tracked by in-process queue only, shared backends know their oldest message
+type counters struct {
+	mu          *sync.Mutex
+	inFlight    int
+	enqueued    map[string]int64
+	lastError   string
+	lastErrorAt time.Time
+	seq         uint64
+	pending     map[uint64]time.Time
+}
+
+// newCounters creates new counters
+func newCounters() *counters {
+	return &counters{
+		mu:       &sync.Mutex{},
+		enqueued: make(map[string]int64),
+		pending:  make(map[uint64]time.Time),
+	}
+}
+
+// added counts message as enqueued for each of it's consumers
+func (c *counters) added(message entity.NotifierMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, consumer := range message.Consumers {
+		c.enqueued[consumer]++
+	}
+}
+
+// retain removes enqueued counters of consumers, that are not subscribed by subs
+func (c *counters) retain(subs []entity.Subscription) {
+	consumers := make(map[string]struct{}, len(subs))
+	for _, s := range subs {
+		consumers[s.URL] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for consumer := range c.enqueued {
+		if _, ok := consumers[consumer]; !ok {
+			delete(c.enqueued, consumer)
+		}
+	}
+}
+
+// track starts tracking age of pending message, returned id is passed to untrack
+func (c *counters) track(queued time.Time) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	c.pending[c.seq] = queued
+
+	return c.seq
+}
+
+// untrack stops tracking age of message
+func (c *counters) untrack(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, id)
+}
+
+// started counts delivery in progress
+func (c *counters) started() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight++
+}
+
+// finished counts delivery end, err is kept as the last error
+func (c *counters) finished(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+
+	if err != nil {
+		c.failure(err)
+	}
+}
+
+// failed keeps err as the last error
+func (c *counters) failed(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failure(err)
+}
+
+// failure keeps err as the last error, c.mu must be locked
+func (c *counters) failure(err error) {
+	c.lastError = err.Error()
+	c.lastErrorAt = time.Now().UTC()
+}
+
+// stats returns counters as entity.QueueStats
+// oldest pending message is used, if there is no older one in oldest
+func (c *counters) stats(oldest time.Time) entity.QueueStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, queued := range c.pending {
+		if oldest.IsZero() || queued.Before(oldest) {
+			oldest = queued
+		}
+	}
+
+	s := entity.QueueStats{
+		InFlight:  c.inFlight,
+		LastError: c.lastError,
+		Consumers: make([]entity.ConsumerStats, 0, len(c.enqueued)),
+	}
+
+	if !oldest.IsZero() {
+		s.OldestAge = time.Since(oldest).Seconds()
+	}
+
+	if !c.lastErrorAt.IsZero() {
+		at := c.lastErrorAt
+		s.LastErrorTime = &at
+	}
+
+	for consumer, n := range c.enqueued {
+		s.Consumers = append(s.Consumers, entity.ConsumerStats{Consumer: consumer, Enqueued: n})
+	}
+
+	sort.Slice(s.Consumers, func(i, j int) bool {
+		return s.Consumers[i].Consumer < s.Consumers[j].Consumer
+	})
+
+	return s
+}
@@ -0,0 +1,47 @@
+//go:generate mockgen -source ../audit/audit.go -destination ../audit/mock/mock_audit.go
+
+package audit
+
+import (
+	"context"
+
+	"github.com/faceit/test/entity"
+)
+
+// client is a user audit client interface
+type client interface {
+	History(ctx context.Context, userID int, page entity.Page) ([]entity.Audit, error)
+}
+
+// Audit is a user audit service struct
+type Audit struct {
+	client client
+}
+
+// New creates new Audit instance
+func New(c client) *Audit {
+	return &Audit{
+		client: c,
+	}
+}
+
+// History returns page of user's changes, the latest first, and a cursor of the next page
+// cursor is empty, if it is the last page
+func (a *Audit) History(ctx context.Context, userID int, page entity.Page) ([]entity.Audit, string, error) {
+	// one more record is requested to know, if there is a next page
+	query := page
+	query.Limit++
+
+	records, err := a.client.History(ctx, userID, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(records) <= page.Limit {
+		return records, "", nil
+	}
+
+	records = records[:page.Limit]
+
+	return records, entity.Cursor(records[len(records)-1].ID), nil
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	mock_audit "github.com/faceit/test/services/audit/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserID = 1
+
+var (
+	errTest = errors.New("error_test")
+
+	testRecords = []entity.Audit{
+		{ID: 3, UserID: testUserID, Action: entity.ActionUpdate},
+		{ID: 2, UserID: testUserID, Action: entity.AuditActionPassword},
+		{ID: 1, UserID: testUserID, Action: entity.ActionCreate},
+	}
+)
+
+func TestHistory(t *testing.T) {
+	t.Run("positive_last_page", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_audit.NewMockclient(ctr)
+		mockClient.EXPECT().History(ctx, testUserID, entity.Page{Limit: 4}).Return(testRecords, nil)
+
+		records, next, err := New(mockClient).History(ctx, testUserID, entity.Page{Limit: 3})
+		assert.Nil(t, err)
+		assert.Equal(t, testRecords, records)
+		assert.Empty(t, next)
+	})
+
+	t.Run("positive_next_page", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_audit.NewMockclient(ctr)
+		mockClient.EXPECT().History(ctx, testUserID, entity.Page{After: 5, Limit: 3}).Return(testRecords, nil)
+
+		records, next, err := New(mockClient).History(ctx, testUserID, entity.Page{After: 5, Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, testRecords[:2], records)
+		assert.Equal(t, entity.Cursor(testRecords[1].ID), next)
+	})
+
+	t.Run("negative_client_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_audit.NewMockclient(ctr)
+		mockClient.EXPECT().History(ctx, testUserID, entity.Page{Limit: 3}).Return(nil, errTest)
+
+		_, _, err := New(mockClient).History(ctx, testUserID, entity.Page{Limit: 2})
+		assert.ErrorIs(t, err, errTest)
+	})
+}
@@ -0,0 +1,87 @@
+//go:generate mockgen -source ../deadletter/deadletter.go -destination ../deadletter/mock/mock_deadletter.go
+
+package deadletter
+
+import (
+	"context"
+	"fmt"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+)
+
+// client is a dead letter client interface
+type client interface {
+	All(ctx context.Context, consumer string) ([]entity.DeadLetter, error)
+	One(ctx context.Context, id int) (entity.DeadLetter, error)
+	Failed(ctx context.Context, id, attempts int, lastError string) error
+	Delete(ctx context.Context, id int) error
+	DeleteAll(ctx context.Context) (int, error)
+}
+
+// notifier is a dead letter notifier interface
+type notifier interface {
+	Redeliver(ctx context.Context, consumer string, message []byte) (int, error)
+}
+
+// DeadLetter is a dead letter service struct
+type DeadLetter struct {
+	client   client
+	notifier notifier
+}
+
+// New creates new dead letter service instance
+func New(c client, n notifier) *DeadLetter {
+	return &DeadLetter{
+		client:   c,
+		notifier: n,
+	}
+}
+
+// All returnes all dead letters, or only consumer's ones if consumer is not empty
+func (d *DeadLetter) All(ctx context.Context, consumer string) ([]entity.DeadLetter, error) {
+	return d.client.All(ctx, consumer)
+}
+
+// One returnes one dead letter by id
+func (d *DeadLetter) One(ctx context.Context, id int) (entity.DeadLetter, error) {
+	return d.client.One(ctx, id)
+}
+
+// Replay sends dead letter to it's consumer once again
+// dead letter is removed, if it was delivered, otherwise it's attempts are increased by attempts made
+// and last error is updated
+func (d *DeadLetter) Replay(ctx context.Context, id int) error {
+	dl, err := d.client.One(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// replayed notification keeps it's original event id
+	sendCtx := ctx
+	if dl.EventID != "" {
+		sendCtx = cont.SetEventID(ctx, dl.EventID)
+	}
+
+	attempts, sendErr := d.notifier.Redeliver(sendCtx, dl.Consumer, dl.Payload)
+	if sendErr != nil {
+		err = d.client.Failed(ctx, id, attempts, sendErr.Error())
+		if err != nil {
+			return fmt.Errorf("failed to update dead letter, error: %w", err)
+		}
+
+		return fmt.Errorf("%w, %s", entity.ErrDeliveryFailed, sendErr)
+	}
+
+	return d.client.Delete(ctx, id)
+}
+
+// Purge removes one dead letter by id
+func (d *DeadLetter) Purge(ctx context.Context, id int) error {
+	return d.client.Delete(ctx, id)
+}
+
+// PurgeAll removes all dead letters and returnes their number
+func (d *DeadLetter) PurgeAll(ctx context.Context) (int, error) {
+	return d.client.DeleteAll(ctx)
+}
@@ -0,0 +1,121 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	mock_deadletter "github.com/faceit/test/services/deadletter/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testID       = 1
+	testAttempts = 3
+	testConsumer = "http://consumer.test"
+
+	testEventID = "test_event_id"
+
+	testDeadLetter = entity.DeadLetter{
+		ID:        testID,
+		EventID:   testEventID,
+		Consumer:  testConsumer,
+		LastError: "timeout",
+		Attempts:  testAttempts,
+		Payload:   []byte(`{"action":"CREATE"}`),
+	}
+
+	testDeadLetters = []entity.DeadLetter{testDeadLetter}
+)
+
+func TestAll(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx, testConsumer).Return(testDeadLetters, nil)
+
+		dls, err := New(mockClient, mock_deadletter.NewMocknotifier(ctr)).All(ctx, testConsumer)
+		assert.Nil(t, err)
+		assert.Equal(t, testDeadLetters, dls)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().One(ctx, testID).Return(testDeadLetter, nil)
+		mockClient.EXPECT().Delete(ctx, testID).Return(nil)
+
+		mockNotifier := mock_deadletter.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Redeliver(cont.SetEventID(ctx, testEventID), testConsumer, []byte(testDeadLetter.Payload)).Return(1, nil)
+
+		err := New(mockClient, mockNotifier).Replay(ctx, testID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().One(ctx, testID).Return(entity.DeadLetter{}, entity.ErrNotFound)
+
+		err := New(mockClient, mock_deadletter.NewMocknotifier(ctr)).Replay(ctx, testID)
+		assert.Equal(t, entity.ErrNotFound, err)
+	})
+
+	t.Run("negative_delivery_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().One(ctx, testID).Return(testDeadLetter, nil)
+		mockClient.EXPECT().Failed(ctx, testID, testAttempts, errTest.Error()).Return(nil)
+
+		mockNotifier := mock_deadletter.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Redeliver(cont.SetEventID(ctx, testEventID), testConsumer, []byte(testDeadLetter.Payload)).Return(testAttempts, errTest)
+
+		err := New(mockClient, mockNotifier).Replay(ctx, testID)
+		assert.True(t, errors.Is(err, entity.ErrDeliveryFailed))
+	})
+
+	t.Run("negative_update_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().One(ctx, testID).Return(testDeadLetter, nil)
+		mockClient.EXPECT().Failed(ctx, testID, testAttempts, errTest.Error()).Return(errTest)
+
+		mockNotifier := mock_deadletter.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Redeliver(cont.SetEventID(ctx, testEventID), testConsumer, []byte(testDeadLetter.Payload)).Return(testAttempts, errTest)
+
+		err := New(mockClient, mockNotifier).Replay(ctx, testID)
+		assert.True(t, errors.Is(err, errTest))
+		assert.False(t, errors.Is(err, entity.ErrDeliveryFailed))
+	})
+}
+
+func TestPurgeAll(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_deadletter.NewMockclient(ctr)
+		mockClient.EXPECT().DeleteAll(ctx).Return(len(testDeadLetters), nil)
+
+		n, err := New(mockClient, mock_deadletter.NewMocknotifier(ctr)).PurgeAll(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, len(testDeadLetters), n)
+	})
+}
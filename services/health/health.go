@@ -12,24 +12,38 @@ type check interface {
 	Ping() error
 }
 
+// reporter is a component, reporting it's own health state
+type reporter interface {
+	Health(ctx context.Context) []entity.Response
+}
+
 // Check is a healtCheck struct
 type Check struct {
-	db  check
-	log logger.Logger
+	db        check
+	reporters []reporter
+	log       logger.Logger
 }
 
 // New creates New Check instance
-func New(db check, log logger.Logger) *Check {
+func New(db check, log logger.Logger, reporters ...reporter) *Check {
 	return &Check{
-		db: db,
+		db:        db,
+		reporters: reporters,
+		log:       log,
 	}
 }
 
 // Do performes a healtCheck
 func (c *Check) Do(ctx context.Context) []entity.Response {
-	return []entity.Response{
+	resp := []entity.Response{
 		c.dbCheck(ctx),
 	}
+
+	for _, r := range c.reporters {
+		resp = append(resp, r.Health(ctx)...)
+	}
+
+	return resp
 }
 
 // dbCheck is checking db health
@@ -43,7 +57,7 @@ func (c *Check) dbCheck(ctx context.Context) entity.Response {
 	if err != nil {
 		resp.Message = err.Error()
 
-		c.log.Errorf(ctx, "healthCheck: %s in unhealthy, error: %w", err)
+		c.log.Errorf(ctx, "healthCheck: %s in unhealthy, error: %w", resp.Name, err)
 	}
 
 	resp.Healthy = err == nil
@@ -0,0 +1,114 @@
+//go:generate mockgen -source ../queuestats/queuestats.go -destination ../queuestats/mock/mock_queuestats.go
+
+package queuestats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+)
+
+// healthName is a name of queue in health response
+const healthName = "queue"
+
+// queue is a queue backend interface
+type queue interface {
+	Stats(ctx context.Context) (entity.QueueStats, error)
+}
+
+// notifier is a notifier counters interface
+type notifier interface {
+	ConsumerStats(ctx context.Context) []entity.ConsumerStats
+	ConsumerName(consumer string) (string, bool)
+}
+
+// Stats is a queue introspection service struct
+type Stats struct {
+	queue        queue
+	notifier     notifier
+	stuckTimeout time.Duration
+}
+
+// New creates new Stats instance
+func New(cfg config.Queue, q queue, n notifier) *Stats {
+	return &Stats{
+		queue:        q,
+		notifier:     n,
+		stuckTimeout: time.Duration(cfg.StuckTimeout) * time.Second,
+	}
+}
+
+// Do returns queue state with enqueued counters of queue and delivery counters of notifier per consumer
+// consumers are reported by names of notifier, as urls could hold credentials, unsubscribed consumers are skipped
+func (s *Stats) Do(ctx context.Context) (entity.QueueStats, error) {
+	stats, err := s.queue.Stats(ctx)
+	if err != nil {
+		return entity.QueueStats{}, err
+	}
+
+	consumers := make(map[string]entity.ConsumerStats, len(stats.Consumers))
+
+	// queue counts consumers by url
+	for _, c := range stats.Consumers {
+		name, ok := s.notifier.ConsumerName(c.Consumer)
+		if !ok {
+			continue
+		}
+
+		c.Consumer = name
+		consumers[name] = c
+	}
+
+	for _, c := range s.notifier.ConsumerStats(ctx) {
+		c.Enqueued = consumers[c.Consumer].Enqueued
+		consumers[c.Consumer] = c
+	}
+
+	stats.Consumers = make([]entity.ConsumerStats, 0, len(consumers))
+	for _, c := range consumers {
+		stats.Consumers = append(stats.Consumers, c)
+	}
+
+	sort.Slice(stats.Consumers, func(i, j int) bool {
+		return stats.Consumers[i].Consumer < stats.Consumers[j].Consumer
+	})
+
+	return stats, nil
+}
+
+// Health returns queue health state
+// queue is unhealthy, if it is full, or it's oldest message is waiting for longer than stuck timeout
+func (s *Stats) Health(ctx context.Context) []entity.Response {
+	resp := entity.Response{
+		Name: healthName,
+		Time: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	stats, err := s.queue.Stats(ctx)
+	if err != nil {
+		resp.Message = err.Error()
+		return []entity.Response{resp}
+	}
+
+	resp.Message = fmt.Sprintf("buffered: %d/%d, in flight: %d, oldest: %.0fs",
+		stats.Buffered, stats.Capacity, stats.InFlight, stats.OldestAge)
+
+	switch {
+	case stats.Capacity > 0 && stats.Buffered >= stats.Capacity:
+		resp.Message = "queue is full, " + resp.Message
+	case s.stuckTimeout > 0 && stats.OldestAge > s.stuckTimeout.Seconds():
+		resp.Message = "queue is stuck, " + resp.Message
+	default:
+		resp.Healthy = true
+	}
+
+	if stats.LastError != "" {
+		resp.Message += ", last error: " + stats.LastError
+	}
+
+	return []entity.Response{resp}
+}
@@ -0,0 +1,128 @@
+package queuestats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	mock_queuestats "github.com/faceit/test/services/queuestats/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testConfig = config.Queue{StuckTimeout: 60}
+
+	testStats = entity.QueueStats{
+		Backend:  config.QueueBackendMemory,
+		Buffered: 2,
+		InFlight: 1,
+		Capacity: 10,
+		Consumers: []entity.ConsumerStats{
+			{Consumer: "consumer_a", Enqueued: 3},
+			{Consumer: "consumer_c", Enqueued: 1},
+		},
+	}
+)
+
+func TestDo(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockQueue := mock_queuestats.NewMockqueue(ctr)
+		mockQueue.EXPECT().Stats(ctx).Return(testStats, nil)
+
+		// notifier reports consumers by names, consumer_b received redelivered dead letter, that was not queued,
+		// consumer_c is not subscribed anymore
+		mockNotifier := mock_queuestats.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().ConsumerStats(ctx).Return([]entity.ConsumerStats{
+			{Consumer: "subscription 1", Delivered: 2},
+			{Consumer: "subscription 2", Delivered: 1},
+		})
+		mockNotifier.EXPECT().ConsumerName("consumer_a").Return("subscription 1", true)
+		mockNotifier.EXPECT().ConsumerName("consumer_c").Return("", false)
+
+		stats, err := New(testConfig, mockQueue, mockNotifier).Do(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []entity.ConsumerStats{
+			{Consumer: "subscription 1", Enqueued: 3, Delivered: 2},
+			{Consumer: "subscription 2", Delivered: 1},
+		}, stats.Consumers)
+		assert.Equal(t, testStats.Buffered, stats.Buffered)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockQueue := mock_queuestats.NewMockqueue(ctr)
+		mockQueue.EXPECT().Stats(ctx).Return(entity.QueueStats{}, errTest)
+
+		_, err := New(testConfig, mockQueue, mock_queuestats.NewMocknotifier(ctr)).Do(ctx)
+		assert.Equal(t, errTest, err)
+	})
+}
+
+type testCaseHealth struct {
+	stats   entity.QueueStats
+	err     error
+	healthy bool
+	message string
+}
+
+func TestHealth(t *testing.T) {
+	stuck := testStats
+	stuck.OldestAge = 61
+
+	full := testStats
+	full.Buffered = full.Capacity
+
+	failed := testStats
+	failed.LastError = errTest.Error()
+
+	for name, tc := range map[string]testCaseHealth{
+		"positive": {
+			stats:   testStats,
+			healthy: true,
+			message: "buffered: 2/10, in flight: 1, oldest: 0s",
+		},
+		"positive_last_error": {
+			stats:   failed,
+			healthy: true,
+			message: "buffered: 2/10, in flight: 1, oldest: 0s, last error: error_test",
+		},
+		"negative_stuck": {
+			stats:   stuck,
+			message: "queue is stuck, buffered: 2/10, in flight: 1, oldest: 61s",
+		},
+		"negative_full": {
+			stats:   full,
+			message: "queue is full, buffered: 10/10, in flight: 1, oldest: 0s",
+		},
+		"negative_stats_failed": {
+			err:     errTest,
+			message: errTest.Error(),
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockQueue := mock_queuestats.NewMockqueue(ctr)
+			mockQueue.EXPECT().Stats(ctx).Return(tc.stats, tc.err)
+
+			resp := New(testConfig, mockQueue, mock_queuestats.NewMocknotifier(ctr)).Health(ctx)
+			assert.Len(t, resp, 1)
+			assert.Equal(t, healthName, resp[0].Name)
+			assert.Equal(t, tc.healthy, resp[0].Healthy)
+			assert.Equal(t, tc.message, resp[0].Message)
+		})
+	}
+}
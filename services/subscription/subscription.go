@@ -0,0 +1,294 @@
+//go:generate mockgen -source ../subscription/subscription.go -destination ../subscription/mock/mock_subscription.go
+
+package subscription
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	"github.com/faceit/test/notifier/signature"
+)
+
+// client is a subscription client interface
+type client interface {
+	Create(ctx context.Context, s entity.Subscription) (int, error)
+	One(ctx context.Context, id int) (entity.Subscription, error)
+	All(ctx context.Context) ([]entity.Subscription, error)
+	Update(ctx context.Context, s entity.Subscription) error
+	Delete(ctx context.Context, id int) error
+	Rotate(ctx context.Context, id int, secret string, expiresAt time.Time) error
+}
+
+// Subscription is a subscription service struct
+// it keeps enabled subscriptions in memory to resolve consumers of a notification,
+// they are reloaded after every change and every refresh interval, so changes made by other nodes are picked up too
+type Subscription struct {
+	client        client
+	static        []entity.Subscription
+	staticSecrets []string
+	mu            *sync.RWMutex
+	active        []entity.Subscription
+	listeners     []func([]entity.Subscription)
+	interval      time.Duration
+	rotationTTL   time.Duration
+	log           logger.Logger
+}
+
+// New creates new subscription service instance
+// consumers from cfg are always subscribed to their actions and share signing secrets from cfg
+func New(c client, cfg config.Notifier, l logger.Logger) *Subscription {
+	s := &Subscription{
+		client:      c,
+		mu:          &sync.RWMutex{},
+		interval:    time.Duration(cfg.SubscriptionsRefresh) * time.Second,
+		rotationTTL: time.Duration(cfg.SecretRotationTTL) * time.Second,
+		log:         l,
+	}
+
+	for _, secret := range []string{cfg.SigningSecret, cfg.PreviousSigningSecret} {
+		if secret != "" {
+			s.staticSecrets = append(s.staticSecrets, secret)
+		}
+	}
+
+	s.static = append(s.static, static(cfg.OnCreate(), entity.ActionCreate)...)
+	s.static = append(s.static, static(cfg.OnUpdate(), entity.ActionUpdate)...)
+	s.static = append(s.static, static(cfg.OnDelete(), entity.ActionDelete)...)
+	s.active = s.static
+
+	return s
+}
+
+// All returnes all stored subscriptions
+func (s *Subscription) All(ctx context.Context) ([]entity.Subscription, error) {
+	return s.client.All(ctx)
+}
+
+// One returnes one subscription by id
+func (s *Subscription) One(ctx context.Context, id int) (entity.Subscription, error) {
+	return s.client.One(ctx, id)
+}
+
+// Create stores a new subscription and returnes it with id and signing secret set
+// signing secret is generated, if subscription has none
+func (s *Subscription) Create(ctx context.Context, sub entity.Subscription) (entity.Subscription, error) {
+	if sub.Secret == "" {
+		secret, err := signature.NewSecret()
+		if err != nil {
+			return entity.Subscription{}, err
+		}
+
+		sub.Secret = secret
+	}
+
+	id, err := s.client.Create(ctx, sub)
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	s.reload(ctx)
+
+	sub.ID = id
+
+	return sub, nil
+}
+
+// Update updates subscription
+func (s *Subscription) Update(ctx context.Context, sub entity.Subscription) error {
+	err := s.client.Update(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+
+	return nil
+}
+
+// Delete removes subscription by id
+func (s *Subscription) Delete(ctx context.Context, id int) error {
+	err := s.client.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+
+	return nil
+}
+
+// Rotate generates a new signing secret of subscription and returnes updated subscription
+// deliveries are signed with both new and previous secrets until rotation TTL expires
+func (s *Subscription) Rotate(ctx context.Context, id int) (entity.Subscription, error) {
+	secret, err := signature.NewSecret()
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	err = s.client.Rotate(ctx, id, secret, time.Now().UTC().Add(s.rotationTTL))
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	s.reload(ctx)
+
+	return s.client.One(ctx, id)
+}
+
+// Secrets returnes active signing secrets of consumer
+func (s *Subscription) Secrets(consumer string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	secrets := []string{}
+
+	for _, sub := range s.active {
+		if sub.URL != consumer {
+			continue
+		}
+
+		if sub.ID == 0 {
+			secrets = append(secrets, s.staticSecrets...)
+			continue
+		}
+
+		secrets = append(secrets, sub.Secrets(now)...)
+	}
+
+	return unique(secrets)
+}
+
+// Batch returnes batch settings of consumer
+// if consumer has many subscriptions, settings of the first one with batching enabled are used,
+// batching is disabled for consumers from config
+func (s *Subscription) Batch(consumer string) entity.Batch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sub := range s.active {
+		if sub.URL == consumer && sub.Batch != nil && sub.Batch.Enabled() {
+			return *sub.Batch
+		}
+	}
+
+	return entity.Batch{}
+}
+
+// Consumers returnes urls of enabled subscriptions, matching action and user's countryID
+func (s *Subscription) Consumers(action string, countryID int) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	consumers := []string{}
+
+	for _, sub := range s.active {
+		if sub.Match(action, countryID) {
+			consumers = append(consumers, sub.URL)
+		}
+	}
+
+	return unique(consumers)
+}
+
+// Refresh reloads enabled subscriptions from store
+func (s *Subscription) Refresh(ctx context.Context) error {
+	subs, err := s.client.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make([]entity.Subscription, 0, len(s.static)+len(subs))
+	active = append(active, s.static...)
+
+	for _, sub := range subs {
+		if sub.Enabled {
+			active = append(active, sub)
+		}
+	}
+
+	s.mu.Lock()
+	s.active = active
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(active)
+	}
+
+	return nil
+}
+
+// OnRefresh registers fn, that is called with enabled subscriptions after every refresh
+// fn is called with the current ones at once, so it does not wait for the next refresh
+func (s *Subscription) OnRefresh(fn func([]entity.Subscription)) {
+	s.mu.Lock()
+	s.listeners = append(s.listeners, fn)
+	active := s.active
+	s.mu.Unlock()
+
+	fn(active)
+}
+
+// Run refreshes subscriptions every refresh interval until ctx is done
+// periodic refresh is disabled, if interval is not positive
+func (s *Subscription) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+// reload refreshes subscriptions, change is already stored, so error is only logged
+// and subscriptions would be reloaded on next refresh
+func (s *Subscription) reload(ctx context.Context) {
+	err := s.Refresh(ctx)
+	if err != nil {
+		s.log.Errorf(ctx, "failed to refresh subscriptions, error: %s", err)
+	}
+}
+
+// static returnes subscriptions of consumers from config
+func static(consumers []string, action string) []entity.Subscription {
+	subs := make([]entity.Subscription, 0, len(consumers))
+
+	for _, c := range consumers {
+		subs = append(subs, entity.Subscription{
+			URL:     c,
+			Actions: []string{action},
+			Enabled: true,
+		})
+	}
+
+	return subs
+}
+
+// unique returnes values without duplicates, keeping their order
+func unique(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+
+	for _, v := range values {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
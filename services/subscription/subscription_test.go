@@ -0,0 +1,279 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	mock_subscription "github.com/faceit/test/services/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testID        = 1
+	testCountryID = 2
+	testConsumer  = "http://consumer.test"
+
+	testSubscription = entity.Subscription{
+		ID:        testID,
+		URL:       testConsumer,
+		Actions:   []string{entity.ActionCreate, entity.ActionDelete},
+		CountryID: testCountryID,
+		Enabled:   true,
+		Secret:    "subscription_secret",
+	}
+
+	testConfig = config.Notifier{
+		Consumers: config.Consumers{
+			OnCreate: []string{"http://static.test"},
+			OnUpdate: []string{"http://static.test"},
+		},
+		SigningSecret:     "static_secret",
+		SecretRotationTTL: 60,
+	}
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("positive_reloaded", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Create(ctx, testSubscription).Return(testID, nil)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{testSubscription}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+		assert.Equal(t, []string{"http://static.test"}, s.Consumers(entity.ActionCreate, testCountryID))
+
+		sub, err := s.Create(ctx, testSubscription)
+		assert.Nil(t, err)
+		assert.Equal(t, testSubscription, sub)
+		assert.Equal(t, []string{"http://static.test", testConsumer}, s.Consumers(entity.ActionCreate, testCountryID))
+	})
+
+	t.Run("positive_secret_generated", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		sub := testSubscription
+		sub.Secret = ""
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, s entity.Subscription) (int, error) {
+				assert.NotEmpty(t, s.Secret)
+				return testID, nil
+			})
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{}, nil)
+
+		created, err := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr))).Create(ctx, sub)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, created.Secret)
+	})
+
+	t.Run("negative_reload_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Create(ctx, testSubscription).Return(testID, nil)
+		mockClient.EXPECT().All(ctx).Return(nil, errTest)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(1)
+
+		sub, err := New(mockClient, testConfig, logger.New(mockLogger)).Create(ctx, testSubscription)
+		assert.Nil(t, err)
+		assert.Equal(t, testID, sub.ID)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Create(ctx, testSubscription).Return(0, errTest)
+
+		_, err := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr))).Create(ctx, testSubscription)
+		assert.Equal(t, errTest, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("positive_reloaded", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{testSubscription}, nil)
+		mockClient.EXPECT().Delete(ctx, testID).Return(nil)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+
+		assert.Nil(t, s.Refresh(ctx))
+		assert.Equal(t, []string{testConsumer}, s.Consumers(entity.ActionDelete, testCountryID))
+
+		assert.Nil(t, s.Delete(ctx, testID))
+		assert.Equal(t, []string{}, s.Consumers(entity.ActionDelete, testCountryID))
+	})
+
+	t.Run("negative_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Delete(ctx, testID).Return(entity.ErrNotFound)
+
+		err := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr))).Delete(ctx, testID)
+		assert.Equal(t, entity.ErrNotFound, err)
+	})
+}
+
+func TestConsumers(t *testing.T) {
+	t.Run("positive_filters", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		disabled := testSubscription
+		disabled.ID = 2
+		disabled.URL = "http://disabled.test"
+		disabled.Enabled = false
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{testSubscription, disabled}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+		assert.Nil(t, s.Refresh(ctx))
+
+		assert.Equal(t, []string{"http://static.test", testConsumer}, s.Consumers(entity.ActionCreate, testCountryID))
+		assert.Equal(t, []string{"http://static.test"}, s.Consumers(entity.ActionCreate, testCountryID+1))
+		assert.Equal(t, []string{"http://static.test"}, s.Consumers(entity.ActionUpdate, testCountryID))
+		// country of deleted user is unknown
+		assert.Equal(t, []string{}, s.Consumers(entity.ActionDelete, 0))
+	})
+}
+
+func TestRotate(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		expiresAt := time.Now().UTC().Add(time.Minute)
+
+		rotated := testSubscription
+		rotated.Secret = "new_subscription_secret"
+		rotated.PreviousSecret = testSubscription.Secret
+		rotated.PreviousSecretExpiresAt = &expiresAt
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Rotate(ctx, testID, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int, secret string, expires time.Time) error {
+				assert.NotEqual(t, testSubscription.Secret, secret)
+				assert.WithinDuration(t, expiresAt, expires, time.Second)
+				return nil
+			})
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{rotated}, nil)
+		mockClient.EXPECT().One(ctx, testID).Return(rotated, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+
+		sub, err := s.Rotate(ctx, testID)
+		assert.Nil(t, err)
+		assert.Equal(t, rotated, sub)
+
+		// both secrets are active until previous one expires
+		assert.Equal(t, []string{rotated.Secret, testSubscription.Secret}, s.Secrets(testConsumer))
+	})
+
+	t.Run("negative_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().Rotate(ctx, testID, gomock.Any(), gomock.Any()).Return(entity.ErrNotFound)
+
+		_, err := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr))).Rotate(ctx, testID)
+		assert.Equal(t, entity.ErrNotFound, err)
+	})
+}
+
+func TestSecrets(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		expired := time.Now().UTC().Add(-time.Minute)
+
+		sub := testSubscription
+		sub.PreviousSecret = "expired_secret"
+		sub.PreviousSecretExpiresAt = &expired
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{sub}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+		assert.Nil(t, s.Refresh(ctx))
+
+		assert.Equal(t, []string{testSubscription.Secret}, s.Secrets(testConsumer))
+		assert.Equal(t, []string{testConfig.SigningSecret}, s.Secrets("http://static.test"))
+		assert.Equal(t, []string{}, s.Secrets("http://unknown.test"))
+	})
+}
+
+func TestBatch(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		batch := entity.Batch{Size: 10, Window: 500, Format: entity.BatchFormatNDJSON}
+
+		// the first subscription of consumer delivers messages one by one
+		single := testSubscription
+		batched := testSubscription
+		batched.ID = 2
+		batched.Batch = &batch
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{single, batched}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+		assert.Nil(t, s.Refresh(ctx))
+
+		assert.Equal(t, batch, s.Batch(testConsumer))
+		assert.False(t, s.Batch("http://static.test").Enabled())
+	})
+}
+
+func TestOnRefresh(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_subscription.NewMockclient(ctr)
+		mockClient.EXPECT().All(ctx).Return([]entity.Subscription{testSubscription}, nil)
+
+		s := New(mockClient, testConfig, logger.New(mock_logger.NewMocklog(ctr)))
+
+		var got [][]entity.Subscription
+
+		s.OnRefresh(func(subs []entity.Subscription) {
+			got = append(got, subs)
+		})
+
+		assert.Nil(t, s.Refresh(ctx))
+
+		// the current subscriptions are passed at once, and after every refresh
+		assert.Len(t, got, 2)
+		assert.Len(t, got[1], len(got[0])+1)
+		assert.Contains(t, got[1], testSubscription)
+	})
+}
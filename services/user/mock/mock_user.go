@@ -34,11 +34,26 @@ func (m *Mockclient) EXPECT() *MockclientMockRecorder {
 	return m.recorder
 }
 
+// All mocks base method
+func (m *Mockclient) All(ctx context.Context, filter entity.UserFilter, page entity.Page) ([]entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "All", ctx, filter, page)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// All indicates an expected call of All
+func (mr *MockclientMockRecorder) All(ctx, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*Mockclient)(nil).All), ctx, filter, page)
+}
+
 // Create mocks base method
-func (m *Mockclient) Create(ctx context.Context, u entity.User) (int, error) {
+func (m *Mockclient) Create(ctx context.Context, u entity.User) (entity.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, u)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -49,18 +64,32 @@ func (mr *MockclientMockRecorder) Create(ctx, u interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Mockclient)(nil).Create), ctx, u)
 }
 
-// Update mocks base method
-func (m *Mockclient) Update(ctx context.Context, u entity.User) error {
+// Delete mocks base method
+func (m *Mockclient) Delete(ctx context.Context, u entity.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, u)
+	ret := m.ctrl.Call(m, "Delete", ctx, u)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update
-func (mr *MockclientMockRecorder) Update(ctx, u interface{}) *gomock.Call {
+// Delete indicates an expected call of Delete
+func (mr *MockclientMockRecorder) Delete(ctx, u interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockclient)(nil).Delete), ctx, u)
+}
+
+// Each mocks base method
+func (m *Mockclient) Each(ctx context.Context, filter entity.UserFilter, fn func(entity.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Each", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Each indicates an expected call of Each
+func (mr *MockclientMockRecorder) Each(ctx, filter, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*Mockclient)(nil).Update), ctx, u)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Each", reflect.TypeOf((*Mockclient)(nil).Each), ctx, filter, fn)
 }
 
 // One mocks base method
@@ -78,63 +107,64 @@ func (mr *MockclientMockRecorder) One(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*Mockclient)(nil).One), ctx, id)
 }
 
-// Delete mocks base method
-func (m *Mockclient) Delete(ctx context.Context, id int) error {
+// Patch mocks base method
+func (m *Mockclient) Patch(ctx context.Context, u entity.User, patch entity.UserPatch, change entity.UserChange) (entity.UserChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Patch", ctx, u, patch, change)
+	ret0, _ := ret[0].(entity.UserChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete
-func (mr *MockclientMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+// Patch indicates an expected call of Patch
+func (mr *MockclientMockRecorder) Patch(ctx, u, patch, change interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockclient)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*Mockclient)(nil).Patch), ctx, u, patch, change)
 }
 
-// All mocks base method
-func (m *Mockclient) All(ctx context.Context) ([]entity.User, error) {
+// Restore mocks base method
+func (m *Mockclient) Restore(ctx context.Context, id int) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "All", ctx)
-	ret0, _ := ret[0].([]entity.User)
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// All indicates an expected call of All
-func (mr *MockclientMockRecorder) All(ctx interface{}) *gomock.Call {
+// Restore indicates an expected call of Restore
+func (mr *MockclientMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*Mockclient)(nil).All), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*Mockclient)(nil).Restore), ctx, id)
 }
 
-// AllByCountry mocks base method
-func (m *Mockclient) AllByCountry(ctx context.Context, iso2 string) ([]entity.User, error) {
+// Search mocks base method
+func (m *Mockclient) Search(ctx context.Context, q string, limit int) ([]entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AllByCountry", ctx, iso2)
+	ret := m.ctrl.Call(m, "Search", ctx, q, limit)
 	ret0, _ := ret[0].([]entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AllByCountry indicates an expected call of AllByCountry
-func (mr *MockclientMockRecorder) AllByCountry(ctx, iso2 interface{}) *gomock.Call {
+// Search indicates an expected call of Search
+func (mr *MockclientMockRecorder) Search(ctx, q, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllByCountry", reflect.TypeOf((*Mockclient)(nil).AllByCountry), ctx, iso2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*Mockclient)(nil).Search), ctx, q, limit)
 }
 
-// AllWithFilter mocks base method
-func (m *Mockclient) AllWithFilter(ctx context.Context, title, filter string) ([]entity.User, error) {
+// Update mocks base method
+func (m *Mockclient) Update(ctx context.Context, u entity.User, change entity.UserChange) (entity.UserChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AllWithFilter", ctx, title, filter)
-	ret0, _ := ret[0].([]entity.User)
+	ret := m.ctrl.Call(m, "Update", ctx, u, change)
+	ret0, _ := ret[0].(entity.UserChange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AllWithFilter indicates an expected call of AllWithFilter
-func (mr *MockclientMockRecorder) AllWithFilter(ctx, title, filter interface{}) *gomock.Call {
+// Update indicates an expected call of Update
+func (mr *MockclientMockRecorder) Update(ctx, u, change interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllWithFilter", reflect.TypeOf((*Mockclient)(nil).AllWithFilter), ctx, title, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*Mockclient)(nil).Update), ctx, u, change)
 }
 
 // MockpasswordClient is a mock of passwordClient interface
@@ -175,6 +205,44 @@ func (mr *MockpasswordClientMockRecorder) One(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*MockpasswordClient)(nil).One), ctx, id)
 }
 
+// MockcountryClient is a mock of countryClient interface
+type MockcountryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockcountryClientMockRecorder
+}
+
+// MockcountryClientMockRecorder is the mock recorder for MockcountryClient
+type MockcountryClientMockRecorder struct {
+	mock *MockcountryClient
+}
+
+// NewMockcountryClient creates a new mock instance
+func NewMockcountryClient(ctrl *gomock.Controller) *MockcountryClient {
+	mock := &MockcountryClient{ctrl: ctrl}
+	mock.recorder = &MockcountryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockcountryClient) EXPECT() *MockcountryClientMockRecorder {
+	return m.recorder
+}
+
+// One mocks base method
+func (m *MockcountryClient) One(ctx context.Context, id int) (entity.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "One", ctx, id)
+	ret0, _ := ret[0].(entity.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// One indicates an expected call of One
+func (mr *MockcountryClientMockRecorder) One(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*MockcountryClient)(nil).One), ctx, id)
+}
+
 // Mockhasher is a mock of hasher interface
 type Mockhasher struct {
 	ctrl     *gomock.Controller
@@ -198,6 +266,20 @@ func (m *Mockhasher) EXPECT() *MockhasherMockRecorder {
 	return m.recorder
 }
 
+// Compare mocks base method
+func (m *Mockhasher) Compare(password, hashed string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", password, hashed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compare indicates an expected call of Compare
+func (mr *MockhasherMockRecorder) Compare(password, hashed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*Mockhasher)(nil).Compare), password, hashed)
+}
+
 // Hash mocks base method
 func (m *Mockhasher) Hash(password, salt string) (string, error) {
 	m.ctrl.T.Helper()
@@ -226,17 +308,3 @@ func (mr *MockhasherMockRecorder) Salt() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Salt", reflect.TypeOf((*Mockhasher)(nil).Salt))
 }
-
-// Compare mocks base method
-func (m *Mockhasher) Compare(password, hashed string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Compare", password, hashed)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Compare indicates an expected call of Compare
-func (mr *MockhasherMockRecorder) Compare(password, hashed interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*Mockhasher)(nil).Compare), password, hashed)
-}
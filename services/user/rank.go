@@ -0,0 +1,120 @@
+package user
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/faceit/test/entity"
+)
+
+// field match scores, the closer match is, the higher score is
+const (
+	scoreExact      = 1.0
+	scorePrefix     = 0.9
+	scoreWordPrefix = 0.8
+	scoreContains   = 0.7
+
+	// fuzzy match is scored by trigram similarity, that is scaled not to outrank substring matches
+	scoreSimilarityScale = 0.6
+)
+
+// rankFields holds searched fields with their weights, players are looked up by nick name mostly
+var rankFields = []struct {
+	weight float64
+	value  func(u entity.User) string
+}{
+	{1.0, func(u entity.User) string { return u.NickName }},
+	{0.8, func(u entity.User) string { return u.FirstName }},
+	{0.8, func(u entity.User) string { return u.LastName }},
+	{0.6, func(u entity.User) string { return u.Email }},
+}
+
+// Rank returns relevance of user for search query q from 0 to 1
+// user is ranked by it's best matching field, match is case insensitive
+func Rank(q string, u entity.User) float64 {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return 0
+	}
+
+	var rank float64
+
+	for _, f := range rankFields {
+		r := f.weight * fieldRank(q, strings.ToLower(f.value(u)))
+		if r > rank {
+			rank = r
+		}
+	}
+
+	return rank
+}
+
+// fieldRank returns how close field value v matches lower cased query q
+func fieldRank(q, v string) float64 {
+	switch {
+	case v == "":
+		return 0
+	case v == q:
+		return scoreExact
+	case strings.HasPrefix(v, q):
+		return scorePrefix
+	case hasWordPrefix(v, q):
+		return scoreWordPrefix
+	case strings.Contains(v, q):
+		return scoreContains
+	default:
+		return scoreSimilarityScale * similarity(q, v)
+	}
+}
+
+// hasWordPrefix checks, if any word of v starts with q
+func hasWordPrefix(v, q string) bool {
+	for _, w := range words(v) {
+		if strings.HasPrefix(w, q) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// similarity returns trigram similarity of a and b, the same way pg_trgm does:
+// number of shared trigrams divided by number of all trigrams of both strings
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// trigrams returns set of trigrams of s, every word is padded with two spaces in front and one behind
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+
+	for _, w := range words(s) {
+		r := []rune("  " + w + " ")
+
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// words splits s into words of letters and digits
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
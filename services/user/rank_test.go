@@ -0,0 +1,94 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseRank struct {
+	query    string
+	user     entity.User
+	expected float64
+}
+
+func TestRank(t *testing.T) {
+	user := entity.User{
+		FirstName: "David",
+		LastName:  "Bowie",
+		NickName:  "Star Man",
+		Email:     "ziggy@stardust.com",
+	}
+
+	for name, tc := range map[string]testCaseRank{
+		"positive_exact_nick_name": {
+			query:    "star man",
+			user:     user,
+			expected: scoreExact,
+		},
+		"positive_case_insensitive": {
+			query:    "  STAR MAN ",
+			user:     user,
+			expected: scoreExact,
+		},
+		"positive_nick_name_prefix": {
+			query:    "sta",
+			user:     user,
+			expected: scorePrefix,
+		},
+		"positive_nick_name_word_prefix": {
+			query:    "ma",
+			user:     user,
+			expected: scoreWordPrefix,
+		},
+		"positive_last_name_exact": {
+			query:    "bowie",
+			user:     user,
+			expected: 0.8 * scoreExact,
+		},
+		"positive_email_contains": {
+			query:    "dust",
+			user:     user,
+			expected: 0.6 * scoreContains,
+		},
+		"positive_fuzzy": {
+			query:    "bowei",
+			user:     user,
+			expected: 0.8 * scoreSimilarityScale * similarity("bowei", "bowie"),
+		},
+		"negative_no_match": {
+			query: "qwx",
+			user:  user,
+		},
+		"negative_empty_query": {
+			query: " ",
+			user:  user,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, Rank(tc.query, tc.user), 1e-9)
+		})
+	}
+}
+
+func TestRankOrder(t *testing.T) {
+	exact := entity.User{NickName: "neo"}
+	prefix := entity.User{NickName: "neon"}
+	fuzzy := entity.User{NickName: "nero"}
+	email := entity.User{Email: "neo@matrix.com"}
+
+	assert.Greater(t, Rank("neo", exact), Rank("neo", prefix))
+	assert.Greater(t, Rank("neo", prefix), Rank("neo", email))
+	assert.Greater(t, Rank("neo", email), Rank("neo", fuzzy))
+	assert.Greater(t, Rank("neo", fuzzy), 0.0)
+}
+
+func TestSimilarity(t *testing.T) {
+	// pg_trgm: similarity('word', 'two words') = 4 / 11
+	assert.InDelta(t, 4.0/11.0, similarity("word", "two words"), 1e-9)
+	assert.Equal(t, 1.0, similarity("same", "same"))
+	assert.Equal(t, 0.0, similarity("", "word"))
+}
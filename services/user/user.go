@@ -4,43 +4,38 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/faceit/test/entity"
 )
 
-// user get all filter title
-const (
-	filterCountry   = "country"
-	filterLastName  = "firstName"
-	filterFirstName = "lastName"
-	filterNickname  = "nickName"
-	filterEmil      = "email"
-)
-
-var (
-	titleMap = map[string]string{
-		filterLastName:  "first_name",
-		filterFirstName: "last_name",
-		filterNickname:  "nick_name",
-		filterEmil:      "email",
-	}
-)
+// searchCandidates is a number of candidates per search result, that are fetched from store to be ranked
+const searchCandidates = 5
 
 // client is a user client interface
 type client interface {
-	Create(ctx context.Context, u entity.User) (int, error)
-	Update(ctx context.Context, u entity.User) error
+	Create(ctx context.Context, u entity.User) (entity.User, error)
+	Update(ctx context.Context, u entity.User, change entity.UserChange) (entity.UserChange, error)
+	Patch(ctx context.Context, u entity.User, patch entity.UserPatch, change entity.UserChange) (entity.UserChange, error)
 	One(ctx context.Context, id int) (entity.User, error)
-	Delete(ctx context.Context, id int) error
-	All(ctx context.Context) ([]entity.User, error)
-	AllByCountry(ctx context.Context, iso2 string) ([]entity.User, error)
-	AllWithFilter(ctx context.Context, title, filter string) ([]entity.User, error)
+	Delete(ctx context.Context, u entity.User) error
+	Restore(ctx context.Context, id int) (entity.User, error)
+	All(ctx context.Context, filter entity.UserFilter, page entity.Page) ([]entity.User, error)
+	Search(ctx context.Context, q string, limit int) ([]entity.User, error)
+	Each(ctx context.Context, filter entity.UserFilter, fn func(entity.User) error) error
 }
 
 type passwordClient interface {
 	One(ctx context.Context, id int) (entity.Password, error)
 }
 
+// countryClient is a country client interface
+type countryClient interface {
+	One(ctx context.Context, id int) (entity.Country, error)
+}
+
 // hasher is a user password hasher interface
 type hasher interface {
 	Hash(password, salt string) (string, error)
@@ -52,25 +47,27 @@ type hasher interface {
 type User struct {
 	client         client
 	passwordClient passwordClient
+	countryClient  countryClient
 	hasher         hasher
 }
 
 // New creates new user service instance
-func New(c client, h hasher, p passwordClient) *User {
+func New(c client, h hasher, p passwordClient, cc countryClient) *User {
 	return &User{
 		client:         c,
 		passwordClient: p,
+		countryClient:  cc,
 		hasher:         h,
 	}
 }
 
 // Create creates new user in store
-func (u *User) Create(ctx context.Context, user entity.User) (int, error) {
+func (u *User) Create(ctx context.Context, user entity.User) (entity.User, error) {
 	user.Salt = u.hasher.Salt()
 
 	pass, err := u.hasher.Hash(user.Password, user.Salt)
 	if err != nil {
-		return 0, err
+		return entity.User{}, err
 	}
 
 	user.Password = pass
@@ -78,22 +75,59 @@ func (u *User) Create(ctx context.Context, user entity.User) (int, error) {
 	return u.client.Create(ctx, user)
 }
 
-// All returnes all users from store depending on filter and title
-func (u *User) All(ctx context.Context, title, filter string) ([]entity.User, error) {
-	switch title {
-	case filterCountry:
-		return u.client.AllByCountry(ctx, filter)
-	case filterFirstName:
-		fallthrough
-	case filterLastName:
-		fallthrough
-	case filterNickname:
-		fallthrough
-	case filterEmil:
-		return u.client.AllWithFilter(ctx, titleMap[title], filter)
-	default:
-		return u.client.All(ctx)
+// All returnes a page of users from store, that match filter
+// cursor of the next page is returned, it is empty, if page is the last one
+func (u *User) All(ctx context.Context, filter entity.UserFilter, page entity.Page) ([]entity.User, string, error) {
+	// one more user is requested to know, if there is a next page
+	query := page
+	query.Limit++
+
+	users, err := u.client.All(ctx, filter, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(users) <= page.Limit {
+		return users, "", nil
+	}
+
+	users = users[:page.Limit]
+	last := users[len(users)-1]
+
+	return users, entity.Cursor(last.ID, last.SortKeys(filter.Sort)...), nil
+}
+
+// Export passes every user, that matches filter, to fn without loading them all into memory
+func (u *User) Export(ctx context.Context, filter entity.UserFilter, fn func(entity.User) error) error {
+	return u.client.Each(ctx, filter, fn)
+}
+
+// Search returns up to s.Limit users, that match search query, the most relevant first
+// store returns candidates by rough similarity, they are ordered by Rank
+func (u *User) Search(ctx context.Context, s entity.UserSearch) ([]entity.User, error) {
+	users, err := u.client.Search(ctx, s.Query, s.Limit*searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[int]float64, len(users))
+	for _, user := range users {
+		ranks[user.ID] = Rank(s.Query, user)
 	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		if ranks[users[i].ID] != ranks[users[j].ID] {
+			return ranks[users[i].ID] > ranks[users[j].ID]
+		}
+
+		return users[i].ID < users[j].ID
+	})
+
+	if len(users) > s.Limit {
+		users = users[:s.Limit]
+	}
+
+	return users, nil
 }
 
 // One returnes one user b ID
@@ -102,23 +136,118 @@ func (u *User) One(ctx context.Context, id int) (entity.User, error) {
 }
 
 // Update updates user by ID
-func (u *User) Update(ctx context.Context, user entity.User) error {
+// returned change set holds user's previous and current state with a list of changed fields
+func (u *User) Update(ctx context.Context, user entity.User) (entity.UserChange, error) {
 	err := u.canUpdate(ctx, user)
 	if err != nil {
-		return err
+		return entity.UserChange{}, err
+	}
+
+	previous, err := u.client.One(ctx, user.ID)
+	if err != nil {
+		return entity.UserChange{}, err
+	}
+
+	// store checks version as well, this check only saves a country lookup for outdated request
+	if user.Version != previous.Version {
+		return entity.UserChange{}, &entity.VersionConflictError{ID: user.ID, Version: user.Version}
+	}
+
+	current := user
+	current.Country = previous.Country
+	current.CreatedAt = previous.CreatedAt
+
+	current, err = u.withCountry(ctx, previous, current)
+	if err != nil {
+		return entity.UserChange{}, err
+	}
+
+	// update time of current user is set by store
+	return u.client.Update(ctx, user, entity.NewUserChange(previous.ToResponse(), current.ToResponse()))
+}
+
+// Patch applies merge patch to user by patch.ID, if user was not changed since patch.Version
+// only fields, that are set by patch, are updated. Patched user is returned with it's change set for notification,
+// empty patch does not change user, so it's current state is returned
+func (u *User) Patch(ctx context.Context, patch entity.UserPatch) (entity.User, entity.UserChange, error) {
+	err := u.canUpdate(ctx, entity.User{ID: patch.ID, Password: patch.Password})
+	if err != nil {
+		return entity.User{}, entity.UserChange{}, err
+	}
+
+	previous, err := u.client.One(ctx, patch.ID)
+	if err != nil {
+		return entity.User{}, entity.UserChange{}, err
+	}
+
+	if patch.Version != previous.Version {
+		return entity.User{}, entity.UserChange{}, &entity.VersionConflictError{ID: patch.ID, Version: patch.Version}
+	}
+
+	if patch.Empty() {
+		return previous, entity.NewUserChange(previous.ToResponse(), previous.ToResponse()), nil
+	}
+
+	current, err := u.withCountry(ctx, previous, patch.Apply(previous))
+	if err != nil {
+		return entity.User{}, entity.UserChange{}, err
+	}
+
+	// update time of current user is set by store
+	change, err := u.client.Patch(ctx, current, patch, entity.NewUserChange(previous.ToResponse(), current.ToResponse()))
+	if err != nil {
+		return entity.User{}, entity.UserChange{}, err
+	}
+
+	current.UpdatedAt = change.Current.UpdatedAt
+	current.Version++
+
+	return current, change, nil
+}
+
+// withCountry sets country name of current user, it is looked up only if country was changed
+func (u *User) withCountry(ctx context.Context, previous, current entity.User) (entity.User, error) {
+	if current.CountryID == previous.CountryID {
+		return current, nil
 	}
 
-	return u.client.Update(ctx, user)
+	country, err := u.countryClient.One(ctx, current.CountryID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.User{}, fmt.Errorf("%w, country %d not found", entity.ErrValidationFailed, current.CountryID)
+	}
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	current.Country = country.Name
+
+	return current, nil
 }
 
-// Delete deletes user by id
-func (u *User) Delete(ctx context.Context, user entity.User) error {
+// Delete deletes user by id, user could be restored until it is purged
+// last known snapshot of deleted user is returned
+func (u *User) Delete(ctx context.Context, user entity.User) (entity.User, error) {
 	err := u.canUpdate(ctx, user)
 	if err != nil {
-		return err
+		return entity.User{}, err
 	}
 
-	return u.client.Delete(ctx, user.ID)
+	snapshot, err := u.client.One(ctx, user.ID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	err = u.client.Delete(ctx, snapshot)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return snapshot, nil
+}
+
+// Restore restores deleted user by id, password is not checked, as it is an admin action
+func (u *User) Restore(ctx context.Context, id int) (entity.User, error) {
+	return u.client.Restore(ctx, id)
 }
 
 // Can update is checking if action on user can be perfrmed by comparing
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/faceit/test/entity"
 	mock_user "github.com/faceit/test/services/user/mock"
@@ -14,25 +15,23 @@ import (
 var (
 	errTest = errors.New("error_test")
 
-	testUserID               = 1
-	testFirstName            = "David"
-	testLastName             = "Bovie"
-	testNickName             = "Prince"
-	testEmail                = "imAwesome@everything.go"
-	testPassword             = "qwerty"
-	testSalt                 = "test_salt"
-	testCountryID            = 100
-	testCountryName          = "UK"
-	testFilterParamCountry   = "country"
-	testFilterParamFirstName = "firstName"
-	testFilterParamLastName  = "lastName"
-	testFilterParamNickName  = "nickName"
-	testFilterParamEmail     = "email"
-
-	testFilterFirstName = "first_name"
-	testFilterLastName  = "last_name"
-	testFilterNickName  = "nick_name"
-	testFilterEmail     = "email"
+	testUserID      = 1
+	testFirstName   = "David"
+	testLastName    = "Bovie"
+	testNickName    = "Prince"
+	testEmail       = "imAwesome@everything.go"
+	testPassword    = "qwerty"
+	testSalt        = "test_salt"
+	testCountryID   = 100
+	testCountryName = "UK"
+
+	testFilter = entity.UserFilter{
+		Conditions: []entity.Condition{
+			{Field: entity.FieldFirstName, Op: entity.FilterPrefix, Value: "Da"},
+			{Field: entity.FieldCountry, Op: entity.FilterEq, Value: "GB"},
+		},
+		Sort: []entity.Sort{{Field: entity.FieldLastName, Desc: true}},
+	}
 
 	testPasswordHased = "efbwebvjbjwqencj"
 	testUser          = entity.User{
@@ -54,6 +53,18 @@ var (
 		CountryID: testCountryID,
 	}
 
+	testUserPrevious = entity.User{
+		ID:        testUserID,
+		FirstName: testFirstName,
+		LastName:  testLastName,
+		NickName:  testNickName,
+		Email:     testEmail,
+		Country:   testCountryName,
+		CountryID: testCountryID,
+		CreatedAt: time.Date(2021, 7, 10, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2021, 7, 11, 12, 0, 0, 0, time.UTC),
+	}
+
 	testUserHashedPassword = entity.User{
 		FirstName: testFirstName,
 		LastName:  testLastName,
@@ -69,6 +80,10 @@ var (
 	}
 
 	testUsers = []entity.User{testUserHashedPassword}
+
+	// one more user is requested to know, if there is a next page
+	testPage      = entity.Page{Limit: 10}
+	testQueryPage = entity.Page{Limit: 11}
 )
 
 func TestCreate(t *testing.T) {
@@ -76,8 +91,11 @@ func TestCreate(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
+		created := testUserHashedPassword
+		created.ID = testUserID
+
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Create(ctx, testUserHashedPassword).Return(testUserID, nil)
+		mockUserClient.EXPECT().Create(ctx, testUserHashedPassword).Return(created, nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Salt().Return(testSalt)
@@ -85,9 +103,9 @@ func TestCreate(t *testing.T) {
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		id, err := New(mockUserClient, mockHasher, mockPassword).Create(ctx, testUser)
+		user, err := New(mockUserClient, mockHasher, mockPassword, nil).Create(ctx, testUser)
 		assert.Nil(t, err)
-		assert.Equal(t, testUserID, id)
+		assert.Equal(t, created, user)
 	})
 
 	t.Run("negative_failed_to_salt", func(t *testing.T) {
@@ -102,9 +120,9 @@ func TestCreate(t *testing.T) {
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		id, err := New(mockUserClient, mockHasher, mockPassword).Create(ctx, testUser)
+		user, err := New(mockUserClient, mockHasher, mockPassword, nil).Create(ctx, testUser)
 		assert.ErrorIs(t, err, errTest)
-		assert.Equal(t, 0, id)
+		assert.Equal(t, entity.User{}, user)
 	})
 
 	t.Run("negative_client_error", func(t *testing.T) {
@@ -112,7 +130,7 @@ func TestCreate(t *testing.T) {
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Create(ctx, testUserHashedPassword).Return(0, errTest)
+		mockUserClient.EXPECT().Create(ctx, testUserHashedPassword).Return(entity.User{}, errTest)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Salt().Return(testSalt)
@@ -120,122 +138,176 @@ func TestCreate(t *testing.T) {
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		id, err := New(mockUserClient, mockHasher, mockPassword).Create(ctx, testUser)
+		user, err := New(mockUserClient, mockHasher, mockPassword, nil).Create(ctx, testUser)
 		assert.ErrorIs(t, err, errTest)
-		assert.Equal(t, 0, id)
+		assert.Equal(t, entity.User{}, user)
 	})
 }
 
 func TestAll(t *testing.T) {
-	t.Run("positive_all_by_country", func(t *testing.T) {
+	t.Run("positive_all_with_filter", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().AllByCountry(ctx, testCountryName).Return(testUsers, nil)
+		mockUserClient.EXPECT().All(ctx, testFilter, testQueryPage).Return(testUsers, nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, testFilterParamCountry, testCountryName)
+		users, next, err := New(mockUserClient, mockHasher, mockPassword, nil).All(ctx, testFilter, testPage)
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Empty(t, next)
+		assert.Equal(t, testUsers, users)
 	})
 
-	t.Run("positive_all_by_first_name", func(t *testing.T) {
+	t.Run("positive_all_no_filters", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().AllWithFilter(ctx, testFilterFirstName, testFirstName).Return(testUsers, nil)
+		mockUserClient.EXPECT().All(ctx, entity.UserFilter{}, testQueryPage).Return(testUsers, nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, testFilterParamFirstName, testFirstName)
+		users, next, err := New(mockUserClient, mockHasher, mockPassword, nil).All(ctx, entity.UserFilter{}, testPage)
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Empty(t, next)
+		assert.Equal(t, testUsers, users)
 	})
 
-	t.Run("positive_all_by_last_name", func(t *testing.T) {
+	t.Run("positive_next_page", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
+		users := []entity.User{{ID: 3}, {ID: 5}, {ID: 8}}
+
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().AllWithFilter(ctx, testFilterLastName, testLastName).Return(testUsers, nil)
+		mockUserClient.EXPECT().All(ctx, entity.UserFilter{}, entity.Page{After: 2, Limit: 3}).Return(users, nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, testFilterParamLastName, testLastName)
+		page, next, err := New(mockUserClient, mockHasher, mockPassword, nil).All(ctx, entity.UserFilter{}, entity.Page{After: 2, Limit: 2})
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Equal(t, users[:2], page)
+		assert.Equal(t, entity.Cursor(5), next)
 	})
 
-	t.Run("positive_all_by_nick_name", func(t *testing.T) {
+	t.Run("positive_next_page_sorted", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
+		users := []entity.User{{ID: 8, LastName: "Lee"}, {ID: 3, LastName: "Bowie"}}
+		page := entity.Page{After: 5, Keys: []string{"Mercury"}, Limit: 1}
+		query := entity.Page{After: 5, Keys: []string{"Mercury"}, Limit: 2}
+
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().AllWithFilter(ctx, testFilterNickName, testNickName).Return(testUsers, nil)
+		mockUserClient.EXPECT().All(ctx, testFilter, query).Return(users, nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, testFilterParamNickName, testNickName)
+		result, next, err := New(mockUserClient, mockHasher, mockPassword, nil).All(ctx, testFilter, page)
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Equal(t, users[:1], result)
+		assert.Equal(t, entity.Cursor(8, "Lee"), next)
 	})
 
-	t.Run("positive_all_by_email", func(t *testing.T) {
+	t.Run("negative_client_error", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().AllWithFilter(ctx, testFilterEmail, testEmail).Return(testUsers, nil)
+		mockUserClient.EXPECT().All(ctx, entity.UserFilter{}, testQueryPage).Return(nil, errTest)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, testFilterParamEmail, testEmail)
+		users, _, err := New(mockUserClient, mockHasher, mockPassword, nil).All(ctx, entity.UserFilter{}, testPage)
+		assert.Nil(t, users)
+		assert.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestExport(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().Each(ctx, testFilter, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ entity.UserFilter, fn func(entity.User) error) error {
+				return fn(testUserPrevious)
+			})
+
+		var exported []entity.User
+
+		err := New(mockUserClient, nil, nil, nil).Export(ctx, testFilter, func(u entity.User) error {
+			exported = append(exported, u)
+			return nil
+		})
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Equal(t, []entity.User{testUserPrevious}, exported)
 	})
 
-	t.Run("positive_all_no_filters", func(t *testing.T) {
+	t.Run("negative_client_error", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().All(ctx).Return(testUsers, nil)
+		mockUserClient.EXPECT().Each(ctx, testFilter, gomock.Any()).Return(errTest)
 
-		mockHasher := mock_user.NewMockhasher(ctr)
+		err := New(mockUserClient, nil, nil, nil).Export(ctx, testFilter, func(entity.User) error { return nil })
+		assert.ErrorIs(t, err, errTest)
+	})
+}
 
-		mockPassword := mock_user.NewMockpasswordClient(ctr)
+func TestSearch(t *testing.T) {
+	t.Run("positive_ranked", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		prefix := entity.User{ID: 1, NickName: "Princess"}
+		email := entity.User{ID: 2, Email: "prince@purple.rain"}
+		exact := entity.User{ID: 3, NickName: "Prince"}
+		first := entity.User{ID: 4, FirstName: "Prince"}
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, "", "")
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().Search(ctx, "prince", 3*searchCandidates).Return([]entity.User{prefix, email, exact, first}, nil)
+
+		users, err := New(mockUserClient, nil, nil, nil).Search(ctx, entity.UserSearch{Query: "prince", Limit: 3})
 		assert.Nil(t, err)
-		assert.Equal(t, testUsers, countries)
+		assert.Equal(t, []entity.User{exact, prefix, first}, users)
 	})
 
-	t.Run("negative_client_error", func(t *testing.T) {
+	t.Run("positive_no_users", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().All(ctx).Return(nil, errTest)
+		mockUserClient.EXPECT().Search(ctx, "prince", searchCandidates).Return([]entity.User{}, nil)
 
-		mockHasher := mock_user.NewMockhasher(ctr)
+		users, err := New(mockUserClient, nil, nil, nil).Search(ctx, entity.UserSearch{Query: "prince", Limit: 1})
+		assert.Nil(t, err)
+		assert.Empty(t, users)
+	})
 
-		mockPassword := mock_user.NewMockpasswordClient(ctr)
+	t.Run("negative_client_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
 
-		countries, err := New(mockUserClient, mockHasher, mockPassword).All(ctx, "", "")
-		assert.Nil(t, countries)
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().Search(ctx, "prince", searchCandidates).Return(nil, errTest)
+
+		users, err := New(mockUserClient, nil, nil, nil).Search(ctx, entity.UserSearch{Query: "prince", Limit: 1})
+		assert.Nil(t, users)
 		assert.ErrorIs(t, err, errTest)
 	})
 }
@@ -252,7 +324,7 @@ func TestOne(t *testing.T) {
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		user, err := New(mockUserClient, mockHasher, mockPassword).One(ctx, testUserID)
+		user, err := New(mockUserClient, mockHasher, mockPassword, nil).One(ctx, testUserID)
 		assert.Nil(t, err)
 		assert.Equal(t, testUser, user)
 	})
@@ -268,19 +340,85 @@ func TestOne(t *testing.T) {
 
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 
-		user, err := New(mockUserClient, mockHasher, mockPassword).One(ctx, testUserID)
+		user, err := New(mockUserClient, mockHasher, mockPassword, nil).One(ctx, testUserID)
 		assert.Equal(t, user, entity.User{})
 		assert.ErrorIs(t, err, errTest)
 	})
 }
 
+func TestRestore(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().Restore(ctx, testUserID).Return(testUserPrevious, nil)
+
+		user, err := New(mockUserClient, nil, nil, nil).Restore(ctx, testUserID)
+		assert.Nil(t, err)
+		assert.Equal(t, testUserPrevious, user)
+	})
+
+	t.Run("negative_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().Restore(ctx, testUserID).Return(entity.User{}, entity.ErrNotFound)
+
+		_, err := New(mockUserClient, nil, nil, nil).Restore(ctx, testUserID)
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	t.Run("positive", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
+		previous := testUserPrevious
+		current := testUserupdate
+		current.Country = testCountryName
+		current.CreatedAt = previous.CreatedAt
+
+		change := entity.UserChange{
+			Previous:      previous.ToResponse(),
+			Current:       current.ToResponse(),
+			ChangedFields: []string{entity.FieldNickName},
+		}
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(previous, nil)
+		mockUserClient.EXPECT().Update(ctx, testUserupdate, change).Return(change, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		mockCountry := mock_user.NewMockcountryClient(ctr)
+
+		resp, err := New(mockUserClient, mockHasher, mockPassword, mockCountry).Update(ctx, testUserupdate)
+		assert.Nil(t, err)
+		assert.Equal(t, change, resp)
+	})
+
+	t.Run("positive_country_changed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		previous := testUserPrevious
+		previous.NickName = testUserupdate.NickName
+		previous.CountryID = testCountryID + 1
+		previous.Country = "France"
+
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Update(ctx, testUserupdate).Return(nil)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(previous, nil)
+		mockUserClient.EXPECT().Update(ctx, testUserupdate, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ entity.User, change entity.UserChange) (entity.UserChange, error) {
+				return change, nil
+			})
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
@@ -288,8 +426,37 @@ func TestUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Update(ctx, testUserupdate)
+		mockCountry := mock_user.NewMockcountryClient(ctr)
+		mockCountry.EXPECT().One(ctx, testCountryID).Return(entity.Country{ID: testCountryID, Name: testCountryName}, nil)
+
+		resp, err := New(mockUserClient, mockHasher, mockPassword, mockCountry).Update(ctx, testUserupdate)
 		assert.Nil(t, err)
+		assert.Equal(t, []string{entity.FieldCountry}, resp.ChangedFields)
+		assert.Equal(t, "France", resp.Previous.Country)
+		assert.Equal(t, testCountryName, resp.Current.Country)
+	})
+
+	t.Run("negative_country_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		previous := testUserPrevious
+		previous.CountryID = testCountryID + 1
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(previous, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		mockCountry := mock_user.NewMockcountryClient(ctr)
+		mockCountry.EXPECT().One(ctx, testCountryID).Return(entity.Country{}, entity.ErrNotFound)
+
+		_, err := New(mockUserClient, mockHasher, mockPassword, mockCountry).Update(ctx, testUserupdate)
+		assert.ErrorIs(t, err, entity.ErrValidationFailed)
 	})
 
 	t.Run("negative_validation_error", func(t *testing.T) {
@@ -303,7 +470,7 @@ func TestUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(entity.Password{}, errTest)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Update(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Update(ctx, testUserupdate)
 		assert.ErrorIs(t, err, errTest)
 	})
 
@@ -319,16 +486,58 @@ func TestUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Update(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Update(ctx, testUserupdate)
 		assert.ErrorIs(t, err, entity.ErrInvalidPassword)
 	})
 
+	t.Run("negative_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(entity.User{}, entity.ErrNotFound)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Update(ctx, testUserupdate)
+		assert.ErrorIs(t, err, entity.ErrNotFound)
+	})
+
+	t.Run("negative_version_conflict", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		previous := testUserPrevious
+		previous.Version = testUserupdate.Version + 1
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(previous, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Update(ctx, testUserupdate)
+		assert.ErrorIs(t, err, entity.ErrVersionConflict)
+
+		var conflict *entity.VersionConflictError
+		assert.True(t, errors.As(err, &conflict))
+		assert.Equal(t, testUserupdate.Version, conflict.Version)
+	})
+
 	t.Run("negative_client_error", func(t *testing.T) {
 		ctr := gomock.NewController(t)
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Update(ctx, testUserupdate).Return(errTest)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Update(ctx, testUserupdate, gomock.Any()).Return(entity.UserChange{}, errTest)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
@@ -336,7 +545,170 @@ func TestUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Update(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Update(ctx, testUserupdate)
+		assert.ErrorIs(t, err, errTest)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	nickName := "Freddy"
+	country := testCountryID + 1
+
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		patch := entity.UserPatch{ID: testUserID, Password: testPassword, NickName: &nickName}
+
+		current := testUserPrevious
+		current.NickName = nickName
+
+		change := entity.NewUserChange(testUserPrevious.ToResponse(), current.ToResponse())
+		change.Current.UpdatedAt = time.Date(2021, 7, 12, 12, 0, 0, 0, time.UTC)
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Patch(ctx, current, patch, entity.NewUserChange(testUserPrevious.ToResponse(), current.ToResponse())).
+			Return(change, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		user, resp, err := New(mockUserClient, mockHasher, mockPassword, mock_user.NewMockcountryClient(ctr)).Patch(ctx, patch)
+		assert.Nil(t, err)
+		assert.Equal(t, change, resp)
+		assert.Equal(t, []string{entity.FieldNickName}, resp.ChangedFields)
+		assert.Equal(t, nickName, user.NickName)
+		assert.Equal(t, testUserPrevious.Version+1, user.Version)
+		assert.Equal(t, change.Current.UpdatedAt, user.UpdatedAt)
+	})
+
+	t.Run("positive_country_changed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		patch := entity.UserPatch{ID: testUserID, Password: testPassword, CountryID: &country}
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Patch(ctx, gomock.Any(), patch, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ entity.User, _ entity.UserPatch, change entity.UserChange) (entity.UserChange, error) {
+				return change, nil
+			})
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		mockCountry := mock_user.NewMockcountryClient(ctr)
+		mockCountry.EXPECT().One(ctx, country).Return(entity.Country{ID: country, Name: "France"}, nil)
+
+		user, resp, err := New(mockUserClient, mockHasher, mockPassword, mockCountry).Patch(ctx, patch)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{entity.FieldCountry}, resp.ChangedFields)
+		assert.Equal(t, "France", resp.Current.Country)
+		assert.Equal(t, country, user.CountryID)
+		assert.Equal(t, testNickName, user.NickName)
+	})
+
+	t.Run("positive_empty_patch", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		user, resp, err := New(mockUserClient, mockHasher, mockPassword, nil).
+			Patch(ctx, entity.UserPatch{ID: testUserID, Password: testPassword})
+		assert.Nil(t, err)
+		assert.Equal(t, testUserPrevious, user)
+		assert.Empty(t, resp.ChangedFields)
+	})
+
+	t.Run("negative_invalid_password", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(entity.ErrInvalidPassword)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		_, _, err := New(mock_user.NewMockclient(ctr), mockHasher, mockPassword, nil).
+			Patch(ctx, entity.UserPatch{ID: testUserID, Password: testPassword, NickName: &nickName})
+		assert.ErrorIs(t, err, entity.ErrInvalidPassword)
+	})
+
+	t.Run("negative_version_conflict", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		previous := testUserPrevious
+		previous.Version = 2
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(previous, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		_, _, err := New(mockUserClient, mockHasher, mockPassword, nil).
+			Patch(ctx, entity.UserPatch{ID: testUserID, Version: 1, Password: testPassword, NickName: &nickName})
+		assert.ErrorIs(t, err, entity.ErrVersionConflict)
+	})
+
+	t.Run("negative_country_not_found", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		mockCountry := mock_user.NewMockcountryClient(ctr)
+		mockCountry.EXPECT().One(ctx, country).Return(entity.Country{}, entity.ErrNotFound)
+
+		_, _, err := New(mockUserClient, mockHasher, mockPassword, mockCountry).
+			Patch(ctx, entity.UserPatch{ID: testUserID, Password: testPassword, CountryID: &country})
+		assert.ErrorIs(t, err, entity.ErrValidationFailed)
+	})
+
+	t.Run("negative_client_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockUserClient := mock_user.NewMockclient(ctr)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.UserChange{}, errTest)
+
+		mockHasher := mock_user.NewMockhasher(ctr)
+		mockHasher.EXPECT().Compare(testPassword, testUserHashedPassword.Password).Return(nil)
+
+		mockPassword := mock_user.NewMockpasswordClient(ctr)
+		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
+
+		_, _, err := New(mockUserClient, mockHasher, mockPassword, nil).
+			Patch(ctx, entity.UserPatch{ID: testUserID, Password: testPassword, NickName: &nickName})
 		assert.ErrorIs(t, err, errTest)
 	})
 }
@@ -347,7 +719,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Delete(ctx, testUserupdate.ID).Return(nil)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Delete(ctx, testUserPrevious).Return(nil)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
@@ -355,8 +728,9 @@ func TestDelete(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Delete(ctx, testUserupdate)
+		deleted, err := New(mockUserClient, mockHasher, mockPassword, nil).Delete(ctx, testUserupdate)
 		assert.Nil(t, err)
+		assert.Equal(t, testUserPrevious, deleted)
 	})
 
 	t.Run("negative_validation_error", func(t *testing.T) {
@@ -370,7 +744,7 @@ func TestDelete(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(entity.Password{}, errTest)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Delete(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Delete(ctx, testUserupdate)
 		assert.ErrorIs(t, err, errTest)
 	})
 
@@ -386,7 +760,7 @@ func TestDelete(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Delete(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Delete(ctx, testUserupdate)
 		assert.ErrorIs(t, err, entity.ErrInvalidPassword)
 	})
 
@@ -395,7 +769,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockUserClient := mock_user.NewMockclient(ctr)
-		mockUserClient.EXPECT().Delete(ctx, testUserupdate.ID).Return(errTest)
+		mockUserClient.EXPECT().One(ctx, testUserID).Return(testUserPrevious, nil)
+		mockUserClient.EXPECT().Delete(ctx, testUserPrevious).Return(errTest)
 
 		mockHasher := mock_user.NewMockhasher(ctr)
 		mockHasher.EXPECT().Compare(testUserupdate.Password, testUserHashedPassword.Password).Return(nil)
@@ -403,7 +778,7 @@ func TestDelete(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).Delete(ctx, testUserupdate)
+		_, err := New(mockUserClient, mockHasher, mockPassword, nil).Delete(ctx, testUserupdate)
 		assert.ErrorIs(t, err, errTest)
 	})
 }
@@ -421,7 +796,7 @@ func TestCanUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).canUpdate(ctx, testUserupdate)
+		err := New(mockUserClient, mockHasher, mockPassword, nil).canUpdate(ctx, testUserupdate)
 		assert.Nil(t, err)
 	})
 
@@ -436,7 +811,7 @@ func TestCanUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(entity.Password{}, errTest)
 
-		err := New(mockUserClient, mockHasher, mockPassword).canUpdate(ctx, testUserupdate)
+		err := New(mockUserClient, mockHasher, mockPassword, nil).canUpdate(ctx, testUserupdate)
 		assert.ErrorIs(t, err, errTest)
 	})
 
@@ -452,7 +827,7 @@ func TestCanUpdate(t *testing.T) {
 		mockPassword := mock_user.NewMockpasswordClient(ctr)
 		mockPassword.EXPECT().One(ctx, testUserID).Return(testPasswordStr, nil)
 
-		err := New(mockUserClient, mockHasher, mockPassword).canUpdate(ctx, testUserupdate)
+		err := New(mockUserClient, mockHasher, mockPassword, nil).canUpdate(ctx, testUserupdate)
 		assert.ErrorIs(t, err, entity.ErrInvalidPassword)
 	})
 }
@@ -0,0 +1,204 @@
+//go:generate mockgen -source ../userimport/userimport.go -destination ../userimport/mock/mock_userimport.go
+
+package userimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+)
+
+// reasonInternal is a reason of row, that failed not because of it's content, error is logged
+const reasonInternal = "internal error"
+
+// client is a user store interface
+type client interface {
+	CreateBatch(ctx context.Context, users []entity.User) ([]int, []error, error)
+}
+
+// hasher is a user password hasher interface
+type hasher interface {
+	Hash(password, salt string) (string, error)
+	Salt() string
+}
+
+// Import is a users import service struct
+type Import struct {
+	client    client
+	hasher    hasher
+	workers   int
+	batchSize int
+	log       logger.Logger
+}
+
+// New creates new Import instance
+func New(cfg config.Import, c client, h hasher, l logger.Logger) *Import {
+	return &Import{
+		client:    c,
+		hasher:    h,
+		workers:   cfg.Workers,
+		batchSize: cfg.BatchSize,
+		log:       l,
+	}
+}
+
+// pending is a valid row, that is waiting to be created
+type pending struct {
+	index int
+	user  entity.User
+}
+
+// Do creates users from rows and reports result of every row
+// invalid rows fail, rows with email or nick name of an earlier row or of an existing user are skipped,
+// passwords of valid rows are hashed concurrently, users are created in batches
+func (i *Import) Do(ctx context.Context, rows []entity.ImportRow) entity.ImportReport {
+	results := make([]entity.ImportResult, len(rows))
+	valid := make([]pending, 0, len(rows))
+
+	emails := make(map[string]int)
+	nickNames := make(map[string]int)
+
+	for n, row := range rows {
+		results[n] = entity.ImportResult{Row: row.Row}
+
+		err := row.Err
+		if err == nil {
+			err = row.User.Validate()
+		}
+
+		if err != nil {
+			results[n].Status, results[n].Reason = entity.ImportFailed, err.Error()
+			continue
+		}
+
+		// users are matched case insensitive, as emails and nick names are
+		email, nickName := strings.ToLower(row.User.Email), strings.ToLower(row.User.NickName)
+
+		if first, ok := emails[email]; ok {
+			results[n].Status, results[n].Reason = entity.ImportSkipped, fmt.Sprintf("email is the same as in row %d", first)
+			continue
+		}
+
+		if first, ok := nickNames[nickName]; ok {
+			results[n].Status, results[n].Reason = entity.ImportSkipped, fmt.Sprintf("nick name is the same as in row %d", first)
+			continue
+		}
+
+		emails[email], nickNames[nickName] = row.Row, row.Row
+
+		valid = append(valid, pending{index: n, user: row.User.ToUser()})
+	}
+
+	hashed := i.hash(ctx, valid, results)
+
+	for start := 0; start < len(hashed); start += i.batchSize {
+		end := start + i.batchSize
+		if end > len(hashed) {
+			end = len(hashed)
+		}
+
+		i.create(ctx, hashed[start:end], results)
+	}
+
+	return entity.NewImportReport(results)
+}
+
+// hash hashes passwords of users on a pool of workers, failed users are reported in results
+// users, that were hashed successfully, are returned in the same order
+func (i *Import) hash(ctx context.Context, users []pending, results []entity.ImportResult) []pending {
+	jobs := make(chan int)
+	errs := make([]error, len(users))
+
+	wg := &sync.WaitGroup{}
+
+	for w := 0; w < i.workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for n := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[n] = err
+					continue
+				}
+
+				users[n].user.Salt = i.hasher.Salt()
+				users[n].user.Password, errs[n] = i.hasher.Hash(users[n].user.Password, users[n].user.Salt)
+			}
+		}()
+	}
+
+	for n := range users {
+		jobs <- n
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	hashed := users[:0]
+
+	for n, u := range users {
+		if errs[n] != nil {
+			i.fail(ctx, results, u.index, errs[n])
+			continue
+		}
+
+		hashed = append(hashed, u)
+	}
+
+	return hashed
+}
+
+// create creates batch of users and reports result of every one in results
+func (i *Import) create(ctx context.Context, batch []pending, results []entity.ImportResult) {
+	users := make([]entity.User, len(batch))
+	for n, p := range batch {
+		users[n] = p.user
+	}
+
+	ids, errs, err := i.client.CreateBatch(ctx, users)
+	if err != nil {
+		for _, p := range batch {
+			i.fail(ctx, results, p.index, err)
+		}
+
+		return
+	}
+
+	for n, p := range batch {
+		// user, that exists already, is not an error of import, it could be imported earlier
+		if errors.Is(errs[n], entity.ErrUserExist) {
+			results[p.index].Status, results[p.index].Reason = entity.ImportSkipped, errs[n].Error()
+			continue
+		}
+
+		if errs[n] != nil {
+			i.fail(ctx, results, p.index, errs[n])
+			continue
+		}
+
+		results[p.index].Status, results[p.index].ID = entity.ImportCreated, ids[n]
+	}
+}
+
+// fail reports row as failed, validation errors are reported as is, the others are logged
+// and reported as internal, so store details are not exposed
+func (i *Import) fail(ctx context.Context, results []entity.ImportResult, index int, err error) {
+	results[index].Status = entity.ImportFailed
+
+	if errors.Is(err, entity.ErrValidationFailed) {
+		results[index].Reason = err.Error()
+		return
+	}
+
+	i.log.Errorf(ctx, "failed to import row %d, error: %s", results[index].Row, err)
+
+	results[index].Reason = reasonInternal
+}
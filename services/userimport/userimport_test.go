@@ -0,0 +1,195 @@
+package userimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	mock_userimport "github.com/faceit/test/services/userimport/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTest = errors.New("error_test")
+
+	testConfig = config.Import{Workers: 2, BatchSize: 2}
+	testSalt   = "4"
+)
+
+func testRequest(n int) entity.UserRequest {
+	return entity.UserRequest{
+		FirstName: "David",
+		LastName:  "Bowie",
+		NickName:  fmt.Sprintf("star man %d", n),
+		Email:     fmt.Sprintf("david%d@bowie.com", n),
+		Password:  "ziggy stardust",
+		CountryID: 1,
+	}
+}
+
+func testUser(n int) entity.User {
+	u := testRequest(n).ToUser()
+	u.Password = "hashed_" + u.Password
+	u.Salt = testSalt
+
+	return u
+}
+
+func newTestHasher(ctr *gomock.Controller, err error) *mock_userimport.Mockhasher {
+	mockHasher := mock_userimport.NewMockhasher(ctr)
+	mockHasher.EXPECT().Salt().Return(testSalt).AnyTimes()
+	mockHasher.EXPECT().Hash(gomock.Any(), testSalt).DoAndReturn(func(password, _ string) (string, error) {
+		if err != nil {
+			return "", err
+		}
+
+		return "hashed_" + password, nil
+	}).AnyTimes()
+
+	return mockHasher
+}
+
+func newTestLogger(ctr *gomock.Controller) logger.Logger {
+	mockLogger := mock_logger.NewMocklog(ctr)
+	mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+
+	return logger.New(mockLogger)
+}
+
+func TestDo(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		invalid := testRequest(3)
+		invalid.Password = "short"
+
+		duplicate := testRequest(4)
+		duplicate.Email = "DAVID1@bowie.com"
+
+		rows := []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+			{Row: 2, User: testRequest(2)},
+			{Row: 3, User: invalid},
+			{Row: 4, User: duplicate},
+			{Row: 5, Err: fmt.Errorf("%w, invalid json", entity.ErrValidationFailed)},
+			{Row: 6, User: testRequest(6)},
+		}
+
+		countryErr := fmt.Errorf("%w, country 1 not found", entity.ErrValidationFailed)
+
+		mockClient := mock_userimport.NewMockclient(ctr)
+		gomock.InOrder(
+			mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(1), testUser(2)}).Return([]int{10, 11}, []error{nil, nil}, nil),
+			mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(6)}).Return([]int{0}, []error{countryErr}, nil),
+		)
+
+		report := New(testConfig, mockClient, newTestHasher(ctr, nil), newTestLogger(ctr)).Do(ctx, rows)
+		assert.Equal(t, entity.ImportReport{
+			Created: 2,
+			Skipped: 1,
+			Failed:  3,
+			Rows: []entity.ImportResult{
+				{Row: 1, Status: entity.ImportCreated, ID: 10},
+				{Row: 2, Status: entity.ImportCreated, ID: 11},
+				{Row: 3, Status: entity.ImportFailed, Reason: "validation failed, password must be at least 7 charecters long"},
+				{Row: 4, Status: entity.ImportSkipped, Reason: "email is the same as in row 1"},
+				{Row: 5, Status: entity.ImportFailed, Reason: "validation failed, invalid json"},
+				{Row: 6, Status: entity.ImportFailed, Reason: countryErr.Error()},
+			},
+		}, report)
+	})
+
+	t.Run("positive_duplicated_nick_name", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		duplicate := testRequest(2)
+		duplicate.NickName = "Star Man 1"
+
+		mockClient := mock_userimport.NewMockclient(ctr)
+		mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(1)}).Return([]int{10}, []error{nil}, nil)
+
+		report := New(testConfig, mockClient, newTestHasher(ctr, nil), newTestLogger(ctr)).Do(ctx, []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+			{Row: 2, User: duplicate},
+		})
+		assert.Equal(t, []entity.ImportResult{
+			{Row: 1, Status: entity.ImportCreated, ID: 10},
+			{Row: 2, Status: entity.ImportSkipped, Reason: "nick name is the same as in row 1"},
+		}, report.Rows)
+	})
+
+	t.Run("positive_user_exist", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_userimport.NewMockclient(ctr)
+		mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(1)}).Return([]int{0}, []error{&entity.UserExistError{Field: entity.FieldEmail}}, nil)
+
+		report := New(testConfig, mockClient, newTestHasher(ctr, nil), newTestLogger(ctr)).Do(ctx, []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+		})
+		assert.Equal(t, entity.ImportReport{
+			Skipped: 1,
+			Rows:    []entity.ImportResult{{Row: 1, Status: entity.ImportSkipped, Reason: "user exist, email is taken"}},
+		}, report)
+	})
+
+	t.Run("negative_hash_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		report := New(testConfig, mock_userimport.NewMockclient(ctr), newTestHasher(ctr, errTest), newTestLogger(ctr)).Do(ctx, []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+		})
+		assert.Equal(t, entity.ImportReport{
+			Failed: 1,
+			Rows:   []entity.ImportResult{{Row: 1, Status: entity.ImportFailed, Reason: reasonInternal}},
+		}, report)
+	})
+
+	t.Run("negative_batch_failed", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockClient := mock_userimport.NewMockclient(ctr)
+		gomock.InOrder(
+			mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(1), testUser(2)}).Return(nil, nil, errTest),
+			mockClient.EXPECT().CreateBatch(ctx, []entity.User{testUser(3)}).Return([]int{12}, []error{nil}, nil),
+		)
+
+		report := New(testConfig, mockClient, newTestHasher(ctr, nil), newTestLogger(ctr)).Do(ctx, []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+			{Row: 2, User: testRequest(2)},
+			{Row: 3, User: testRequest(3)},
+		})
+		assert.Equal(t, entity.ImportReport{
+			Created: 1,
+			Failed:  2,
+			Rows: []entity.ImportResult{
+				{Row: 1, Status: entity.ImportFailed, Reason: reasonInternal},
+				{Row: 2, Status: entity.ImportFailed, Reason: reasonInternal},
+				{Row: 3, Status: entity.ImportCreated, ID: 12},
+			},
+		}, report)
+	})
+
+	t.Run("negative_context_done", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		report := New(testConfig, mock_userimport.NewMockclient(ctr), newTestHasher(ctr, nil), newTestLogger(ctr)).Do(ctx, []entity.ImportRow{
+			{Row: 1, User: testRequest(1)},
+		})
+		assert.Equal(t, 1, report.Failed)
+	})
+}
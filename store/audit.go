@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+)
+
+// user_audit table parameters and query
+const (
+	auditTable  = `user_audit`
+	auditParams = `audit_id, user_id, action, actor, process_id, source_ip, old_value, new_value, created_at`
+
+	createAuditQuery = `INSERT INTO ` + auditTable +
+		` (user_id, action, actor, process_id, source_ip, old_value, new_value) VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	// history is paginated by audit_id from the latest record, so new records do not shift pages
+	selectAuditQuery = `SELECT ` + auditParams + ` FROM ` + auditTable +
+		` WHERE user_id = $1 AND ($2 = 0 OR audit_id < $2) ORDER BY audit_id DESC LIMIT $3;`
+
+	// lengths of user_audit varchar columns
+	auditActorMaxLen     = 100
+	auditProcessIDMaxLen = 36
+	auditSourceIPMaxLen  = 45
+)
+
+// Audit is a user audit store implementation
+type Audit struct {
+	*sql.DB
+}
+
+// NewAudit creates a new Audit instance
+func NewAudit(db *sql.DB) *Audit {
+	return &Audit{
+		db,
+	}
+}
+
+// History returns page of user's audit records, the latest first
+func (a *Audit) History(ctx context.Context, userID int, page entity.Page) ([]entity.Audit, error) {
+	rows, err := a.QueryContext(ctx, selectAuditQuery, userID, page.After, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("query failed, %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	records := []entity.Audit{}
+
+	for rows.Next() {
+		var (
+			record   entity.Audit
+			old, new sql.NullString
+		)
+
+		err = rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.Action,
+			&record.Actor,
+			&record.ProcessID,
+			&record.SourceIP,
+			&old,
+			&new,
+			&record.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan results failed, %w", err)
+		}
+
+		if old.Valid {
+			record.Old = json.RawMessage(old.String)
+		}
+
+		if new.Valid {
+			record.New = json.RawMessage(new.String)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// createAudit writes a change of user into audit within tx, so change and it's record are committed together
+// actor, processID and source IP are taken from ctx, old or new is nil, if there is no user before or after change
+func createAudit(ctx context.Context, tx *sql.Tx, userID int, action string, old, new *entity.UserResponse) error {
+	oldValue, err := auditValue(old)
+	if err != nil {
+		return err
+	}
+
+	newValue, err := auditValue(new)
+	if err != nil {
+		return err
+	}
+
+	// values are truncated to the columns, as record is written in the same transaction as the change,
+	// and must not fail it
+	_, err = tx.ExecContext(ctx, createAuditQuery, userID, action, truncate(cont.Actor(ctx), auditActorMaxLen),
+		truncate(cont.ProcessID(ctx), auditProcessIDMaxLen), truncate(cont.SourceIP(ctx), auditSourceIPMaxLen), oldValue, newValue)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	return nil
+}
+
+// auditValue returns json of user, NULL is returned for missing user
+func auditValue(user *entity.UserResponse) (sql.NullString, error) {
+	if user == nil {
+		return sql.NullString{}, nil
+	}
+
+	value, err := json.Marshal(user)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal audit value, %w", err)
+	}
+
+	return sql.NullString{String: string(value), Valid: true}, nil
+}
+
+// truncate returns s, cut to at most n characters
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n])
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/faceit/test/entity"
@@ -64,6 +65,9 @@ func (c *Country) One(ctx context.Context, id int) (entity.Country, error) {
 		&country.ID,
 		&country.Name,
 		&country.ISO2)
+	if errors.Is(err, sql.ErrNoRows) {
+		return country, entity.ErrNotFound
+	}
 	if err != nil {
 		err = fmt.Errorf("query failed, %w", err)
 	}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/faceit/test/entity"
+)
+
+// notifications_dead_letter table parameters and query
+const (
+	deadLetterTable  = `notifications_dead_letter`
+	deadLetterParams = `dead_letter_id, event_id, consumer, last_error, attempts, payload, created_at, updated_at`
+
+	createDeadLetterQuery = `INSERT INTO ` + deadLetterTable +
+		` (event_id, consumer, last_error, attempts, payload) VALUES ($1, $2, $3, $4, $5) RETURNING dead_letter_id;`
+
+	selectOneDeadLetterQuery = `SELECT ` + deadLetterParams + ` FROM ` + deadLetterTable + ` WHERE dead_letter_id = $1;`
+
+	selectAllDeadLettersQuery = `SELECT ` + deadLetterParams + ` FROM ` + deadLetterTable + ` ORDER BY dead_letter_id;`
+
+	selectAllDeadLettersByConsumerQuery = `SELECT ` + deadLetterParams + ` FROM ` + deadLetterTable +
+		` WHERE consumer = $1 ORDER BY dead_letter_id;`
+
+	updateDeadLetterFailedQuery = `UPDATE ` + deadLetterTable +
+		` SET last_error = $1, attempts = attempts + $2, updated_at = (now() at time zone 'utc') WHERE dead_letter_id = $3;`
+
+	deleteDeadLetterQuery     = `DELETE FROM ` + deadLetterTable + ` WHERE dead_letter_id = $1;`
+	deleteAllDeadLettersQuery = `DELETE FROM ` + deadLetterTable + `;`
+)
+
+// DeadLetter is a dead letter store implementation
+type DeadLetter struct {
+	*sql.DB
+}
+
+// NewDeadLetter creates a new DeadLetter instance
+func NewDeadLetter(db *sql.DB) *DeadLetter {
+	return &DeadLetter{
+		db,
+	}
+}
+
+// CreateAll creates dead letter records in one transaction and returns their ids
+// either all of them are stored, or none, so retry of failed call does not duplicate them
+func (d *DeadLetter) CreateAll(ctx context.Context, dls []entity.DeadLetter) ([]int, error) {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction failed, %w", err)
+	}
+
+	ids := make([]int, 0, len(dls))
+
+	for _, dl := range dls {
+		var id int
+
+		err = tx.QueryRowContext(ctx, createDeadLetterQuery, dl.EventID, dl.Consumer, dl.LastError, dl.Attempts, []byte(dl.Payload)).Scan(&id)
+		if err != nil {
+			return nil, d.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+		}
+
+		ids = append(ids, id)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, d.rollbackTransaction(tx, err)
+	}
+
+	return ids, nil
+}
+
+func (d *DeadLetter) rollbackTransaction(tx *sql.Tx, e error) error {
+	if err := tx.Rollback(); err != nil {
+		return fmt.Errorf("%w, rollback transaction failed. error: %s", e, err)
+	}
+
+	return e
+}
+
+// One returns one dead letter record by id
+func (d *DeadLetter) One(ctx context.Context, id int) (entity.DeadLetter, error) {
+	dl, err := scanDeadLetter(d.QueryRowContext(ctx, selectOneDeadLetterQuery, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return dl, entity.ErrNotFound
+	}
+	if err != nil {
+		return dl, fmt.Errorf("query failed, %w", err)
+	}
+
+	return dl, nil
+}
+
+// All returns all dead letter records, or only consumer's ones, if consumer is not empty
+func (d *DeadLetter) All(ctx context.Context, consumer string) ([]entity.DeadLetter, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+
+	if consumer == "" {
+		rows, err = d.QueryContext(ctx, selectAllDeadLettersQuery)
+	} else {
+		rows, err = d.QueryContext(ctx, selectAllDeadLettersByConsumerQuery, consumer)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("query failed, %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	dls := []entity.DeadLetter{}
+
+	for rows.Next() {
+		dl, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan results failed, %w", err)
+		}
+
+		dls = append(dls, dl)
+	}
+
+	return dls, rows.Err()
+}
+
+// Failed increases attempts of dead letter record by attempts and sets it's last error
+func (d *DeadLetter) Failed(ctx context.Context, id, attempts int, lastError string) error {
+	_, err := d.ExecContext(ctx, updateDeadLetterFailedQuery, lastError, attempts, id)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes dead letter record by id
+func (d *DeadLetter) Delete(ctx context.Context, id int) error {
+	res, err := d.ExecContext(ctx, deleteDeadLetterQuery, id)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteAll deletes all dead letter records and returns their number
+func (d *DeadLetter) DeleteAll(ctx context.Context) (int, error) {
+	res, err := d.ExecContext(ctx, deleteAllDeadLettersQuery)
+	if err != nil {
+		return 0, fmt.Errorf("query failed, %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("query failed, %w", err)
+	}
+
+	return int(n), nil
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDeadLetter(s scanner) (entity.DeadLetter, error) {
+	dl := entity.DeadLetter{}
+
+	var payload []byte
+
+	err := s.Scan(
+		&dl.ID,
+		&dl.EventID,
+		&dl.Consumer,
+		&dl.LastError,
+		&dl.Attempts,
+		&payload,
+		&dl.CreatedAt,
+		&dl.UpdatedAt)
+
+	dl.Payload = payload
+
+	return dl, err
+}
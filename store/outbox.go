@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+
+	"github.com/google/uuid"
+)
+
+// users_outbox table parameters and query
+const (
+	outboxTable  = `users_outbox`
+	outboxParams = `outbox_id, event_id, action, COALESCE(country_id, 0), payload, created_at`
+
+	createOutboxQuery = `INSERT INTO ` + outboxTable + ` (event_id, action, country_id, payload) VALUES ($1, $2, NULLIF($3, 0), $4);`
+
+	// record is leased to a relay by a single statement, so replicas never relay the same record at once,
+	// lease of failed or stopped relay expires, and record is claimed again
+	claimOutboxQuery = `UPDATE ` + outboxTable + ` SET locked_until = (now() at time zone 'utc') + $2 * interval '1 millisecond'` +
+		` WHERE outbox_id = (SELECT outbox_id FROM ` + outboxTable +
+		` WHERE delivered_at IS NULL AND created_at <= (now() at time zone 'utc') - $1 * interval '1 second'` +
+		` AND (locked_until IS NULL OR locked_until < (now() at time zone 'utc'))` +
+		` ORDER BY outbox_id LIMIT 1 FOR UPDATE SKIP LOCKED)` +
+		` RETURNING ` + outboxParams + `;`
+
+	updateOutboxDeliveredQuery = `UPDATE ` + outboxTable +
+		` SET delivered_at = (now() at time zone 'utc') WHERE event_id = $1 AND delivered_at IS NULL;`
+)
+
+// Outbox is an outbox store implementation
+type Outbox struct {
+	*sql.DB
+}
+
+// NewOutbox creates a new Outbox instance
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{
+		db,
+	}
+}
+
+// Claim leases the oldest not delivered outbox record, that was created at least delay seconds ago
+// and is not leased by other relays, for lease and passes it to deliver.
+// Record is marked as delivered, if deliver succeeds, otherwise it is claimed again after lease expires.
+// false is returned, if there is no record to claim
+func (o *Outbox) Claim(ctx context.Context, delay int, lease time.Duration, deliver func(entity.Outbox) error) (bool, error) {
+	record := entity.Outbox{}
+
+	err := o.QueryRowContext(ctx, claimOutboxQuery, delay, lease.Milliseconds()).Scan(
+		&record.ID,
+		&record.EventID,
+		&record.Action,
+		&record.CountryID,
+		&record.Payload,
+		&record.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("query failed, %w", err)
+	}
+
+	err = deliver(record)
+	if err != nil {
+		return true, err
+	}
+
+	return true, o.Delivered(ctx, record.EventID)
+}
+
+// Delivered marks outbox record with eventID as delivered
+func (o *Outbox) Delivered(ctx context.Context, eventID string) error {
+	_, err := o.ExecContext(ctx, updateOutboxDeliveredQuery, eventID)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	return nil
+}
+
+// createOutbox writes a user event into outbox within tx
+// event id should be a processID of the request, so queued message could mark the record as delivered,
+// user's country is kept to match subscriptions on relay
+func createOutbox(ctx context.Context, tx *sql.Tx, action string, countryID int, event entity.CloudEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload, %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, createOutboxQuery, event.ID, action, countryID, payload)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	return nil
+}
+
+// eventID returns processID from context, or a new uuid, if one is missing
+func eventID(ctx context.Context) string {
+	id := cont.ProcessID(ctx)
+	if id == "" {
+		return uuid.New().String()
+	}
+
+	return id
+}
@@ -15,7 +15,9 @@ const (
 	passwordParams = `password_id, pwd, salt`
 
 	createPasswordQuery = `INSERT INTO ` + passwordTable + ` ( ` + passwordParams + ` ) VALUES ($1, $2, $3);`
-	selectPassqordQuery = `SELECT ` + passwordParams + ` FROM ` + passwordTable + ` WHERE password_id = $1;`
+	// password of deleted user is kept for restore, but user can not be authorized by it
+	selectPassqordQuery = `SELECT ` + passwordParams + ` FROM ` + passwordTable + ` WHERE password_id = $1` +
+		` AND password_id IN (SELECT user_id FROM ` + userTable + ` WHERE deleted_at IS NULL);`
 	updatePasswordQuery = `UPDATE ` + passwordTable + ` SET pwd = $1 , salt = $2 WHERE password_id = $3;`
 	deletePassqordQuery = `DELETE FROM ` + passwordTable + ` WHERE password_id = $1;`
 )
@@ -33,15 +35,39 @@ func NewPassword(db *sql.DB) *Password {
 }
 
 // Update updates users_password record in database by id
+// password change is written into audit without any values
 func (p *Password) Update(ctx context.Context, userID int, hash, salt string) error {
-	_, err := p.ExecContext(ctx, updatePasswordQuery, hash, salt, userID)
+	tx, err := p.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("query failed, %w", err)
+		return fmt.Errorf("begin transaction failed, %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, updatePasswordQuery, hash, salt, userID)
+	if err != nil {
+		return p.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+	}
+
+	err = createAudit(ctx, tx, userID, entity.AuditActionPassword, nil, nil)
+	if err != nil {
+		return p.rollbackTransaction(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return p.rollbackTransaction(tx, err)
 	}
 
 	return nil
 }
 
+func (p *Password) rollbackTransaction(tx *sql.Tx, e error) error {
+	if err := tx.Rollback(); err != nil {
+		return fmt.Errorf("%w, rollback transaction failed. error: %s", e, err)
+	}
+
+	return e
+}
+
 // One returns one record from users_password by id
 func (p *Password) One(ctx context.Context, id int) (entity.Password, error) {
 	pwd := entity.Password{}
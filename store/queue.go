@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faceit/test/entity"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// notification_queue table parameters and query
+const (
+	queueTable = `notification_queue`
+
+	// QueueChannel is a channel, queue inserts are notified to
+	QueueChannel = `notification_queue`
+
+	// queueDepthTable keeps number of messages in queue in a single row
+	queueDepthTable = `notification_queue_depth`
+
+	// message is inserted only if queue is not full, depth row is updated by the same statement,
+	// concurrent enqueues wait only for the row lock of depth and re-check it, so capacity is never exceeded
+	enqueueQuery = `WITH slot AS (UPDATE ` + queueDepthTable + ` SET depth = depth + 1 WHERE depth < $5 RETURNING depth)` +
+		` INSERT INTO ` + queueTable + ` (event_id, ordering_key, message, consumers)` +
+		` SELECT $1, $2, $3, $4 FROM slot;`
+
+	// message is leased to a worker by a single statement, so no transaction is held during delivery,
+	// lease of stopped worker expires, and message is claimed again
+	claimQuery = `UPDATE ` + queueTable + ` SET locked_until = (now() at time zone 'utc') + $1 * interval '1 millisecond',` +
+		` claimed_by = $2 WHERE queue_id = (SELECT queue_id FROM ` + queueTable +
+		` WHERE locked_until IS NULL OR locked_until < (now() at time zone 'utc')` +
+		` ORDER BY queue_id LIMIT 1 FOR UPDATE SKIP LOCKED)` +
+		` RETURNING queue_id, event_id, ordering_key, message, consumers;`
+
+	// message is claimed only if there is no earlier message with the same key,
+	// leased messages stay in table, so message in delivery keeps the next ones of it's key waiting
+	claimOrderedQuery = `UPDATE ` + queueTable + ` SET locked_until = (now() at time zone 'utc') + $1 * interval '1 millisecond',` +
+		` claimed_by = $2 WHERE queue_id = (SELECT q.queue_id FROM ` + queueTable + ` AS q` +
+		` WHERE (q.locked_until IS NULL OR q.locked_until < (now() at time zone 'utc'))` +
+		` AND (q.ordering_key = '' OR NOT EXISTS (SELECT 1 FROM ` + queueTable + ` AS p` +
+		` WHERE p.ordering_key = q.ordering_key AND p.queue_id < q.queue_id))` +
+		` ORDER BY q.queue_id LIMIT 1 FOR UPDATE OF q SKIP LOCKED)` +
+		` RETURNING queue_id, event_id, ordering_key, message, consumers;`
+
+	// delivered message is removed and failed one is released only by the worker, that holds it's lease,
+	// depth is decreased by the same statement, only if message was removed
+	deleteQueueQuery = `WITH removed AS (DELETE FROM ` + queueTable + ` WHERE queue_id = $1 AND claimed_by = $2 RETURNING queue_id)` +
+		` UPDATE ` + queueDepthTable + ` SET depth = depth - 1 WHERE EXISTS (SELECT 1 FROM removed);`
+	releaseQueueQuery = `UPDATE ` + queueTable + ` SET locked_until = NULL, claimed_by = '' WHERE queue_id = $1 AND claimed_by = $2;`
+
+	queueStatsQuery = `SELECT count(*), min(enqueued_at) FROM ` + queueTable + `;`
+)
+
+// Queue is a shared notification queue store implementation
+type Queue struct {
+	*sql.DB
+}
+
+// NewQueue creates a new Queue instance
+func NewQueue(db *sql.DB) *Queue {
+	return &Queue{
+		db,
+	}
+}
+
+// Enqueue inserts message into queue
+// entity.ErrQueueFull is returned, if there are capacity messages in queue already
+func (q *Queue) Enqueue(ctx context.Context, message entity.NotifierMessage, capacity int) error {
+	body, err := json.Marshal(message.Message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message, %w", err)
+	}
+
+	res, err := q.ExecContext(ctx, enqueueQuery, message.ID, message.Key, body, pq.Array(message.Consumers), capacity)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	if n == 0 {
+		return entity.ErrQueueFull
+	}
+
+	return nil
+}
+
+// Claim leases the oldest message, that is not leased by other workers, for lease and passes it to deliver
+// message is removed from queue, if deliver succeeds, and is released for other workers otherwise.
+// Delivery runs outside of transaction, if worker stops in the middle, message is claimed again after lease expires.
+// if ordered is true, message is claimed only after all earlier messages with the same key are removed.
+// false is returned, if there is no message to claim
+func (q *Queue) Claim(ctx context.Context, ordered bool, lease time.Duration,
+	deliver func(entity.NotifierMessage) error) (bool, error) {
+	query := claimQuery
+	if ordered {
+		query = claimOrderedQuery
+	}
+
+	var (
+		id      int64
+		message entity.NotifierMessage
+		body    string
+		token   = uuid.New().String()
+	)
+
+	err := q.QueryRowContext(ctx, query, lease.Milliseconds(), token).
+		Scan(&id, &message.ID, &message.Key, &body, pq.Array(&message.Consumers))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("query failed, %w", err)
+	}
+
+	message.Message = json.RawMessage(body)
+
+	err = deliver(message)
+	if err != nil {
+		// release could fail on shutdown, then message waits for lease to expire
+		_, _ = q.ExecContext(ctx, releaseQueueQuery, id, token)
+		return true, err
+	}
+
+	_, err = q.ExecContext(ctx, deleteQueueQuery, id, token)
+	if err != nil {
+		return true, fmt.Errorf("query failed, %w", err)
+	}
+
+	return true, nil
+}
+
+// Stats returns number of messages in queue and enqueue time of the oldest one
+// zero time is returned, if queue is empty
+func (q *Queue) Stats(ctx context.Context) (int, time.Time, error) {
+	var (
+		count  int
+		oldest sql.NullTime
+	)
+
+	err := q.QueryRowContext(ctx, queueStatsQuery).Scan(&count, &oldest)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("query failed, %w", err)
+	}
+
+	return count, oldest.Time, nil
+}
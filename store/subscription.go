@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faceit/test/entity"
+
+	"github.com/lib/pq"
+)
+
+// notification_subscriptions table parameters and query
+const (
+	subscriptionTable  = `notification_subscriptions`
+	subscriptionParams = `subscription_id, url, actions, country_id, enabled, secret, previous_secret, previous_secret_expires_at,` +
+		` batch_size, batch_window, batch_format, created_at, updated_at`
+
+	// postgres foreign_key_violation error code
+	foreignKeyViolation = "23503"
+
+	createSubscriptionQuery = `INSERT INTO ` + subscriptionTable +
+		` (url, actions, country_id, enabled, secret, batch_size, batch_window, batch_format)` +
+		` VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, $8) RETURNING subscription_id;`
+
+	selectOneSubscriptionQuery = `SELECT ` + subscriptionParams + ` FROM ` + subscriptionTable + ` WHERE subscription_id = $1;`
+
+	selectAllSubscriptionsQuery = `SELECT ` + subscriptionParams + ` FROM ` + subscriptionTable + ` ORDER BY subscription_id;`
+
+	// secret is kept, if new one is empty
+	updateSubscriptionQuery = `UPDATE ` + subscriptionTable +
+		` SET url = $1, actions = $2, country_id = NULLIF($3, 0), enabled = $4, secret = COALESCE(NULLIF($5, ''), secret),` +
+		` batch_size = $6, batch_window = $7, batch_format = $8, updated_at = (now() at time zone 'utc') WHERE subscription_id = $9;`
+
+	rotateSubscriptionSecretQuery = `UPDATE ` + subscriptionTable +
+		` SET previous_secret = secret, previous_secret_expires_at = $1, secret = $2, updated_at = (now() at time zone 'utc')` +
+		` WHERE subscription_id = $3;`
+
+	deleteSubscriptionQuery = `DELETE FROM ` + subscriptionTable + ` WHERE subscription_id = $1;`
+)
+
+// Subscription is a subscription store implementation
+type Subscription struct {
+	*sql.DB
+}
+
+// NewSubscription creates a new Subscription instance
+func NewSubscription(db *sql.DB) *Subscription {
+	return &Subscription{
+		db,
+	}
+}
+
+// Create creates a new subscription record in database
+func (s *Subscription) Create(ctx context.Context, sub entity.Subscription) (int, error) {
+	var id int
+
+	batch := subscriptionBatch(sub)
+
+	err := s.QueryRowContext(ctx, createSubscriptionQuery,
+		sub.URL, pq.Array(sub.Actions), sub.CountryID, sub.Enabled, sub.Secret, batch.Size, batch.Window, batch.Format).Scan(&id)
+	if err != nil {
+		return 0, subscriptionError(err, sub)
+	}
+
+	return id, nil
+}
+
+// One returns one subscription record by id
+func (s *Subscription) One(ctx context.Context, id int) (entity.Subscription, error) {
+	sub, err := scanSubscription(s.QueryRowContext(ctx, selectOneSubscriptionQuery, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return sub, entity.ErrNotFound
+	}
+	if err != nil {
+		return sub, fmt.Errorf("query failed, %w", err)
+	}
+
+	return sub, nil
+}
+
+// All returns all subscription records
+func (s *Subscription) All(ctx context.Context) ([]entity.Subscription, error) {
+	rows, err := s.QueryContext(ctx, selectAllSubscriptionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("query failed, %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	subs := []entity.Subscription{}
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan results failed, %w", err)
+		}
+
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// Update updates subscription record
+func (s *Subscription) Update(ctx context.Context, sub entity.Subscription) error {
+	batch := subscriptionBatch(sub)
+
+	res, err := s.ExecContext(ctx, updateSubscriptionQuery,
+		sub.URL, pq.Array(sub.Actions), sub.CountryID, sub.Enabled, sub.Secret, batch.Size, batch.Window, batch.Format, sub.ID)
+	if err != nil {
+		return subscriptionError(err, sub)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// Rotate replaces subscription's secret with a new one
+// current secret becomes previous one and stays active until expiresAt
+func (s *Subscription) Rotate(ctx context.Context, id int, secret string, expiresAt time.Time) error {
+	res, err := s.ExecContext(ctx, rotateSubscriptionSecretQuery, expiresAt, secret, id)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes subscription record by id
+func (s *Subscription) Delete(ctx context.Context, id int) error {
+	res, err := s.ExecContext(ctx, deleteSubscriptionQuery, id)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// subscriptionError returns validation error, if subscription references country, that does not exist
+func subscriptionError(err error, sub entity.Subscription) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w, country %d does not exist", entity.ErrValidationFailed, sub.CountryID)
+	}
+
+	return fmt.Errorf("query failed, %w", err)
+}
+
+// subscriptionBatch returns batch settings of subscription, they are zero, if batching is disabled
+func subscriptionBatch(sub entity.Subscription) entity.Batch {
+	if sub.Batch == nil {
+		return entity.Batch{}
+	}
+
+	return *sub.Batch
+}
+
+func scanSubscription(s scanner) (entity.Subscription, error) {
+	sub := entity.Subscription{}
+	batch := entity.Batch{}
+
+	var (
+		countryID      sql.NullInt64
+		previousSecret sql.NullString
+		expiresAt      sql.NullTime
+	)
+
+	err := s.Scan(
+		&sub.ID,
+		&sub.URL,
+		pq.Array(&sub.Actions),
+		&countryID,
+		&sub.Enabled,
+		&sub.Secret,
+		&previousSecret,
+		&expiresAt,
+		&batch.Size,
+		&batch.Window,
+		&batch.Format,
+		&sub.CreatedAt,
+		&sub.UpdatedAt)
+
+	sub.CountryID = int(countryID.Int64)
+	sub.PreviousSecret = previousSecret.String
+
+	if expiresAt.Valid {
+		sub.PreviousSecretExpiresAt = &expiresAt.Time
+	}
+
+	if batch.Enabled() {
+		sub.Batch = &batch
+	}
+
+	return sub, err
+}
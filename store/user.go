@@ -7,6 +7,9 @@ import (
 	"fmt"
 
 	"github.com/faceit/test/entity"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // user table parameters and query
@@ -14,24 +17,64 @@ const (
 	userTable = `users`
 
 	createUserQuery = `INSERT INTO ` +
-		userTable + ` (first_name, last_name, nick_name, email, country) VALUES ($1, $2, $3, $4, $5) RETURNING user_id;`
+		userTable + ` (first_name, last_name, nick_name, email, country) VALUES ($1, $2, $3, $4, $5)` +
+		` RETURNING user_id, created_at, updated_at;`
 
+	// user is updated only if it was not changed since it's version was read
+	// updated_at is a time of statement, not of transaction start, so it is as close to commit as possible for incremental pulls
 	updateUserQuery = `UPDATE ` +
-		userTable + ` SET first_name = $1 AND last_name = $2 AND nick_name = $3 AND email = $4 WHERE user_id = $5;`
-
-	deleteUserQuery = `DELETE FROM ` + userTable + ` WHERE user_id = $1;`
-
-	selectOneUserQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name FROM ` +
-		userTable + ` as u, ` + countryTable + ` as c WHERE u.user_id = $1 AND c.country_id = u.country;`
-
-	selectAllUsersQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name FROM ` +
-		userTable + ` as u, ` + countryTable + ` as c WHERE c.country_id = u.country;`
-
-	selectAllUsersByCountryQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name FROM ` +
-		userTable + ` as u, ` + countryTable + ` as c WHERE c.country_name = $1 AND c.country_id = u.country;`
-
-	selectAllUsersByFilterQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name FROM ` +
-		userTable + ` as u, ` + countryTable + ` as c WHERE u.%s = $1 AND c.country_id = u.country;`
+		userTable + ` SET first_name = $1, last_name = $2, nick_name = $3, email = $4, country = $5, version = version + 1,` +
+		` updated_at = (clock_timestamp() at time zone 'utc') WHERE user_id = $6 AND version = $7 AND deleted_at IS NULL RETURNING updated_at;`
+
+	// version of user is read, if update did not match, to tell a changed user from a missing one
+	selectUserVersionQuery = `SELECT version FROM ` + userTable + ` WHERE user_id = $1 AND deleted_at IS NULL;`
+
+	// deleted user is kept with it's password, so it could be restored until it is purged
+	deleteUserQuery = `UPDATE ` + userTable + ` SET deleted_at = (clock_timestamp() at time zone 'utc'), updated_at = (clock_timestamp() at time zone 'utc'),` +
+		` version = version + 1` +
+		` WHERE user_id = $1 AND deleted_at IS NULL;`
+
+	restoreUserQuery = `UPDATE ` + userTable + ` SET deleted_at = NULL, updated_at = (clock_timestamp() at time zone 'utc'), version = version + 1` +
+		` WHERE user_id = $1 AND deleted_at IS NOT NULL;`
+
+	// users are locked, so purgers of other nodes skip them
+	selectPurgedUsersQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name, u.country FROM ` +
+		userTable + ` as u, ` + countryTable + ` as c WHERE c.country_id = u.country` +
+		` AND u.deleted_at <= (now() at time zone 'utc') - $1 * interval '1 second'` +
+		` ORDER BY u.deleted_at LIMIT $2 FOR UPDATE OF u SKIP LOCKED;`
+
+	purgeUserQuery = `DELETE FROM ` + userTable + ` WHERE user_id = $1;`
+
+	// savepoint of one user in batch
+	savepointQuery         = `SAVEPOINT create_user;`
+	releaseSavepointQuery  = `RELEASE SAVEPOINT create_user;`
+	rollbackSavepointQuery = `ROLLBACK TO SAVEPOINT create_user;`
+
+	// PostgreSQL error codes
+	foreignKeyViolationCode = "23503"
+	uniqueViolationCode     = "23505"
+
+	selectOneUserQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name, u.country, u.version,` +
+		` u.created_at, u.updated_at FROM ` +
+		userTable + ` as u, ` + countryTable + ` as c WHERE u.user_id = $1 AND c.country_id = u.country AND u.deleted_at IS NULL;`
+
+	// users are paginated by sort keys and user_id, so pages are stable while users are added
+	// deleted users are filtered out by usersQuery, unless they are requested
+	selectUsersQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name,` +
+		` u.created_at, u.updated_at, u.deleted_at FROM ` +
+		userTable + ` as u, ` + countryTable + ` as c WHERE c.country_id = u.country`
+
+	// vector expression is the same as in users_search_idx, so index is used
+	// candidates are ordered by trigram similarity, final ranking is done by service
+	searchUsersQuery = `SELECT u.user_id, u.first_name, u.last_name, u.nick_name, u.email, c.country_name,` +
+		` u.created_at, u.updated_at FROM ` +
+		userTable + ` as u, ` + countryTable + ` as c WHERE c.country_id = u.country AND u.deleted_at IS NULL AND (` +
+		`to_tsvector('simple', coalesce(u.first_name, '') || ' ' || coalesce(u.last_name, '') || ' ' || coalesce(u.nick_name, ''))` +
+		` @@ plainto_tsquery('simple', $1)` +
+		` OR u.nick_name % $1 OR u.first_name % $1 OR u.last_name % $1 OR u.email % $1` +
+		` OR u.nick_name ILIKE $2 OR u.first_name ILIKE $2 OR u.last_name ILIKE $2 OR u.email ILIKE $2)` +
+		` ORDER BY greatest(similarity(u.nick_name, $1), similarity(u.first_name, $1),` +
+		` similarity(u.last_name, $1), similarity(u.email, $1)) DESC, u.user_id LIMIT $3;`
 )
 
 // User is a user store implementation
@@ -49,62 +92,240 @@ func NewUser(db *sql.DB, tx *sql.TxOptions) *User {
 }
 
 // Create creates a new users record in database
-func (u *User) Create(ctx context.Context, user entity.User) (int, error) {
-	var id int
-
+// created user is returned with it's id and timestamps
+func (u *User) Create(ctx context.Context, user entity.User) (entity.User, error) {
 	// starting a db transaction
 	tx, err := u.BeginTx(ctx, u.tx)
 	if err != nil {
-		return id, fmt.Errorf("failed to begin transaction, %w", err)
+		return entity.User{}, fmt.Errorf("failed to begin transaction, %w", err)
+	}
+
+	created, err := createUser(ctx, tx, user, eventID(ctx))
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
+	}
+
+	// commititng TX
+	err = tx.Commit()
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
 	}
 
-	// creating user and parsing user_id into id var for furthure password creation
-	err = tx.QueryRowContext(ctx, createUserQuery, user.FirstName, user.LastName, user.NickName, user.Email, user.CountryID).Scan(&id)
+	return created, err
+}
+
+// CreateBatch creates users in one transaction
+// every user is created under it's own savepoint, so failed user does not abort the others.
+// ids and errs hold result of every user in the same order, err is returned, if batch failed
+// as a whole, nothing is created then
+func (u *User) CreateBatch(ctx context.Context, users []entity.User) (ids []int, errs []error, err error) {
+	tx, err := u.BeginTx(ctx, u.tx)
 	if err != nil {
-		return 0, u.rollbackTransaction(tx, err)
+		return nil, nil, fmt.Errorf("failed to begin transaction, %w", err)
+	}
+
+	ids = make([]int, len(users))
+	errs = make([]error, len(users))
+
+	for i, user := range users {
+		_, err = tx.ExecContext(ctx, savepointQuery)
+		if err != nil {
+			return nil, nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+		}
+
+		// every user gets it's own notification, so they can't share request's event id
+		var created entity.User
+
+		created, errs[i] = createUser(ctx, tx, user, uuid.New().String())
+		ids[i] = created.ID
+
+		query := releaseSavepointQuery
+		if errs[i] != nil {
+			query = rollbackSavepointQuery
+		}
+
+		_, err = tx.ExecContext(ctx, query)
+		if err != nil {
+			return nil, nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, nil, u.rollbackTransaction(tx, err)
+	}
+
+	return ids, errs, nil
+}
+
+// createUser creates user with password and create notification with eventID in tx
+// created user is returned with it's id and timestamps
+func createUser(ctx context.Context, tx *sql.Tx, user entity.User, eventID string) (entity.User, error) {
+	// creating user and parsing user_id into user for furthure password creation
+	err := tx.QueryRowContext(ctx, createUserQuery, user.FirstName, user.LastName, user.NickName, user.Email, user.CountryID).
+		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return entity.User{}, userError(err, user)
 	}
 
 	// creating password for user with id from last query
-	_, err = tx.ExecContext(ctx, createPasswordQuery, id, user.Password, user.Salt)
+	_, err = tx.ExecContext(ctx, createPasswordQuery, user.ID, user.Password, user.Salt)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("query failed, %w", err)
+	}
+
+	// writing notification into outbox, so it would be sent even if service stops right after commit
+	created := user.ToResponse()
+
+	event := entity.NewUserEvent(eventID, entity.ActionCreate, created)
+
+	err = createOutbox(ctx, tx, entity.ActionCreate, user.CountryID, event)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	err = createAudit(ctx, tx, user.ID, entity.ActionCreate, nil, &created)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// userUniqueFields are user fields of unique indexes
+var userUniqueFields = map[string]string{
+	"users_email_unique_idx":     entity.FieldEmail,
+	"users_nick_name_unique_idx": entity.FieldNickName,
+}
+
+// userError translates error of user's insert or update
+// *entity.UserExistError is returned, if user's email or nick name is taken,
+// entity.ErrValidationFailed is returned, if user's country does not exist
+func userError(err error, user entity.User) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return fmt.Errorf("query failed, %w", err)
+	}
+
+	switch pqErr.Code {
+	case foreignKeyViolationCode:
+		return fmt.Errorf("%w, country %d not found", entity.ErrValidationFailed, user.CountryID)
+	case uniqueViolationCode:
+		if field, ok := userUniqueFields[pqErr.Constraint]; ok {
+			return &entity.UserExistError{Field: field}
+		}
+	}
+
+	return fmt.Errorf("query failed, %w", err)
+}
+
+// Update Updates a users record in database by it's id, if it's version is user.Version
+// *entity.VersionConflictError is returned, if user was changed since then, ErrNotFound, if it was deleted.
+// change is written into outbox as update notification, it is returned with user's new update time
+func (u *User) Update(ctx context.Context, user entity.User, change entity.UserChange) (entity.UserChange, error) {
+	return u.update(ctx, updateUserQuery,
+		[]interface{}{user.FirstName, user.LastName, user.NickName, user.Email, user.CountryID, user.ID, user.Version}, user, change)
+}
+
+// Patch updates only columns of user, that are set by patch, if user's version is patch.Version
+// user is a patched user, it's country is used by notification. Errors are the same as in Update
+func (u *User) Patch(ctx context.Context, user entity.User, patch entity.UserPatch, change entity.UserChange) (entity.UserChange, error) {
+	query, args := patchUserQuery(patch)
+
+	return u.update(ctx, query, args, user, change)
+}
+
+// update runs update user query with args and writes change into outbox and audit in the same transaction
+func (u *User) update(ctx context.Context, query string, args []interface{}, user entity.User,
+	change entity.UserChange) (entity.UserChange, error) {
+	// starting a db transaction
+	tx, err := u.BeginTx(ctx, u.tx)
 	if err != nil {
-		return 0, u.rollbackTransaction(tx, err)
+		return entity.UserChange{}, fmt.Errorf("begin transaction failed, %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&change.Current.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.UserChange{}, u.rollbackTransaction(tx, u.updateMissed(ctx, tx, user))
+	}
+	if err != nil {
+		return entity.UserChange{}, u.rollbackTransaction(tx, userError(err, user))
+	}
+
+	// writing notification into outbox
+	event := entity.NewUserChangeEvent(eventID(ctx), change)
+
+	err = createOutbox(ctx, tx, entity.ActionUpdate, user.CountryID, event)
+	if err != nil {
+		return entity.UserChange{}, u.rollbackTransaction(tx, err)
+	}
+
+	err = createAudit(ctx, tx, user.ID, entity.ActionUpdate, &change.Previous, &change.Current)
+	if err != nil {
+		return entity.UserChange{}, u.rollbackTransaction(tx, err)
 	}
 
 	// commititng TX
 	err = tx.Commit()
 	if err != nil {
-		return 0, u.rollbackTransaction(tx, err)
+		return entity.UserChange{}, u.rollbackTransaction(tx, err)
 	}
 
-	return id, err
+	return change, nil
 }
 
-// Update Updates a users record in database by it's id
-func (u *User) Update(ctx context.Context, user entity.User) error {
-	_, err := u.ExecContext(ctx, updateUserQuery, user.FirstName, user.LastName, user.NickName, user.Email, user.CountryID)
+// updateMissed returns the reason, why update did not match user: ErrNotFound, if user was deleted,
+// otherwise *entity.VersionConflictError, as user was changed since user.Version
+func (u *User) updateMissed(ctx context.Context, tx *sql.Tx, user entity.User) error {
+	var version int
+
+	err := tx.QueryRowContext(ctx, selectUserVersionQuery, user.ID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("query failed, %w", err)
 	}
 
-	return nil
+	return &entity.VersionConflictError{ID: user.ID, Version: user.Version}
 }
 
-// Delete deletes a users record from database by it's id
-func (u *User) Delete(ctx context.Context, id int) error {
+// Delete marks a users record in database as deleted by it's id, user is kept until it is purged
+// snapshot of user is written into outbox as delete notification
+func (u *User) Delete(ctx context.Context, user entity.User) error {
 	// starting a db transaction
 	tx, err := u.BeginTx(ctx, u.tx)
 	if err != nil {
 		return fmt.Errorf("begin transaction failed, %w", err)
 	}
 
-	// deleting user by his id
-	_, err = tx.ExecContext(ctx, deletePassqordQuery, id)
+	// marking user as deleted, password is kept for restore
+	res, err := tx.ExecContext(ctx, deleteUserQuery, user.ID)
 	if err != nil {
 		return u.rollbackTransaction(tx, err)
 	}
 
-	// deleting user's password
-	_, err = tx.ExecContext(ctx, deleteUserQuery, id)
+	n, err := res.RowsAffected()
+	if err != nil {
+		return u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+	}
+
+	// user was deleted by other request since it was read
+	if n == 0 {
+		return u.rollbackTransaction(tx, entity.ErrNotFound)
+	}
+
+	deleted := user.ToResponse()
+
+	// writing notification into outbox
+	event := entity.NewUserEvent(eventID(ctx), entity.ActionDelete, deleted)
+
+	err = createOutbox(ctx, tx, entity.ActionDelete, user.CountryID, event)
+	if err != nil {
+		return u.rollbackTransaction(tx, err)
+	}
+
+	err = createAudit(ctx, tx, user.ID, entity.ActionDelete, &deleted, nil)
 	if err != nil {
 		return u.rollbackTransaction(tx, err)
 	}
@@ -118,17 +339,152 @@ func (u *User) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// One returns one users record from database by id
+// Restore restores deleted user by it's id, ErrNotFound is returned, if there is no deleted user with that id
+// restored user is written into outbox as restore notification
+func (u *User) Restore(ctx context.Context, id int) (entity.User, error) {
+	tx, err := u.BeginTx(ctx, u.tx)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("begin transaction failed, %w", err)
+	}
+
+	// email or nick name of deleted user could be taken by an other user since it was deleted
+	res, err := tx.ExecContext(ctx, restoreUserQuery, id)
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, userError(err, entity.User{ID: id}))
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+	}
+
+	if n == 0 {
+		return entity.User{}, u.rollbackTransaction(tx, entity.ErrNotFound)
+	}
+
+	user, err := scanUser(tx.QueryRowContext(ctx, selectOneUserQuery, id))
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
+	}
+
+	restored := user.ToResponse()
+
+	event := entity.NewUserRestoreEvent(eventID(ctx), restored)
+
+	err = createOutbox(ctx, tx, entity.ActionCreate, user.CountryID, event)
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
+	}
+
+	err = createAudit(ctx, tx, user.ID, entity.AuditActionRestore, nil, &restored)
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entity.User{}, u.rollbackTransaction(tx, err)
+	}
+
+	return user, nil
+}
+
+// Purge removes up to limit users, that were deleted at least retention seconds ago, with their passwords
+// last snapshot of every purged user is written into outbox as purge notification, purged users are returned
+func (u *User) Purge(ctx context.Context, retention, limit int) ([]entity.User, error) {
+	tx, err := u.BeginTx(ctx, u.tx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction failed, %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, selectPurgedUsersQuery, retention, limit)
+	if err != nil {
+		return nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+	}
+
+	users := []entity.User{}
+
+	for rows.Next() {
+		user := entity.User{}
+
+		err = rows.Scan(
+			&user.ID,
+			&user.FirstName,
+			&user.LastName,
+			&user.NickName,
+			&user.Email,
+			&user.Country,
+			&user.CountryID)
+		if err != nil {
+			_ = rows.Close()
+			return nil, u.rollbackTransaction(tx, fmt.Errorf("scan results failed, %w", err))
+		}
+
+		users = append(users, user)
+	}
+
+	// rows must be closed, before other queries are made in transaction
+	_ = rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+	}
+
+	for _, user := range users {
+		_, err = tx.ExecContext(ctx, deletePassqordQuery, user.ID)
+		if err != nil {
+			return nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+		}
+
+		_, err = tx.ExecContext(ctx, purgeUserQuery, user.ID)
+		if err != nil {
+			return nil, u.rollbackTransaction(tx, fmt.Errorf("query failed, %w", err))
+		}
+
+		purged := user.ToResponse()
+
+		// purge is not a part of any request, so every user gets it's own event id
+		event := entity.NewUserPurgeEvent(uuid.New().String(), purged)
+
+		err = createOutbox(ctx, tx, entity.ActionDelete, user.CountryID, event)
+		if err != nil {
+			return nil, u.rollbackTransaction(tx, err)
+		}
+
+		err = createAudit(ctx, tx, user.ID, entity.AuditActionPurge, &purged, nil)
+		if err != nil {
+			return nil, u.rollbackTransaction(tx, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, u.rollbackTransaction(tx, err)
+	}
+
+	return users, nil
+}
+
+// One returns one users record from database by id, deleted user is not returned
 func (u *User) One(ctx context.Context, id int) (entity.User, error) {
+	return scanUser(u.QueryRowContext(ctx, selectOneUserQuery, id))
+}
+
+// scanUser scans user, selected by selectOneUserQuery, ErrNotFound is returned, if there is no user
+func scanUser(row *sql.Row) (entity.User, error) {
 	user := entity.User{}
 
-	err := u.QueryRowContext(ctx, selectOneUserQuery, id).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.FirstName,
 		&user.LastName,
 		&user.NickName,
 		&user.Email,
-		&user.Country)
+		&user.Country,
+		&user.CountryID,
+		&user.Version,
+		&user.CreatedAt,
+		&user.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return user, entity.ErrNotFound
 	}
@@ -139,9 +495,14 @@ func (u *User) One(ctx context.Context, id int) (entity.User, error) {
 	return user, err
 }
 
-// All returns all users records from database
-func (u *User) All(ctx context.Context) ([]entity.User, error) {
-	userRows, err := u.QueryContext(ctx, selectAllUsersQuery)
+// All returns a page of users records from database, that match filter
+func (u *User) All(ctx context.Context, filter entity.UserFilter, page entity.Page) ([]entity.User, error) {
+	query, args, err := usersQuery(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	userRows, err := u.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query failed, %w", err)
 	}
@@ -155,13 +516,18 @@ func (u *User) All(ctx context.Context) ([]entity.User, error) {
 	for userRows.Next() {
 		user := entity.User{}
 
+		var deletedAt sql.NullTime
+
 		err = userRows.Scan(
 			&user.ID,
 			&user.FirstName,
 			&user.LastName,
 			&user.NickName,
 			&user.Email,
-			&user.Country)
+			&user.Country,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&deletedAt)
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, entity.ErrNotFound
 		}
@@ -169,51 +535,74 @@ func (u *User) All(ctx context.Context) ([]entity.User, error) {
 			return nil, fmt.Errorf("scan results failed, %w", err)
 		}
 
+		if deletedAt.Valid {
+			user.DeletedAt = &deletedAt.Time
+		}
+
 		users = append(users, user)
 	}
 
 	return users, nil
 }
 
-// AllByCountry gets all users for selected country
-func (u *User) AllByCountry(ctx context.Context, iso2 string) ([]entity.User, error) {
-	userRows, err := u.QueryContext(ctx, selectAllUsersByCountryQuery, iso2)
+// Each passes every user, that matches filter, to fn in filter's sort order
+// rows are read from database one by one, so memory does not depend on number of users.
+// iteration stops on the first error of fn, it is returned as is
+func (u *User) Each(ctx context.Context, filter entity.UserFilter, fn func(entity.User) error) error {
+	query, args, err := usersQuery(filter, entity.Page{})
 	if err != nil {
-		return nil, fmt.Errorf("query failed, %w", err)
+		return err
+	}
+
+	userRows, err := u.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
 	}
 
 	defer func() {
 		_ = userRows.Close()
 	}()
 
-	users := []entity.User{}
-
 	for userRows.Next() {
 		user := entity.User{}
 
+		var deletedAt sql.NullTime
+
 		err = userRows.Scan(
 			&user.ID,
 			&user.FirstName,
 			&user.LastName,
 			&user.NickName,
 			&user.Email,
-			&user.Country)
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, entity.ErrNotFound
+			&user.Country,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&deletedAt)
+		if err != nil {
+			return fmt.Errorf("scan results failed, %w", err)
 		}
+
+		if deletedAt.Valid {
+			user.DeletedAt = &deletedAt.Time
+		}
+
+		err = fn(user)
 		if err != nil {
-			return nil, fmt.Errorf("query failed, %w", err)
+			return err
 		}
+	}
 
-		users = append(users, user)
+	err = userRows.Err()
+	if err != nil {
+		return fmt.Errorf("query failed, %w", err)
 	}
 
-	return users, nil
+	return nil
 }
 
-// AllWithFilter gets all users by selected filter
-func (u *User) AllWithFilter(ctx context.Context, title, filter string) ([]entity.User, error) {
-	userRows, err := u.QueryContext(ctx, fmt.Sprintf(selectAllUsersByFilterQuery, title), filter)
+// Search returns up to limit users, that match q by words, trigram similarity or substring
+func (u *User) Search(ctx context.Context, q string, limit int) ([]entity.User, error) {
+	userRows, err := u.QueryContext(ctx, searchUsersQuery, q, "%"+likeEscaper.Replace(q)+"%", limit)
 	if err != nil {
 		return nil, fmt.Errorf("query failed, %w", err)
 	}
@@ -233,12 +622,11 @@ func (u *User) AllWithFilter(ctx context.Context, title, filter string) ([]entit
 			&user.LastName,
 			&user.NickName,
 			&user.Email,
-			&user.Country)
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, entity.ErrNotFound
-		}
+			&user.Country,
+			&user.CreatedAt,
+			&user.UpdatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("query failed, %w", err)
+			return nil, fmt.Errorf("scan results failed, %w", err)
 		}
 
 		users = append(users, user)
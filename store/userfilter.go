@@ -0,0 +1,148 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/faceit/test/entity"
+)
+
+var (
+	// userFilterColumns is a whitelist of columns users could be filtered by
+	userFilterColumns = map[string]string{
+		entity.FieldFirstName: `u.first_name`,
+		entity.FieldLastName:  `u.last_name`,
+		entity.FieldNickName:  `u.nick_name`,
+		entity.FieldEmail:     `u.email`,
+		entity.FieldCountry:   `c.iso2`,
+	}
+
+	// userSortColumns is a whitelist of columns users could be sorted by
+	// country is sorted by name, as it is returned in response
+	userSortColumns = map[string]string{
+		entity.FieldFirstName: `u.first_name`,
+		entity.FieldLastName:  `u.last_name`,
+		entity.FieldNickName:  `u.nick_name`,
+		entity.FieldEmail:     `u.email`,
+		entity.FieldCountry:   `c.country_name`,
+		entity.FieldCreatedAt: `u.created_at`,
+		entity.FieldUpdatedAt: `u.updated_at`,
+	}
+
+	// likeEscaper escapes LIKE wildcards, so filter value is matched literally
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// queryBuilder builds query with positional arguments
+type queryBuilder struct {
+	query strings.Builder
+	args  []interface{}
+}
+
+// arg adds argument and returns it's placeholder
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// usersQuery builds select users query, that matches filter and page
+// only columns from whitelist are put into query, all values are passed as arguments
+func usersQuery(filter entity.UserFilter, page entity.Page) (string, []interface{}, error) {
+	b := &queryBuilder{}
+	b.query.WriteString(selectUsersQuery)
+
+	// deleted users are tombstones of incremental pulls, they are kept until they are purged
+	if !filter.IncludeDeleted {
+		b.query.WriteString(` AND u.deleted_at IS NULL`)
+	}
+
+	for _, c := range filter.Conditions {
+		column, ok := userFilterColumns[c.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("%w, users can't be filtered by %q", entity.ErrValidationFailed, c.Field)
+		}
+
+		switch c.Op {
+		case entity.FilterEq:
+			fmt.Fprintf(&b.query, ` AND %s = %s`, column, b.arg(c.Value))
+		case entity.FilterPrefix:
+			fmt.Fprintf(&b.query, ` AND %s LIKE %s`, column, b.arg(likeEscaper.Replace(c.Value)+"%"))
+		case entity.FilterIContains:
+			fmt.Fprintf(&b.query, ` AND %s ILIKE %s`, column, b.arg("%"+likeEscaper.Replace(c.Value)+"%"))
+		default:
+			return "", nil, fmt.Errorf("%w, operator %q is not supported", entity.ErrValidationFailed, c.Op)
+		}
+	}
+
+	// stored time is in UTC without time zone, updated_at is set by statement before commit,
+	// so change, that commits later, could have an earlier time, than the last one seen by consumer,
+	// consumer covers it by requesting updated_since with overlap window
+	if !filter.UpdatedSince.IsZero() {
+		fmt.Fprintf(&b.query, ` AND u.updated_at >= %s`, b.arg(filter.UpdatedSince.UTC()))
+	}
+
+	columns := make([]string, 0, len(filter.Sort))
+	order := make([]string, 0, len(filter.Sort)+1)
+
+	for _, s := range filter.Sort {
+		column, ok := userSortColumns[s.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("%w, users can't be sorted by %q", entity.ErrValidationFailed, s.Field)
+		}
+
+		columns = append(columns, column)
+
+		if s.Desc {
+			order = append(order, column+` DESC`)
+		} else {
+			order = append(order, column)
+		}
+	}
+
+	order = append(order, `u.user_id`)
+
+	// the first page has no cursor, cursor holds values of sort keys, so it can't be used with different sort
+	if page.After > 0 {
+		if len(page.Keys) != len(filter.Sort) {
+			return "", nil, fmt.Errorf("%w, cursor does not match sort", entity.ErrValidationFailed)
+		}
+
+		b.query.WriteString(` AND (` + keyset(b, filter.Sort, columns, page) + `)`)
+	}
+
+	fmt.Fprintf(&b.query, ` ORDER BY %s`, strings.Join(order, `, `))
+
+	// all users are selected, if page has no limit
+	if page.Limit > 0 {
+		fmt.Fprintf(&b.query, ` LIMIT %s`, b.arg(page.Limit))
+	}
+
+	b.query.WriteString(`;`)
+
+	return b.query.String(), b.args, nil
+}
+
+// keyset returns condition, that matches rows after the cursor of page
+// sort keys could have different directions, so row comparison can't be used,
+// row follows cursor, if it's first key, that differs from cursor, is after cursor's one in it's direction
+func keyset(b *queryBuilder, sort []entity.Sort, columns []string, page entity.Page) string {
+	alternatives := make([]string, 0, len(sort)+1)
+	equal := make([]string, 0, len(sort))
+
+	for i, s := range sort {
+		op := `>`
+		if s.Desc {
+			op = `<`
+		}
+
+		value := b.arg(page.Keys[i])
+
+		alternatives = append(alternatives, strings.Join(append(equal, fmt.Sprintf(`%s %s %s`, columns[i], op, value)), ` AND `))
+		equal = append(equal, fmt.Sprintf(`%s = %s`, columns[i], value))
+	}
+
+	alternatives = append(alternatives, strings.Join(append(equal, `u.user_id > `+b.arg(page.After)), ` AND `))
+
+	return `(` + strings.Join(alternatives, `) OR (`) + `)`
+}
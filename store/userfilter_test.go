@@ -0,0 +1,130 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faceit/test/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseUsersQuery struct {
+	filter entity.UserFilter
+	page   entity.Page
+	query  string
+	args   []interface{}
+	err    error
+}
+
+func TestUsersQuery(t *testing.T) {
+	since := time.Date(2021, 7, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	for name, tc := range map[string]testCaseUsersQuery{
+		"positive_no_filter": {
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL ORDER BY u.user_id;`,
+			args:  nil,
+		},
+		"positive_include_deleted": {
+			filter: entity.UserFilter{IncludeDeleted: true},
+			query:  selectUsersQuery + ` ORDER BY u.user_id;`,
+			args:   nil,
+		},
+		"positive_conditions": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{
+					{Field: entity.FieldCountry, Op: entity.FilterEq, Value: "UK"},
+					{Field: entity.FieldNickName, Op: entity.FilterPrefix, Value: "star"},
+					{Field: entity.FieldEmail, Op: entity.FilterIContains, Value: "dust"},
+				},
+				UpdatedSince: since,
+			},
+			page: entity.Page{Limit: 10},
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL AND c.iso2 = $1 AND u.nick_name LIKE $2` +
+				` AND u.email ILIKE $3 AND u.updated_at >= $4 ORDER BY u.user_id LIMIT $5;`,
+			args: []interface{}{"UK", "star%", "%dust%", since.UTC(), 10},
+		},
+		"positive_like_escaped": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{
+					{Field: entity.FieldNickName, Op: entity.FilterPrefix, Value: `50%_off\`},
+					{Field: entity.FieldEmail, Op: entity.FilterIContains, Value: "a_b"},
+				},
+			},
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL AND u.nick_name LIKE $1 AND u.email ILIKE $2 ORDER BY u.user_id;`,
+			args:  []interface{}{`50\%\_off\\%`, `%a\_b%`},
+		},
+		"positive_multi_key_sort": {
+			filter: entity.UserFilter{
+				Sort: []entity.Sort{
+					{Field: entity.FieldCountry},
+					{Field: entity.FieldCreatedAt, Desc: true},
+				},
+			},
+			page:  entity.Page{Limit: 20},
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL ORDER BY c.country_name, u.created_at DESC, u.user_id LIMIT $1;`,
+			args:  []interface{}{20},
+		},
+		"positive_keyset_id": {
+			page:  entity.Page{After: 5, Limit: 20},
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL AND ((u.user_id > $1)) ORDER BY u.user_id LIMIT $2;`,
+			args:  []interface{}{5, 20},
+		},
+		"positive_keyset_multi_key": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{
+					{Field: entity.FieldFirstName, Op: entity.FilterEq, Value: "David"},
+				},
+				Sort: []entity.Sort{
+					{Field: entity.FieldLastName},
+					{Field: entity.FieldUpdatedAt, Desc: true},
+				},
+			},
+			page: entity.Page{After: 7, Keys: []string{"Bowie", "2021-07-01T12:00:00Z"}, Limit: 20},
+			query: selectUsersQuery + ` AND u.deleted_at IS NULL AND u.first_name = $1` +
+				` AND ((u.last_name > $2) OR (u.last_name = $2 AND u.updated_at < $3)` +
+				` OR (u.last_name = $2 AND u.updated_at = $3 AND u.user_id > $4))` +
+				` ORDER BY u.last_name, u.updated_at DESC, u.user_id LIMIT $5;`,
+			args: []interface{}{"David", "Bowie", "2021-07-01T12:00:00Z", 7, 20},
+		},
+		"negative_filter_field": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{{Field: "password", Op: entity.FilterEq, Value: "secret"}},
+			},
+			err: entity.ErrValidationFailed,
+		},
+		"negative_filter_sort_only_field": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{{Field: entity.FieldCreatedAt, Op: entity.FilterEq, Value: "2021"}},
+			},
+			err: entity.ErrValidationFailed,
+		},
+		"negative_filter_operator": {
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{{Field: entity.FieldEmail, Op: "gt", Value: "a"}},
+			},
+			err: entity.ErrValidationFailed,
+		},
+		"negative_sort_field": {
+			filter: entity.UserFilter{
+				Sort: []entity.Sort{{Field: "u.password"}},
+			},
+			err: entity.ErrValidationFailed,
+		},
+		"negative_cursor_sort_mismatch": {
+			filter: entity.UserFilter{
+				Sort: []entity.Sort{{Field: entity.FieldLastName}},
+			},
+			page: entity.Page{After: 7, Limit: 20},
+			err:  entity.ErrValidationFailed,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			query, args, err := usersQuery(tc.filter, tc.page)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.query, query)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
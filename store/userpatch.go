@@ -0,0 +1,38 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/faceit/test/entity"
+)
+
+// patchUserQuery builds update user query, that sets only columns, which are set by patch
+// user is patched only if it was not changed since patch.Version, as in update query
+func patchUserQuery(patch entity.UserPatch) (string, []interface{}) {
+	b := &queryBuilder{}
+	set := make([]string, 0, 7)
+
+	for _, c := range []struct {
+		column string
+		value  interface{}
+		ok     bool
+	}{
+		{`first_name`, patch.FirstName, patch.FirstName != nil},
+		{`last_name`, patch.LastName, patch.LastName != nil},
+		{`nick_name`, patch.NickName, patch.NickName != nil},
+		{`email`, patch.Email, patch.Email != nil},
+		{`country`, patch.CountryID, patch.CountryID != nil},
+	} {
+		if c.ok {
+			set = append(set, fmt.Sprintf(`%s = %s`, c.column, b.arg(c.value)))
+		}
+	}
+
+	set = append(set, `version = version + 1`, `updated_at = (clock_timestamp() at time zone 'utc')`)
+
+	fmt.Fprintf(&b.query, `UPDATE `+userTable+` SET %s WHERE user_id = %s AND version = %s AND deleted_at IS NULL RETURNING updated_at;`,
+		strings.Join(set, `, `), b.arg(patch.ID), b.arg(patch.Version))
+
+	return b.query.String(), b.args
+}
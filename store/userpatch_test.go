@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCasePatchUserQuery struct {
+	patch entity.UserPatch
+	query string
+	args  []interface{}
+}
+
+func TestPatchUserQuery(t *testing.T) {
+	firstName, lastName, nickName, email, country := "David", "Bowie", "Star Man", "ziggy@stardust.com", 2
+
+	for name, tc := range map[string]testCasePatchUserQuery{
+		"positive_first_name": {
+			patch: entity.UserPatch{ID: 1, Version: 3, FirstName: &firstName},
+			query: `UPDATE ` + userTable + ` SET first_name = $1, version = version + 1,` +
+				` updated_at = (clock_timestamp() at time zone 'utc')` +
+				` WHERE user_id = $2 AND version = $3 AND deleted_at IS NULL RETURNING updated_at;`,
+			args: []interface{}{&firstName, 1, 3},
+		},
+		"positive_country": {
+			patch: entity.UserPatch{ID: 1, Version: 3, CountryID: &country},
+			query: `UPDATE ` + userTable + ` SET country = $1, version = version + 1,` +
+				` updated_at = (clock_timestamp() at time zone 'utc')` +
+				` WHERE user_id = $2 AND version = $3 AND deleted_at IS NULL RETURNING updated_at;`,
+			args: []interface{}{&country, 1, 3},
+		},
+		"positive_every_field": {
+			patch: entity.UserPatch{
+				ID:        7,
+				Version:   1,
+				FirstName: &firstName,
+				LastName:  &lastName,
+				NickName:  &nickName,
+				Email:     &email,
+				CountryID: &country,
+			},
+			query: `UPDATE ` + userTable + ` SET first_name = $1, last_name = $2, nick_name = $3, email = $4, country = $5,` +
+				` version = version + 1, updated_at = (clock_timestamp() at time zone 'utc')` +
+				` WHERE user_id = $6 AND version = $7 AND deleted_at IS NULL RETURNING updated_at;`,
+			args: []interface{}{&firstName, &lastName, &nickName, &email, &country, 7, 1},
+		},
+		"positive_password_not_set": {
+			// password of patch authorizes change, it is not changed by patch
+			patch: entity.UserPatch{ID: 7, Version: 1, Password: "secret", LastName: &lastName},
+			query: `UPDATE ` + userTable + ` SET last_name = $1, version = version + 1,` +
+				` updated_at = (clock_timestamp() at time zone 'utc')` +
+				` WHERE user_id = $2 AND version = $3 AND deleted_at IS NULL RETURNING updated_at;`,
+			args: []interface{}{&lastName, 7, 1},
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			query, args := patchUserQuery(tc.patch)
+			assert.Equal(t, tc.query, query)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
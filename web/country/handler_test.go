@@ -21,6 +21,6 @@ func TestHandler(t *testing.T) {
 		mockCountryClient := mock_country.NewMockclient(ctr)
 		countryService := country.New(mockCountryClient)
 
-		NewHandler(mux.NewRouter().StrictSlash(true), log, middleware.New(log), countryService)
+		NewHandler(mux.NewRouter().StrictSlash(true), log, middleware.New(log, nil), countryService)
 	})
 }
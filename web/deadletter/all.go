@@ -0,0 +1,45 @@
+//go:generate mockgen -source ../deadletter/all.go -destination ../deadletter/mock/mock_all.go
+
+package deadletter
+
+import (
+	"context"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+// query params
+const (
+	queryParamConsumer = "consumer"
+)
+
+type all interface {
+	All(ctx context.Context, consumer string) ([]entity.DeadLetter, error)
+}
+
+// All is a all dead letters endpoint struct
+type All struct {
+	do   all
+	resp *web.Response
+}
+
+func newAll(r *web.Response, a all) *All {
+	return &All{
+		do:   a,
+		resp: r,
+	}
+}
+
+// Do returnes all dead letters, filtered by consumer if one is provided
+func (a *All) Do(r *web.Request) {
+	ctx := r.Context()
+
+	dls, err := a.do.All(ctx, r.GetQueryParamsString(queryParamConsumer))
+	if err != nil {
+		a.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	a.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, dls)
+}
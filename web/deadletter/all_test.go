@@ -0,0 +1,106 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_deadletter "github.com/faceit/test/web/deadletter/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	allURL = "http://localhost:8080/v1/admin/notifications/dead"
+)
+
+var (
+	errTest = fmt.Errorf("errTest")
+
+	testID         = 1
+	testConsumer   = "http://consumer.test"
+	testDeadLetter = entity.DeadLetter{
+		ID:        testID,
+		Consumer:  testConsumer,
+		LastError: "timeout",
+		Attempts:  3,
+		Payload:   json.RawMessage(`{"action":"CREATE"}`),
+	}
+)
+
+type testCaseAll struct {
+	url                string
+	expectedResponse   []entity.DeadLetter
+	expectedStatusCode int
+}
+
+func (tc testCaseAll) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
+	assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+	if tc.expectedResponse != nil {
+		var resp []entity.DeadLetter
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expectedResponse, resp)
+	}
+}
+
+func TestAll(t *testing.T) {
+	t.Run("positive_200_by_consumer", func(t *testing.T) {
+		tc := testCaseAll{
+			url:                allURL + "?consumer=" + testConsumer,
+			expectedResponse:   []entity.DeadLetter{testDeadLetter},
+			expectedStatusCode: http.StatusOK,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_deadletter.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, testConsumer).Return([]entity.DeadLetter{testDeadLetter}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("negative_500", func(t *testing.T) {
+		tc := testCaseAll{
+			url:                allURL,
+			expectedStatusCode: http.StatusInternalServerError,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_deadletter.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, "").Return(nil, errTest)
+
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+}
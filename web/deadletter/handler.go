@@ -0,0 +1,71 @@
+package deadletter
+
+import (
+	"net/http"
+
+	"github.com/faceit/test/logger"
+	"github.com/faceit/test/services/deadletter"
+	"github.com/faceit/test/web"
+	"github.com/faceit/test/web/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is a web events handler struct
+type Handler struct {
+	router     *mux.Router
+	log        logger.Logger
+	middleware middleware.Middleware
+	deadLetter *deadletter.DeadLetter
+}
+
+// NewHandler creates new dead letter handler instancce
+func NewHandler(router *mux.Router, l logger.Logger, m middleware.Middleware, d *deadletter.DeadLetter) {
+	h := Handler{
+		router:     router,
+		log:        l,
+		middleware: m,
+		deadLetter: d,
+	}
+
+	admin := router.PathPrefix("/v1/admin").Subrouter()
+
+	admin.HandleFunc("/notifications/dead", h.middleware.SetContextHeader(http.HandlerFunc(h.All))).
+		Methods(http.MethodGet)
+	admin.HandleFunc("/notifications/dead", h.middleware.SetContextHeader(http.HandlerFunc(h.PurgeAll))).
+		Methods(http.MethodDelete)
+
+	admin.HandleFunc("/notifications/dead/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.One))).
+		Methods(http.MethodGet)
+	admin.HandleFunc("/notifications/dead/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Purge))).
+		Methods(http.MethodDelete)
+	admin.HandleFunc("/notifications/dead/{id}/replay", h.middleware.SetContextHeader(http.HandlerFunc(h.Replay))).
+		Methods(http.MethodPost)
+}
+
+// All handles Get All dead letters requests
+// dead letters can be filtered by consumer query parameter
+func (h *Handler) All(w http.ResponseWriter, r *http.Request) {
+	newAll(web.NewResponse(w, h.log), h.deadLetter).Do(web.NewRequest(r))
+}
+
+// One handles Get One dead letter by id requests
+func (h *Handler) One(w http.ResponseWriter, r *http.Request) {
+	newOne(web.NewResponse(w, h.log), h.deadLetter).Do(web.NewRequest(r))
+}
+
+// Replay handles POST replay dead letter requests
+// dead letter is removed, once it is delivered to it's consumer
+func (h *Handler) Replay(w http.ResponseWriter, r *http.Request) {
+	newReplay(web.NewResponse(w, h.log), h.deadLetter).Do(web.NewRequest(r))
+}
+
+// Purge handles DELETE one dead letter by id requests
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	newPurge(web.NewResponse(w, h.log), h.deadLetter).Do(web.NewRequest(r))
+}
+
+// PurgeAll handles DELETE all dead letters requests
+func (h *Handler) PurgeAll(w http.ResponseWriter, r *http.Request) {
+	newPurgeAll(web.NewResponse(w, h.log), h.deadLetter).Do(web.NewRequest(r))
+}
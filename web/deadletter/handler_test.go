@@ -0,0 +1,24 @@
+package deadletter
+
+import (
+	"testing"
+
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/services/deadletter"
+	mock_deadletter "github.com/faceit/test/services/deadletter/mock"
+	"github.com/faceit/test/web/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestNewHandler(t *testing.T) {
+	ctr := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMocklog(ctr)
+	log := logger.New(mockLogger)
+
+	service := deadletter.New(mock_deadletter.NewMockclient(ctr), mock_deadletter.NewMocknotifier(ctr))
+
+	NewHandler(mux.NewRouter().StrictSlash(true), log, middleware.New(log, nil), service)
+}
@@ -0,0 +1,55 @@
+//go:generate mockgen -source ../deadletter/one.go -destination ../deadletter/mock/mock_one.go
+
+package deadletter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+const (
+	pathParamID = "id"
+)
+
+type one interface {
+	One(ctx context.Context, id int) (entity.DeadLetter, error)
+}
+
+// One is a one dead letter endpoint struct
+type One struct {
+	do   one
+	resp *web.Response
+}
+
+func newOne(r *web.Response, o one) *One {
+	return &One{
+		do:   o,
+		resp: r,
+	}
+}
+
+// Do is getting dead letter's id from URL and returning it with it's payload
+func (o *One) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		o.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	dl, err := o.do.One(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		o.resp.NotFound(ctx, err)
+		return
+	}
+	if err != nil {
+		o.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	o.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, dl)
+}
@@ -0,0 +1,85 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_deadletter "github.com/faceit/test/web/deadletter/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oneURL = "http://localhost:8080/v1/admin/notifications/dead/1"
+)
+
+func TestOne(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientOne := mock_deadletter.NewMockone(ctr)
+		mockClientOne.EXPECT().One(ctx, testID).Return(testDeadLetter, nil)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mockClientOne).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp entity.DeadLetter
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, testDeadLetter, resp)
+	})
+
+	t.Run("negative_400_missing_id", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mock_deadletter.NewMockone(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("negative_404", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientOne := mock_deadletter.NewMockone(ctr)
+		mockClientOne.EXPECT().One(ctx, testID).Return(entity.DeadLetter{}, entity.ErrNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mockClientOne).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,51 @@
+//go:generate mockgen -source ../deadletter/purge.go -destination ../deadletter/mock/mock_purge.go
+
+package deadletter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type purge interface {
+	Purge(ctx context.Context, id int) error
+}
+
+// Purge is a purge one dead letter endpoint struct
+type Purge struct {
+	do   purge
+	resp *web.Response
+}
+
+func newPurge(r *web.Response, p purge) *Purge {
+	return &Purge{
+		do:   p,
+		resp: r,
+	}
+}
+
+// Do is getting dead letter's id from URL and removes it
+func (p *Purge) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		p.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	err := p.do.Purge(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		p.resp.NotFound(ctx, err)
+		return
+	}
+	if err != nil {
+		p.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	p.resp.Ok(ctx)
+}
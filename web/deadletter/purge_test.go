@@ -0,0 +1,111 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_deadletter "github.com/faceit/test/web/deadletter/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	purgeURL = "http://localhost:8080/v1/admin/notifications/dead/1"
+)
+
+func TestPurge(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientPurge := mock_deadletter.NewMockpurge(ctr)
+		mockClientPurge.EXPECT().Purge(ctx, testID).Return(nil)
+
+		req := httptest.NewRequest(http.MethodDelete, purgeURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newPurge(web.NewResponse(w, logger), mockClientPurge).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("negative_404", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientPurge := mock_deadletter.NewMockpurge(ctr)
+		mockClientPurge.EXPECT().Purge(ctx, testID).Return(entity.ErrNotFound)
+
+		req := httptest.NewRequest(http.MethodDelete, purgeURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newPurge(web.NewResponse(w, logger), mockClientPurge).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPurgeAll(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientPurgeAll := mock_deadletter.NewMockpurgeAll(ctr)
+		mockClientPurgeAll.EXPECT().PurgeAll(ctx).Return(2, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, allURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newPurgeAll(web.NewResponse(w, logger), mockClientPurgeAll).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Purged int `json:"purged"`
+		}
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, resp.Purged)
+	})
+
+	t.Run("negative_500", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientPurgeAll := mock_deadletter.NewMockpurgeAll(ctr)
+		mockClientPurgeAll.EXPECT().PurgeAll(ctx).Return(0, errTest)
+
+		req := httptest.NewRequest(http.MethodDelete, allURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newPurgeAll(web.NewResponse(w, logger), mockClientPurgeAll).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,45 @@
+//go:generate mockgen -source ../deadletter/purgeall.go -destination ../deadletter/mock/mock_purgeall.go
+
+package deadletter
+
+import (
+	"context"
+
+	"github.com/faceit/test/web"
+)
+
+type purgeAll interface {
+	PurgeAll(ctx context.Context) (int, error)
+}
+
+// PurgeAll is a purge all dead letters endpoint struct
+type PurgeAll struct {
+	do   purgeAll
+	resp *web.Response
+}
+
+func newPurgeAll(r *web.Response, p purgeAll) *PurgeAll {
+	return &PurgeAll{
+		do:   p,
+		resp: r,
+	}
+}
+
+// Do removes all dead letters and returnes their number
+func (p *PurgeAll) Do(r *web.Request) {
+	ctx := r.Context()
+
+	n, err := p.do.PurgeAll(ctx)
+	if err != nil {
+		p.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	var respBody struct {
+		Purged int `json:"purged"`
+	}
+
+	respBody.Purged = n
+
+	p.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, respBody)
+}
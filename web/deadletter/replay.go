@@ -0,0 +1,55 @@
+//go:generate mockgen -source ../deadletter/replay.go -destination ../deadletter/mock/mock_replay.go
+
+package deadletter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type replay interface {
+	Replay(ctx context.Context, id int) error
+}
+
+// Replay is a replay dead letter endpoint struct
+type Replay struct {
+	do   replay
+	resp *web.Response
+}
+
+func newReplay(r *web.Response, rp replay) *Replay {
+	return &Replay{
+		do:   rp,
+		resp: r,
+	}
+}
+
+// Do is getting dead letter's id from URL and sends it to it's consumer once again
+func (rp *Replay) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		rp.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	err := rp.do.Replay(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		rp.resp.NotFound(ctx, err)
+		return
+	}
+	if errors.Is(err, entity.ErrDeliveryFailed) {
+		rp.resp.BadGateway(ctx, err)
+		return
+	}
+	if err != nil {
+		rp.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	rp.resp.Ok(ctx)
+}
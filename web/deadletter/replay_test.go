@@ -0,0 +1,58 @@
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_deadletter "github.com/faceit/test/web/deadletter/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	replayURL = "http://localhost:8080/v1/admin/notifications/dead/1/replay"
+)
+
+type testCaseReplay struct {
+	replayErr          error
+	expectedStatusCode int
+}
+
+func TestReplay(t *testing.T) {
+	for name, tc := range map[string]testCaseReplay{
+		"positive_200":               {expectedStatusCode: http.StatusOK},
+		"negative_404":               {replayErr: entity.ErrNotFound, expectedStatusCode: http.StatusNotFound},
+		"negative_502_not_delivered": {replayErr: fmt.Errorf("%w, timeout", entity.ErrDeliveryFailed), expectedStatusCode: http.StatusBadGateway},
+		"negative_500":               {replayErr: errTest, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientReplay := mock_deadletter.NewMockreplay(ctr)
+			mockClientReplay.EXPECT().Replay(ctx, testID).Return(tc.replayErr)
+
+			req := httptest.NewRequest(http.MethodPost, replayURL, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newReplay(web.NewResponse(w, logger), mockClientReplay).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -2,32 +2,79 @@ package middleware
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/logger"
 )
 
+// request headers, that identify who made the request, they are set by API gateway, that is a trusted proxy
+const (
+	actorHeader        = "X-Actor"
+	forwardedForHeader = "X-Forwarded-For"
+	anonymousActor     = "anonymous"
+)
+
+// requestTimeout is a default timeout of request
+const requestTimeout = time.Minute
+
 // Middleware is a middleware interface
 type Middleware interface {
 	SetContextHeader(next http.HandlerFunc) http.HandlerFunc
+	SetContextHeaderTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc
 }
 
-// New creates new Middleware
-func New(log logger.Logger) Middleware {
-	return &middleware{log: log}
+// New creates new Middleware, actor and client IP headers are accepted only from trusted proxies,
+// that are IPs or CIDRs, invalid ones are skipped
+func New(log logger.Logger, trustedProxies []string) Middleware {
+	m := &middleware{log: log}
+
+	for _, p := range trustedProxies {
+		// single address is a network of one host
+		if ip := net.ParseIP(p); ip != nil {
+			bits := 8 * len(ip)
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+
+			m.trusted = append(m.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(p)
+		if err != nil {
+			log.Warningf(context.Background(), "invalid trusted proxy %q is skipped", p)
+			continue
+		}
+
+		m.trusted = append(m.trusted, network)
+	}
+
+	return m
 }
 
 type middleware struct {
-	log logger.Logger
+	log     logger.Logger
+	trusted []*net.IPNet
 }
 
 // AcceptPAcceptGetost is a middlware, that is setting a requestID into r.Context()
 // if one is missing, and sets a timeout request to 1 minute
 func (m *middleware) SetContextHeader(next http.HandlerFunc) http.HandlerFunc {
+	return m.SetContextHeaderTimeout(requestTimeout, next)
+}
+
+// SetContextHeaderTimeout is SetContextHeader with it's own request timeout, it is used by long running requests,
+// request has no deadline, if timeout is 0, and is cancelled only when client goes away
+func (m *middleware) SetContextHeaderTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+		ctx, cancel := context.WithCancel(r.Context())
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(r.Context(), timeout)
+		}
 		defer cancel()
 
 		id := cont.ProcessID(ctx)
@@ -39,7 +86,76 @@ func (m *middleware) SetContextHeader(next http.HandlerFunc) http.HandlerFunc {
 			}
 		}
 
+		ctx = cont.SetSourceIP(cont.SetActor(ctx, m.actor(r)), m.sourceIP(r))
+
 		m.log.Infof(ctx, "request %s:%s received.", r.Method, r.URL.String())
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// actor returns who made the request, anonymous is returned, if request did not come through trusted proxy,
+// or proxy did not set one
+func (m *middleware) actor(r *http.Request) string {
+	if !m.isTrusted(remoteIP(r)) {
+		return anonymousActor
+	}
+
+	name := strings.TrimSpace(r.Header.Get(actorHeader))
+	if name == "" {
+		return anonymousActor
+	}
+
+	return name
+}
+
+// sourceIP returns IP address of client, if request came through trusted proxies, X-Forwarded-For is read
+// from the right, and the first address, that is not a trusted proxy, is used, as addresses on the left could be set by client
+func (m *middleware) sourceIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !m.isTrusted(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(r.Header.Get(forwardedForHeader), ",")
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if addr == nil {
+			break
+		}
+
+		ip = addr.String()
+
+		if !m.isTrusted(ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+// isTrusted checks, if ip is an address of trusted proxy
+func (m *middleware) isTrusted(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, network := range m.trusted {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remoteIP returns IP address of connection
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
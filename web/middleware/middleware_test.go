@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetContextHeader(t *testing.T) {
+	trusted := []string{"10.0.0.1", "192.168.0.0/16", "invalid"}
+
+	for name, tc := range map[string]struct {
+		remoteAddr       string
+		actor            string
+		forwardedFor     string
+		expectedActor    string
+		expectedSourceIP string
+	}{
+		"positive_trusted_proxy": {
+			remoteAddr:       "10.0.0.1:4000",
+			actor:            "support@faceit.com",
+			forwardedFor:     "1.2.3.4",
+			expectedActor:    "support@faceit.com",
+			expectedSourceIP: "1.2.3.4",
+		},
+		"positive_trusted_proxies_chain": {
+			remoteAddr:       "10.0.0.1:4000",
+			actor:            "support@faceit.com",
+			forwardedFor:     "6.6.6.6, 1.2.3.4, 192.168.1.1",
+			expectedActor:    "support@faceit.com",
+			expectedSourceIP: "1.2.3.4",
+		},
+		"positive_trusted_proxy_no_headers": {
+			remoteAddr:       "192.168.1.1:4000",
+			expectedActor:    anonymousActor,
+			expectedSourceIP: "192.168.1.1",
+		},
+		"negative_untrusted_client": {
+			remoteAddr:       "1.2.3.4:4000",
+			actor:            "admin",
+			forwardedFor:     "10.0.0.1",
+			expectedActor:    anonymousActor,
+			expectedSourceIP: "1.2.3.4",
+		},
+		"negative_forwarded_for_not_an_ip": {
+			remoteAddr:       "10.0.0.1:4000",
+			forwardedFor:     "1.2.3.4, very-long-garbage",
+			expectedActor:    anonymousActor,
+			expectedSourceIP: "10.0.0.1",
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+			var actor, sourceIP string
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				actor, sourceIP = cont.Actor(r.Context()), cont.SourceIP(r.Context())
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/v1/user", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.actor != "" {
+				req.Header.Set(actorHeader, tc.actor)
+			}
+			if tc.forwardedFor != "" {
+				req.Header.Set(forwardedForHeader, tc.forwardedFor)
+			}
+
+			New(logger.New(mockLogger), trusted).SetContextHeader(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expectedActor, actor)
+			assert.Equal(t, tc.expectedSourceIP, sourceIP)
+		})
+	}
+}
+
+func TestSetContextHeaderTimeout(t *testing.T) {
+	for name, tc := range map[string]struct {
+		timeout          time.Duration
+		expectedDeadline bool
+	}{
+		"positive_default":     {timeout: requestTimeout, expectedDeadline: true},
+		"positive_no_deadline": {timeout: 0, expectedDeadline: false},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+			var deadline bool
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				_, deadline = r.Context().Deadline()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/v1/admin/users/export", nil)
+
+			New(logger.New(mockLogger), nil).SetContextHeaderTimeout(tc.timeout, next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expectedDeadline, deadline)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"net/http"
+
+	"github.com/faceit/test/logger"
+	"github.com/faceit/test/services/queuestats"
+	"github.com/faceit/test/web"
+	"github.com/faceit/test/web/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is a web events handler struct
+type Handler struct {
+	router     *mux.Router
+	log        logger.Logger
+	middleware middleware.Middleware
+	stats      *queuestats.Stats
+}
+
+// NewHandler creates new queue handler instancce
+func NewHandler(router *mux.Router, l logger.Logger, m middleware.Middleware, s *queuestats.Stats) {
+	h := Handler{
+		router:     router,
+		log:        l,
+		middleware: m,
+		stats:      s,
+	}
+
+	admin := router.PathPrefix("/v1/admin").Subrouter()
+
+	admin.HandleFunc("/queue", h.middleware.SetContextHeader(http.HandlerFunc(h.Stats))).
+		Methods(http.MethodGet)
+}
+
+// Stats handles Get queue state and delivery counters requests
+func (h *Handler) Stats(w http.ResponseWriter, r *http.Request) {
+	newStats(web.NewResponse(w, h.log), h.stats).Do(web.NewRequest(r))
+}
@@ -0,0 +1,25 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/services/queuestats"
+	mock_queuestats "github.com/faceit/test/services/queuestats/mock"
+	"github.com/faceit/test/web/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestNewHandler(t *testing.T) {
+	ctr := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMocklog(ctr)
+	log := logger.New(mockLogger)
+
+	service := queuestats.New(config.Queue{}, mock_queuestats.NewMockqueue(ctr), mock_queuestats.NewMocknotifier(ctr))
+
+	NewHandler(mux.NewRouter().StrictSlash(true), log, middleware.New(log, nil), service)
+}
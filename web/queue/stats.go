@@ -0,0 +1,40 @@
+//go:generate mockgen -source ../queue/stats.go -destination ../queue/mock/mock_stats.go
+
+package queue
+
+import (
+	"context"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type stats interface {
+	Do(ctx context.Context) (entity.QueueStats, error)
+}
+
+// Stats is a queue stats endpoint struct
+type Stats struct {
+	do   stats
+	resp *web.Response
+}
+
+func newStats(r *web.Response, s stats) *Stats {
+	return &Stats{
+		do:   s,
+		resp: r,
+	}
+}
+
+// Do returnes queue state and delivery counters per consumer
+func (s *Stats) Do(r *web.Request) {
+	ctx := r.Context()
+
+	stats, err := s.do.Do(ctx)
+	if err != nil {
+		s.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	s.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, stats)
+}
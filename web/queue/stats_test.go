@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_queue "github.com/faceit/test/web/queue/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	statsURL = "http://localhost:8080/v1/admin/queue"
+)
+
+var (
+	errTest = fmt.Errorf("errTest")
+
+	testStats = entity.QueueStats{
+		Backend:   "memory",
+		Buffered:  2,
+		InFlight:  1,
+		Capacity:  100,
+		OldestAge: 1.5,
+		Consumers: []entity.ConsumerStats{
+			{Consumer: "subscription 1", Enqueued: 3, Delivered: 1},
+		},
+	}
+)
+
+type testCaseStats struct {
+	statsErr           error
+	expectedResponse   *entity.QueueStats
+	expectedStatusCode int
+}
+
+func (tc testCaseStats) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
+	assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+	if tc.expectedResponse != nil {
+		var resp entity.QueueStats
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, *tc.expectedResponse, resp)
+	}
+}
+
+func TestStats(t *testing.T) {
+	for name, tc := range map[string]testCaseStats{
+		"positive_200": {expectedResponse: &testStats, expectedStatusCode: http.StatusOK},
+		"negative_500": {statsErr: errTest, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientStats := mock_queue.NewMockstats(ctr)
+			mockClientStats.EXPECT().Do(ctx).Return(testStats, tc.statsErr)
+
+			req := httptest.NewRequest(http.MethodGet, statsURL, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newStats(web.NewResponse(w, logger), mockClientStats).Do(web.NewRequest(req))
+
+			tc.checkresult(t, w)
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -26,6 +29,11 @@ func (r *Request) Context() context.Context {
 	return r.req.Context()
 }
 
+// Body returns request body reader
+func (r *Request) Body() io.Reader {
+	return r.req.Body
+}
+
 // UnmarshalBodyJSON is unmarshalling req.body into v(should be a pointer)
 func (r *Request) UnmarshalBodyJSON(v interface{}) error {
 	err := json.NewDecoder(r.req.Body).Decode(v)
@@ -61,6 +69,55 @@ func (r *Request) GetQueryParamsString(key string) string {
 	return r.req.URL.Query().Get(key)
 }
 
+// AcceptsGzip checks, if client accepts gzip encoded response
+func (r *Request) AcceptsGzip() bool {
+	for _, enc := range strings.Split(r.req.Header.Get(acceptEncodingKey), ",") {
+		params := strings.Split(enc, ";")
+		if strings.TrimSpace(params[0]) != gzipEncoding {
+			continue
+		}
+
+		// gzip;q=0 means gzip is not accepted
+		for _, p := range params[1:] {
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(p), "q="), 64); err == nil && q == 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// IfMatch returns entity tags of If-Match header, nil is returned, if header is not set
+// strong tags are unquoted, weak tags are returned as is, so they never match, as If-Match uses strong comparison
+func (r *Request) IfMatch() []string {
+	header := r.req.Header.Get(ifMatchKey)
+	if header == "" {
+		return nil
+	}
+
+	tags := []string{}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if unquoted, err := strconv.Unquote(tag); err == nil && strings.HasPrefix(tag, `"`) {
+			tag = unquoted
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// GetQueryParams is getting all Query parameters
+func (r *Request) GetQueryParams() url.Values {
+	return r.req.URL.Query()
+}
+
 // GetQueryParamsInt is getting Query parameters of type int
 func (r *Request) GetQueryParamsInt(key string) *int {
 	return getParamInt(r.req.URL.Query().Get(key))
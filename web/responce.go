@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/faceit/test/logger"
 )
@@ -12,6 +13,8 @@ import (
 const (
 	contentTypeKey   = "Content-Type"
 	contentTypeValue = "application/json; charset=UTF-8"
+	etagKey          = "ETag"
+	ifMatchKey       = "If-Match"
 )
 
 // Response is an endpoint response struct
@@ -37,6 +40,16 @@ func (r *Response) ContentHeader(ctx context.Context) *Response {
 	return r
 }
 
+// ETag is setting an ETag header to strong entity tag, tag is quoted
+// it must be set before status code is
+func (r *Response) ETag(ctx context.Context, tag string) *Response {
+	r.log.Infof(ctx, "setting headers %s:%q", etagKey, tag)
+
+	r.writer.Header().Set(etagKey, strconv.Quote(tag))
+
+	return r
+}
+
 // Ok is setting response status code to http.StatusOK
 func (r *Response) Ok(ctx context.Context) *Response {
 	return r.setStatus(ctx, http.StatusOK)
@@ -52,6 +65,13 @@ func (r *Response) NoContent(ctx context.Context) *Response {
 	return r.setStatus(ctx, http.StatusNoContent)
 }
 
+// Warning logs err, that does not fail the request
+func (r *Response) Warning(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "request succeeded with warning, message: %s", err.Error())
+
+	return r
+}
+
 // Unauthorized is setting response status code to http.StatusUnauthorized
 func (r *Response) Unauthorized(ctx context.Context) *Response {
 	return r.setStatus(ctx, http.StatusUnauthorized)
@@ -78,6 +98,48 @@ func (r *Response) InternalServerError(ctx context.Context, err error) *Response
 	return r.setStatus(ctx, http.StatusInternalServerError)
 }
 
+// Conflict is setting response status code to http.StatusConflict
+func (r *Response) Conflict(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "conflict, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusConflict)
+}
+
+// PreconditionFailed is setting response status code to http.StatusPreconditionFailed
+func (r *Response) PreconditionFailed(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "precondition failed, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusPreconditionFailed)
+}
+
+// PreconditionRequired is setting response status code to http.StatusPreconditionRequired
+func (r *Response) PreconditionRequired(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "precondition required, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusPreconditionRequired)
+}
+
+// RequestEntityTooLarge is setting response status code to http.StatusRequestEntityTooLarge
+func (r *Response) RequestEntityTooLarge(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "request entity too large, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusRequestEntityTooLarge)
+}
+
+// BadGateway is setting response status code to http.StatusBadGateway
+func (r *Response) BadGateway(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "upstream request failed, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusBadGateway)
+}
+
+// ServiceUnavailable is setting response status code to http.StatusServiceUnavailable
+func (r *Response) ServiceUnavailable(ctx context.Context, err error) *Response {
+	r.log.Warningf(ctx, "service unavailable, message: %s", err.Error())
+
+	return r.setStatus(ctx, http.StatusServiceUnavailable)
+}
+
 // Ok is marshalling v and sets it as a response body
 func (r *Response) WithBody(ctx context.Context, v interface{}) *Response {
 	body, err := json.Marshal(v)
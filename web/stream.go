@@ -0,0 +1,70 @@
+package web
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+)
+
+// stream header constants
+const (
+	acceptEncodingKey  = "Accept-Encoding"
+	contentEncodingKey = "Content-Encoding"
+	varyKey            = "Vary"
+	trailerKey         = "Trailer"
+	gzipEncoding       = "gzip"
+
+	// StreamErrorTrailer reports error, that interrupted streamed body, as status is already sent by then
+	StreamErrorTrailer = "X-Stream-Error"
+)
+
+// Stream is a response body, that is written while it is produced
+type Stream struct {
+	writer io.Writer
+	gzip   *gzip.Writer
+	resp   *Response
+}
+
+// Stream sets content type and status 200 and returns writer of response body,
+// body is gzip encoded, if compress is true. Stream must be closed to flush the body
+func (r *Response) Stream(ctx context.Context, contentType string, compress bool) *Stream {
+	s := &Stream{writer: r.writer, resp: r}
+
+	h := r.writer.Header()
+	h.Set(contentTypeKey, contentType)
+	h.Set(trailerKey, StreamErrorTrailer)
+	h.Add(varyKey, acceptEncodingKey)
+
+	if compress {
+		h.Set(contentEncodingKey, gzipEncoding)
+
+		s.gzip = gzip.NewWriter(r.writer)
+		s.writer = s.gzip
+	}
+
+	r.Ok(ctx)
+
+	return s
+}
+
+// Write writes p into response body
+func (s *Stream) Write(p []byte) (int, error) {
+	return s.writer.Write(p)
+}
+
+// Close flushes response body, err is the one, that interrupted the body, if any
+// it is logged and sent in StreamErrorTrailer, so client knows the body is incomplete
+func (s *Stream) Close(ctx context.Context, err error) {
+	if err != nil {
+		s.resp.log.Errorf(ctx, "stream interrupted, error: %s", err.Error())
+		s.resp.writer.Header().Set(StreamErrorTrailer, err.Error())
+	}
+
+	if s.gzip == nil {
+		return
+	}
+
+	if err := s.gzip.Close(); err != nil {
+		s.resp.log.Errorf(ctx, "failed to write response body, error: %s", err.Error())
+	}
+}
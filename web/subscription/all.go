@@ -0,0 +1,40 @@
+//go:generate mockgen -source ../subscription/all.go -destination ../subscription/mock/mock_all.go
+
+package subscription
+
+import (
+	"context"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type all interface {
+	All(ctx context.Context) ([]entity.Subscription, error)
+}
+
+// All is an all subscriptions endpoint struct
+type All struct {
+	do   all
+	resp *web.Response
+}
+
+func newAll(r *web.Response, a all) *All {
+	return &All{
+		do:   a,
+		resp: r,
+	}
+}
+
+// Do is returning all subscriptions
+func (a *All) Do(r *web.Request) {
+	ctx := r.Context()
+
+	subs, err := a.do.All(ctx)
+	if err != nil {
+		a.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	a.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, subs)
+}
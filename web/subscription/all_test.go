@@ -0,0 +1,95 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	allURL = "http://localhost:8080/v1/admin/subscriptions"
+	oneURL = "http://localhost:8080/v1/admin/subscriptions/1"
+)
+
+var (
+	errTest = fmt.Errorf("errTest")
+
+	testID           = 1
+	testSubscription = entity.Subscription{
+		ID:        testID,
+		URL:       "http://consumer.test",
+		Actions:   []string{entity.ActionCreate},
+		CountryID: 2,
+		Enabled:   true,
+		Secret:    "subscription_secret",
+	}
+
+	// publicSubscription is testSubscription, as it is returned, secrets are never exposed
+	publicSubscription = entity.Subscription{
+		ID:        testID,
+		URL:       "http://consumer.test",
+		Actions:   []string{entity.ActionCreate},
+		CountryID: 2,
+		Enabled:   true,
+	}
+)
+
+func TestAll(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_subscription.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx).Return([]entity.Subscription{testSubscription}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, allURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotContains(t, w.Body.String(), testSubscription.Secret)
+
+		var resp []entity.Subscription
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []entity.Subscription{publicSubscription}, resp)
+	})
+
+	t.Run("negative_500", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_subscription.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx).Return(nil, errTest)
+
+		req := httptest.NewRequest(http.MethodGet, allURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
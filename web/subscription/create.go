@@ -0,0 +1,68 @@
+//go:generate mockgen -source ../subscription/create.go -destination ../subscription/mock/mock_create.go
+
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type create interface {
+	Create(ctx context.Context, s entity.Subscription) (entity.Subscription, error)
+}
+
+// Create is a create subscription endpoint struct
+type Create struct {
+	do   create
+	resp *web.Response
+}
+
+func newCreate(r *web.Response, c create) *Create {
+	return &Create{
+		do:   c,
+		resp: r,
+	}
+}
+
+// Do is reading request body and creates subscription, if request is valid
+func (c *Create) Do(r *web.Request) {
+	ctx := r.Context()
+
+	var reqBody entity.SubscriptionRequest
+
+	err := r.UnmarshalBodyJSON(&reqBody)
+	if err != nil {
+		c.resp.BadRequest(ctx, err)
+		return
+	}
+
+	err = reqBody.Validate()
+	if err != nil {
+		c.resp.BadRequest(ctx, err)
+		return
+	}
+
+	sub, err := c.do.Create(ctx, reqBody.ToSubscription())
+	if errors.Is(err, entity.ErrValidationFailed) {
+		c.resp.BadRequest(ctx, err)
+		return
+	}
+	if err != nil {
+		c.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	// secret is returned only here and on rotation, so consumer could verify signatures
+	var respbody struct {
+		ID     int    `json:"id"`
+		Secret string `json:"secret"`
+	}
+
+	respbody.ID = sub.ID
+	respbody.Secret = sub.Secret
+
+	c.resp.ContentHeader(ctx).Created(ctx).WithBody(ctx, respbody)
+}
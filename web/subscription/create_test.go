@@ -0,0 +1,115 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseCreate struct {
+	input              entity.SubscriptionRequest
+	createErr          error
+	expectCreate       bool
+	expectedStatusCode int
+}
+
+func TestCreate(t *testing.T) {
+	disabled := false
+
+	valid := entity.SubscriptionRequest{
+		URL:       testSubscription.URL,
+		Actions:   testSubscription.Actions,
+		CountryID: testSubscription.CountryID,
+	}
+
+	for name, tc := range map[string]testCaseCreate{
+		"positive_201": {input: valid, expectCreate: true, expectedStatusCode: http.StatusCreated},
+		"positive_201_disabled": {
+			input:              entity.SubscriptionRequest{URL: valid.URL, Actions: valid.Actions, Enabled: &disabled},
+			expectCreate:       true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		"positive_201_batch": {
+			input: entity.SubscriptionRequest{URL: valid.URL, Actions: valid.Actions,
+				Batch: &entity.Batch{Size: 100, Window: 500, Format: entity.BatchFormatNDJSON}},
+			expectCreate:       true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		"negative_400_batch_without_window": {
+			input:              entity.SubscriptionRequest{URL: valid.URL, Actions: valid.Actions, Batch: &entity.Batch{Size: 100}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_unknown_batch_format": {
+			input: entity.SubscriptionRequest{URL: valid.URL, Actions: valid.Actions,
+				Batch: &entity.Batch{Size: 100, Window: 500, Format: "xml"}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_invalid_url": {
+			input:              entity.SubscriptionRequest{URL: "consumer", Actions: valid.Actions},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_unknown_action": {
+			input:              entity.SubscriptionRequest{URL: valid.URL, Actions: []string{"PATCH"}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_short_secret": {
+			input:              entity.SubscriptionRequest{URL: valid.URL, Actions: valid.Actions, Secret: "secret"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_unknown_country": {
+			input:              valid,
+			createErr:          fmt.Errorf("%w, country 2 does not exist", entity.ErrValidationFailed),
+			expectCreate:       true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_500": {input: valid, createErr: errTest, expectCreate: true, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientCreate := mock_subscription.NewMockcreate(ctr)
+			if tc.expectCreate {
+				mockClientCreate.EXPECT().Create(ctx, tc.input.ToSubscription()).Return(testSubscription, tc.createErr)
+			}
+
+			b, err := json.Marshal(tc.input)
+			assert.Nil(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, allURL, bytes.NewReader(b)).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newCreate(web.NewResponse(w, logger), mockClientCreate).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+			if tc.expectedStatusCode == http.StatusCreated {
+				var resp map[string]interface{}
+
+				err := json.NewDecoder(w.Body).Decode(&resp)
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]interface{}{"id": float64(testID), "secret": testSubscription.Secret}, resp)
+			}
+		})
+	}
+}
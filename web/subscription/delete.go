@@ -0,0 +1,51 @@
+//go:generate mockgen -source ../subscription/delete.go -destination ../subscription/mock/mock_delete.go
+
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type delete interface {
+	Delete(ctx context.Context, id int) error
+}
+
+// Delete is a delete subscription endpoint struct
+type Delete struct {
+	do   delete
+	resp *web.Response
+}
+
+func newDelete(r *web.Response, d delete) *Delete {
+	return &Delete{
+		do:   d,
+		resp: r,
+	}
+}
+
+// Do is getting subscription's id from URL and removes it
+func (d *Delete) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		d.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	err := d.do.Delete(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		d.resp.NotFound(ctx, err)
+		return
+	}
+	if err != nil {
+		d.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	d.resp.Ok(ctx)
+}
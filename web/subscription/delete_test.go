@@ -0,0 +1,52 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseDelete struct {
+	deleteErr          error
+	expectedStatusCode int
+}
+
+func TestDelete(t *testing.T) {
+	for name, tc := range map[string]testCaseDelete{
+		"positive_200": {expectedStatusCode: http.StatusOK},
+		"negative_404": {deleteErr: entity.ErrNotFound, expectedStatusCode: http.StatusNotFound},
+		"negative_500": {deleteErr: errTest, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientDelete := mock_subscription.NewMockdelete(ctr)
+			mockClientDelete.EXPECT().Delete(ctx, testID).Return(tc.deleteErr)
+
+			req := httptest.NewRequest(http.MethodDelete, oneURL, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newDelete(web.NewResponse(w, logger), mockClientDelete).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package subscription
+
+import (
+	"net/http"
+
+	"github.com/faceit/test/logger"
+	"github.com/faceit/test/services/subscription"
+	"github.com/faceit/test/web"
+	"github.com/faceit/test/web/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is a web events handler struct
+type Handler struct {
+	router       *mux.Router
+	log          logger.Logger
+	middleware   middleware.Middleware
+	subscription *subscription.Subscription
+}
+
+// NewHandler creates new subscription handler instancce
+func NewHandler(router *mux.Router, l logger.Logger, m middleware.Middleware, s *subscription.Subscription) {
+	h := Handler{
+		router:       router,
+		log:          l,
+		middleware:   m,
+		subscription: s,
+	}
+
+	admin := router.PathPrefix("/v1/admin").Subrouter()
+
+	admin.HandleFunc("/subscriptions", h.middleware.SetContextHeader(http.HandlerFunc(h.All))).
+		Methods(http.MethodGet)
+	admin.HandleFunc("/subscriptions", h.middleware.SetContextHeader(http.HandlerFunc(h.Create))).
+		Methods(http.MethodPost)
+
+	admin.HandleFunc("/subscriptions/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.One))).
+		Methods(http.MethodGet)
+	admin.HandleFunc("/subscriptions/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Update))).
+		Methods(http.MethodPut)
+	admin.HandleFunc("/subscriptions/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Delete))).
+		Methods(http.MethodDelete)
+	admin.HandleFunc("/subscriptions/{id}/rotate", h.middleware.SetContextHeader(http.HandlerFunc(h.Rotate))).
+		Methods(http.MethodPost)
+}
+
+// All handles Get All subscriptions requests
+func (h *Handler) All(w http.ResponseWriter, r *http.Request) {
+	newAll(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
+
+// One handles Get One subscription by id requests
+func (h *Handler) One(w http.ResponseWriter, r *http.Request) {
+	newOne(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
+
+// Create handles POST Create subscription requests
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	newCreate(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
+
+// Update handles PUT Update subscription requests
+// subscription is replaced with request body
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	newUpdate(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
+
+// Delete handles DELETE subscription by id requests
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	newDelete(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
+
+// Rotate handles POST rotate subscription secret requests
+// previous secret stays active for a rotation TTL, so consumer could switch to a new one
+func (h *Handler) Rotate(w http.ResponseWriter, r *http.Request) {
+	newRotate(web.NewResponse(w, h.log), h.subscription).Do(web.NewRequest(r))
+}
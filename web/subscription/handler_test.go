@@ -0,0 +1,25 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/faceit/test/config"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/services/subscription"
+	mock_subscription "github.com/faceit/test/services/subscription/mock"
+	"github.com/faceit/test/web/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestNewHandler(t *testing.T) {
+	ctr := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMocklog(ctr)
+	log := logger.New(mockLogger)
+
+	service := subscription.New(mock_subscription.NewMockclient(ctr), config.Notifier{}, log)
+
+	NewHandler(mux.NewRouter().StrictSlash(true), log, middleware.New(log, nil), service)
+}
@@ -0,0 +1,83 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOne(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientOne := mock_subscription.NewMockone(ctr)
+		mockClientOne.EXPECT().One(ctx, testID).Return(testSubscription, nil)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mockClientOne).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotContains(t, w.Body.String(), testSubscription.Secret)
+
+		var resp entity.Subscription
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, publicSubscription, resp)
+	})
+
+	t.Run("negative_400_missing_id", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mock_subscription.NewMockone(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("negative_404", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientOne := mock_subscription.NewMockone(ctr)
+		mockClientOne.EXPECT().One(ctx, testID).Return(entity.Subscription{}, entity.ErrNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, oneURL, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newOne(web.NewResponse(w, logger), mockClientOne).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
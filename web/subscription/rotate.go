@@ -0,0 +1,51 @@
+//go:generate mockgen -source ../subscription/rotate.go -destination ../subscription/mock/mock_rotate.go
+
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type rotate interface {
+	Rotate(ctx context.Context, id int) (entity.Subscription, error)
+}
+
+// Rotate is a rotate subscription secret endpoint struct
+type Rotate struct {
+	do   rotate
+	resp *web.Response
+}
+
+func newRotate(r *web.Response, ro rotate) *Rotate {
+	return &Rotate{
+		do:   ro,
+		resp: r,
+	}
+}
+
+// Do is getting subscription's id from URL, generates a new secret and returns updated subscription with the new secret
+func (ro *Rotate) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		ro.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	sub, err := ro.do.Rotate(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		ro.resp.NotFound(ctx, err)
+		return
+	}
+	if err != nil {
+		ro.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	ro.resp.ContentHeader(ctx).Ok(ctx).WithBody(ctx, entity.SubscriptionSecretResponse{Subscription: sub, Secret: sub.Secret})
+}
@@ -0,0 +1,66 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	rotateURL = "http://localhost:8080/v1/admin/subscriptions/1/rotate"
+)
+
+type testCaseRotate struct {
+	rotateErr          error
+	expectedStatusCode int
+}
+
+func TestRotate(t *testing.T) {
+	for name, tc := range map[string]testCaseRotate{
+		"positive_200": {expectedStatusCode: http.StatusOK},
+		"negative_404": {rotateErr: entity.ErrNotFound, expectedStatusCode: http.StatusNotFound},
+		"negative_500": {rotateErr: errTest, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientRotate := mock_subscription.NewMockrotate(ctr)
+			mockClientRotate.EXPECT().Rotate(ctx, testID).Return(testSubscription, tc.rotateErr)
+
+			req := httptest.NewRequest(http.MethodPost, rotateURL, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newRotate(web.NewResponse(w, logger), mockClientRotate).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+			if tc.rotateErr == nil {
+				var resp entity.SubscriptionSecretResponse
+
+				err := json.NewDecoder(w.Body).Decode(&resp)
+				assert.Nil(t, err)
+				assert.Equal(t, testSubscription.Secret, resp.Secret)
+				assert.Equal(t, publicSubscription, resp.Subscription)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+//go:generate mockgen -source ../subscription/update.go -destination ../subscription/mock/mock_update.go
+
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type update interface {
+	Update(ctx context.Context, s entity.Subscription) error
+}
+
+// Update is an update subscription endpoint struct
+type Update struct {
+	do   update
+	resp *web.Response
+}
+
+func newUpdate(r *web.Response, u update) *Update {
+	return &Update{
+		do:   u,
+		resp: r,
+	}
+}
+
+// Do is getting subscription's id from URL and replaces subscription with request body
+func (u *Update) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamID)
+	if id == nil {
+		u.resp.BadRequest(ctx, entity.ErrIDIsMissing)
+		return
+	}
+
+	var reqBody entity.SubscriptionRequest
+
+	err := r.UnmarshalBodyJSON(&reqBody)
+	if err != nil {
+		u.resp.BadRequest(ctx, err)
+		return
+	}
+
+	err = reqBody.Validate()
+	if err != nil {
+		u.resp.BadRequest(ctx, err)
+		return
+	}
+
+	sub := reqBody.ToSubscription()
+	sub.ID = *id
+
+	err = u.do.Update(ctx, sub)
+	if errors.Is(err, entity.ErrNotFound) {
+		u.resp.NotFound(ctx, err)
+		return
+	}
+	if errors.Is(err, entity.ErrValidationFailed) {
+		u.resp.BadRequest(ctx, err)
+		return
+	}
+	if err != nil {
+		u.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	u.resp.Ok(ctx)
+}
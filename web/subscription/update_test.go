@@ -0,0 +1,73 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_subscription "github.com/faceit/test/web/subscription/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCaseUpdate struct {
+	input              entity.SubscriptionRequest
+	updateErr          error
+	expectUpdate       bool
+	expectedStatusCode int
+}
+
+func TestUpdate(t *testing.T) {
+	valid := entity.SubscriptionRequest{
+		URL:     testSubscription.URL,
+		Actions: []string{entity.ActionCreate, entity.ActionUpdate},
+	}
+
+	for name, tc := range map[string]testCaseUpdate{
+		"positive_200": {input: valid, expectUpdate: true, expectedStatusCode: http.StatusOK},
+		"negative_400_no_actions": {
+			input:              entity.SubscriptionRequest{URL: valid.URL},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_404": {input: valid, updateErr: entity.ErrNotFound, expectUpdate: true, expectedStatusCode: http.StatusNotFound},
+		"negative_500": {input: valid, updateErr: errTest, expectUpdate: true, expectedStatusCode: http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), pathParamID, testID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientUpdate := mock_subscription.NewMockupdate(ctr)
+			if tc.expectUpdate {
+				sub := tc.input.ToSubscription()
+				sub.ID = testID
+
+				mockClientUpdate.EXPECT().Update(ctx, sub).Return(tc.updateErr)
+			}
+
+			b, err := json.Marshal(tc.input)
+			assert.Nil(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, oneURL, bytes.NewReader(b)).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newUpdate(web.NewResponse(w, logger), mockClientUpdate).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -5,6 +5,9 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/web"
@@ -14,10 +17,19 @@ import (
 const (
 	queryParamTitleConst  = "title"
 	queryParamFilterConst = "filter"
+	queryParamLimitConst  = "limit"
+	queryParamCursorConst = "cursor"
+	queryParamSortConst   = "sort"
+
+	queryParamUpdatedSinceConst   = "updated_since"
+	queryParamIncludeDeletedConst = "include_deleted"
 )
 
+// filterOpSeparator separates field and operator in filter query parameter, e.g. email.icontains
+const filterOpSeparator = "."
+
 type all interface {
-	All(ctx context.Context, title, filter string) ([]entity.User, error)
+	All(ctx context.Context, filter entity.UserFilter, page entity.Page) ([]entity.User, string, error)
 }
 
 // All is a all users endpoint struct
@@ -33,26 +45,146 @@ func newAll(r *web.Response, a all) *All {
 	}
 }
 
-// Do returnes all users based on provided filters,
-// if o filters provided, all users would be returned
+// Do returnes a page of users based on provided filters,
+// if no filters provided, all users are paginated. Page size is set by limit query parameter,
+// next page is requested with cursor, returned with the previous one.
+// deleted users, that are not purged yet, are returned with deletedAt, if include_deleted is true
 func (a *All) Do(r *web.Request) {
 	ctx := r.Context()
 
-	users, err := a.do.All(ctx, r.GetQueryParamsString(queryParamTitleConst), r.GetQueryParamsString(queryParamFilterConst))
+	page, err := newPage(r)
+	if err != nil {
+		a.resp.BadRequest(ctx, err)
+		return
+	}
+
+	filter, err := newUserFilter(r)
+	if err != nil {
+		a.resp.BadRequest(ctx, err)
+		return
+	}
+
+	filter.IncludeDeleted, err = entity.NewIncludeDeleted(r.GetQueryParamsString(queryParamIncludeDeletedConst))
+	if err != nil {
+		a.resp.BadRequest(ctx, err)
+		return
+	}
+
+	users, next, err := a.do.All(ctx, filter, page)
 	if errors.Is(err, entity.ErrNotFound) {
 		a.resp.NoContent(ctx)
 		return
 	}
 
+	if errors.Is(err, entity.ErrValidationFailed) {
+		a.resp.BadRequest(ctx, err)
+		return
+	}
+
 	if err != nil {
 		a.resp.InternalServerError(ctx, err)
 		return
 	}
 
-	resp := make([]entity.UserResponse, len(users))
+	resp := entity.UserPage{
+		Users:      make([]entity.UserResponse, len(users)),
+		NextCursor: next,
+	}
+
 	for i := range users {
-		resp[i] = users[i].ToResponse()
+		resp.Users[i] = users[i].ToResponse()
 	}
 
 	a.resp.Ok(ctx).WithBody(ctx, resp)
 }
+
+// newPage returns page from limit and cursor query parameters
+func newPage(r *web.Request) (entity.Page, error) {
+	limit, err := queryLimit(r)
+	if err != nil {
+		return entity.Page{}, err
+	}
+
+	return entity.NewPage(limit, r.GetQueryParamsString(queryParamCursorConst))
+}
+
+// queryLimit returns limit query parameter, 0 is returned, if it is not set
+func queryLimit(r *web.Request) (int, error) {
+	if r.GetQueryParamsString(queryParamLimitConst) == "" {
+		return 0, nil
+	}
+
+	l := r.GetQueryParamsInt(queryParamLimitConst)
+	if l == nil || *l == 0 {
+		return 0, fmt.Errorf("%w, limit must be a positive number", entity.ErrValidationFailed)
+	}
+
+	return *l, nil
+}
+
+// newUserFilter returns user filter from query parameters
+// field parameter is matched exactly, other operators are set by suffix, e.g. email.icontains=gmail,
+// title and filter parameters are kept for compatibility, they are matched exactly as well
+// updated_since parameter selects users, that were updated at that RFC3339 time or later
+func newUserFilter(r *web.Request) (entity.UserFilter, error) {
+	var (
+		filter entity.UserFilter
+		err    error
+	)
+
+	filter.Sort, err = entity.NewSort(r.GetQueryParamsString(queryParamSortConst))
+	if err != nil {
+		return entity.UserFilter{}, err
+	}
+
+	filter.UpdatedSince, err = entity.NewUpdatedSince(r.GetQueryParamsString(queryParamUpdatedSinceConst))
+	if err != nil {
+		return entity.UserFilter{}, err
+	}
+
+	if title := r.GetQueryParamsString(queryParamTitleConst); title != "" {
+		c, err := entity.NewCondition(title, entity.FilterEq, r.GetQueryParamsString(queryParamFilterConst))
+		if err != nil {
+			return entity.UserFilter{}, err
+		}
+
+		filter.Conditions = append(filter.Conditions, c)
+	}
+
+	fields := make(map[string]struct{})
+	for _, f := range entity.FilterFields() {
+		fields[f] = struct{}{}
+	}
+
+	params := r.GetQueryParams()
+
+	// keys are sorted, so conditions order does not depend on map order
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		field, op := k, ""
+		if i := strings.Index(k, filterOpSeparator); i >= 0 {
+			field, op = k[:i], k[i+1:]
+		}
+
+		if _, ok := fields[field]; !ok {
+			continue
+		}
+
+		for _, v := range params[k] {
+			c, err := entity.NewCondition(field, op, v)
+			if err != nil {
+				return entity.UserFilter{}, err
+			}
+
+			filter.Conditions = append(filter.Conditions, c)
+		}
+	}
+
+	return filter, nil
+}
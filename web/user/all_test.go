@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/logger"
@@ -18,14 +19,14 @@ import (
 )
 
 const (
-	allURL     = "http://localhost:8080/v1/users"
-	testFilter = "filter_test"
-	testTitle  = "title_test"
+	allURL = "http://localhost:8080/v1/users"
 )
 
 var (
 	errTest = fmt.Errorf("errTest")
 
+	testPage = entity.Page{Limit: entity.PageLimitDefault}
+
 	testUser = entity.User{
 		ID:        1,
 		FirstName: "David",
@@ -36,15 +37,16 @@ var (
 		Salt:      "tre",
 		Country:   "UK",
 		CountryID: 1,
+		Version:   1,
 	}
 )
 
 type testCaseAll struct {
 	url                string
 	method             string
-	filter             string
-	title              string
-	expectedResponse   []entity.UserResponse
+	filter             entity.UserFilter
+	page               entity.Page
+	expectedResponse   *entity.UserPage
 	expectedStatusCode int
 }
 
@@ -52,11 +54,11 @@ func (tc testCaseAll) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
 	assert.Equal(t, tc.expectedStatusCode, w.Code)
 
 	if tc.expectedResponse != nil {
-		var resp []entity.UserResponse
+		var resp entity.UserPage
 
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		assert.Nil(t, err)
-		assert.Equal(t, tc.expectedResponse, resp)
+		assert.Equal(t, *tc.expectedResponse, resp)
 	}
 }
 
@@ -65,7 +67,8 @@ func TestAll(t *testing.T) {
 		tc := testCaseAll{
 			url:                allURL,
 			method:             http.MethodGet,
-			expectedResponse:   []entity.UserResponse{testUser.ToResponse()},
+			page:               testPage,
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}},
 			expectedStatusCode: http.StatusOK,
 		}
 
@@ -77,7 +80,7 @@ func TestAll(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientAll := mock_user.NewMockall(ctr)
-		mockClientAll.EXPECT().All(ctx, tc.title, tc.filter).Return([]entity.User{testUser}, nil)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser}, "", nil)
 
 		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
 		w := httptest.NewRecorder()
@@ -89,11 +92,11 @@ func TestAll(t *testing.T) {
 
 	t.Run("positive_200_with_filters", func(t *testing.T) {
 		tc := testCaseAll{
-			url:                fmt.Sprintf("%s?title=%s&filter=%s", allURL, testTitle, testFilter),
+			url:                allURL + "?title=lastName&filter=Bovie",
 			method:             http.MethodGet,
-			filter:             testFilter,
-			title:              testTitle,
-			expectedResponse:   []entity.UserResponse{testUser.ToResponse()},
+			filter:             entity.UserFilter{Conditions: []entity.Condition{{Field: entity.FieldLastName, Op: entity.FilterEq, Value: "Bovie"}}},
+			page:               testPage,
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}},
 			expectedStatusCode: http.StatusOK,
 		}
 
@@ -105,7 +108,7 @@ func TestAll(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientAll := mock_user.NewMockall(ctr)
-		mockClientAll.EXPECT().All(ctx, tc.title, tc.filter).Return([]entity.User{testUser}, nil)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser}, "", nil)
 
 		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
 		w := httptest.NewRecorder()
@@ -117,10 +120,10 @@ func TestAll(t *testing.T) {
 
 	t.Run("positive_204_no_users", func(t *testing.T) {
 		tc := testCaseAll{
-			url:                fmt.Sprintf("%s?title=%s&filter=%s", allURL, testTitle, testFilter),
+			url:                allURL + "?title=lastName&filter=Bovie",
 			method:             http.MethodGet,
-			filter:             testFilter,
-			title:              testTitle,
+			filter:             entity.UserFilter{Conditions: []entity.Condition{{Field: entity.FieldLastName, Op: entity.FilterEq, Value: "Bovie"}}},
+			page:               testPage,
 			expectedStatusCode: http.StatusNoContent,
 		}
 
@@ -132,7 +135,76 @@ func TestAll(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientAll := mock_user.NewMockall(ctr)
-		mockClientAll.EXPECT().All(ctx, tc.title, tc.filter).Return(nil, entity.ErrNotFound)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return(nil, "", entity.ErrNotFound)
+
+		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("positive_200_multiple_filters", func(t *testing.T) {
+		tc := testCaseAll{
+			url:    allURL + "?firstName.prefix=Da&email.icontains=GMAIL&country=gb&sort=lastName,-country",
+			method: http.MethodGet,
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{
+					{Field: entity.FieldCountry, Op: entity.FilterEq, Value: "GB"},
+					{Field: entity.FieldEmail, Op: entity.FilterIContains, Value: "GMAIL"},
+					{Field: entity.FieldFirstName, Op: entity.FilterPrefix, Value: "Da"},
+				},
+				Sort: []entity.Sort{
+					{Field: entity.FieldLastName},
+					{Field: entity.FieldCountry, Desc: true},
+				},
+			},
+			page:               testPage,
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}},
+			expectedStatusCode: http.StatusOK,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_user.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser}, "", nil)
+
+		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("positive_200_updated_since", func(t *testing.T) {
+		tc := testCaseAll{
+			url:    allURL + "?updated_since=2021-07-11T14:00:00%2B02:00&sort=updatedAt",
+			method: http.MethodGet,
+			filter: entity.UserFilter{
+				Sort:         []entity.Sort{{Field: entity.FieldUpdatedAt}},
+				UpdatedSince: time.Date(2021, 7, 11, 12, 0, 0, 0, time.UTC),
+			},
+			page:               testPage,
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}},
+			expectedStatusCode: http.StatusOK,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_user.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser}, "", nil)
 
 		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
 		w := httptest.NewRecorder()
@@ -142,12 +214,144 @@ func TestAll(t *testing.T) {
 		tc.checkresult(t, w)
 	})
 
+	t.Run("positive_200_include_deleted", func(t *testing.T) {
+		deletedAt := time.Date(2021, 7, 11, 13, 0, 0, 0, time.UTC)
+
+		deleted := testUser
+		deleted.ID, deleted.UpdatedAt, deleted.DeletedAt = testUserID+1, deletedAt, &deletedAt
+
+		tc := testCaseAll{
+			url:    allURL + "?updated_since=2021-07-11T12:00:00Z&sort=updatedAt&include_deleted=true",
+			method: http.MethodGet,
+			filter: entity.UserFilter{
+				Sort:           []entity.Sort{{Field: entity.FieldUpdatedAt}},
+				UpdatedSince:   time.Date(2021, 7, 11, 12, 0, 0, 0, time.UTC),
+				IncludeDeleted: true,
+			},
+			page:               testPage,
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse(), deleted.ToResponse()}},
+			expectedStatusCode: http.StatusOK,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_user.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser, deleted}, "", nil)
+
+		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("negative_400_cursor_does_not_match_sort", func(t *testing.T) {
+		tc := testCaseAll{
+			url:                fmt.Sprintf("%s?cursor=%s", allURL, entity.Cursor(testUser.ID, testUser.LastName)),
+			method:             http.MethodGet,
+			page:               entity.Page{After: testUser.ID, Keys: []string{testUser.LastName}, Limit: entity.PageLimitDefault},
+			expectedStatusCode: http.StatusBadRequest,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_user.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return(nil, "", entity.ErrValidationFailed)
+
+		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("positive_200_next_page", func(t *testing.T) {
+		tc := testCaseAll{
+			url:                fmt.Sprintf("%s?limit=1&cursor=%s", allURL, entity.Cursor(testUser.ID)),
+			method:             http.MethodGet,
+			page:               entity.Page{After: testUser.ID, Limit: 1},
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}, NextCursor: entity.Cursor(2)},
+			expectedStatusCode: http.StatusOK,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		logger := logger.New(mockLogger)
+
+		mockClientAll := mock_user.NewMockall(ctr)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return([]entity.User{testUser}, entity.Cursor(2), nil)
+
+		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newAll(web.NewResponse(w, logger), mockClientAll).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	for name, url := range map[string]string{
+		"negative_400_invalid_limit":   allURL + "?limit=many",
+		"negative_400_zero_limit":      allURL + "?limit=0",
+		"negative_400_limit_too_large": fmt.Sprintf("%s?limit=%d", allURL, entity.PageLimitMax+1),
+		"negative_400_invalid_cursor":  allURL + "?cursor=invalid",
+		"negative_400_unknown_title":   allURL + "?title=password&filter=qwerty",
+		"negative_400_unknown_op":      allURL + "?email.regexp=.*",
+		"negative_400_country_op":      allURL + "?country.prefix=G",
+		"negative_400_country_iso2":    allURL + "?country=GBR",
+		"negative_400_empty_filter":    allURL + "?nickName=",
+		"negative_400_unknown_sort":    allURL + "?sort=password",
+		"negative_400_duplicated_sort": allURL + "?sort=email,-email",
+		"negative_400_updated_since":   allURL + "?updated_since=yesterday",
+		"negative_400_include_deleted": allURL + "?include_deleted=maybe",
+	} {
+		url := url
+
+		t.Run(name, func(t *testing.T) {
+			tc := testCaseAll{
+				url:                url,
+				method:             http.MethodGet,
+				expectedStatusCode: http.StatusBadRequest,
+			}
+
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+			logger := logger.New(mockLogger)
+
+			req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newAll(web.NewResponse(w, logger), mock_user.NewMockall(ctr)).Do(web.NewRequest(req))
+
+			tc.checkresult(t, w)
+		})
+	}
+
 	t.Run("negative_500_client_error", func(t *testing.T) {
 		tc := testCaseAll{
-			url:                fmt.Sprintf("%s?title=%s&filter=%s", allURL, testTitle, testFilter),
+			url:                allURL + "?title=lastName&filter=Bovie",
 			method:             http.MethodGet,
-			filter:             testFilter,
-			title:              testTitle,
+			filter:             entity.UserFilter{Conditions: []entity.Condition{{Field: entity.FieldLastName, Op: entity.FilterEq, Value: "Bovie"}}},
+			page:               testPage,
 			expectedStatusCode: http.StatusInternalServerError,
 		}
 
@@ -160,7 +364,7 @@ func TestAll(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientAll := mock_user.NewMockall(ctr)
-		mockClientAll.EXPECT().All(ctx, tc.title, tc.filter).Return(nil, errTest)
+		mockClientAll.EXPECT().All(ctx, tc.filter, tc.page).Return(nil, "", errTest)
 
 		req := httptest.NewRequest(tc.method, tc.url, nil).WithContext(ctx)
 		w := httptest.NewRecorder()
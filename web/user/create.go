@@ -4,37 +4,46 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 
+	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/web"
 )
 
 const (
-	actionCreate = "CREATE"
+	actionCreate = entity.ActionCreate
 )
 
 type create interface {
-	Create(ctx context.Context, u entity.User) (int, error)
+	Create(ctx context.Context, u entity.User) (entity.User, error)
 }
 
 type notifier interface {
-	Add(message entity.NotifierMessage)
+	Add(ctx context.Context, message entity.NotifierMessage) error
+}
+
+// subscriptions resolves consumers of a notification
+type subscriptions interface {
+	Consumers(action string, countryID int) []string
 }
 
 // Create is a create users endpoint struct
 type Create struct {
-	do        create
-	resp      *web.Response
-	notify    notifier
-	consumers []string
+	do            create
+	resp          *web.Response
+	notify        notifier
+	subscriptions subscriptions
 }
 
-func newCreate(r *web.Response, c create, n notifier, consumers []string) *Create {
+func newCreate(r *web.Response, c create, n notifier, s subscriptions) *Create {
 	return &Create{
-		do:        c,
-		resp:      r,
-		notify:    n,
-		consumers: consumers,
+		do:            c,
+		resp:          r,
+		notify:        n,
+		subscriptions: s,
 	}
 }
 
@@ -58,25 +67,52 @@ func (c *Create) Do(r *web.Request) {
 
 	user := reqBody.ToUser()
 
-	id, err := c.do.Create(ctx, user)
+	user, err = c.do.Create(ctx, user)
+	if conflict(ctx, c.resp, err) {
+		return
+	}
+	if errors.Is(err, entity.ErrValidationFailed) {
+		c.resp.BadRequest(ctx, err)
+		return
+	}
 	if err != nil {
 		c.resp.InternalServerError(ctx, err)
 		return
 	}
 
-	user.ID = id
-
-	c.notify.Add(entity.NotifierMessage{
-		Message: entity.UserNotification{
-			User:   user.ToResponse(),
-			Action: actionCreate},
-		Consumers: c.consumers})
+	notifyStored(ctx, c.resp, c.notify, entity.NotifierMessage{
+		ID:        cont.ProcessID(ctx),
+		Key:       strconv.Itoa(user.ID),
+		Message:   entity.NewUserEvent(cont.ProcessID(ctx), actionCreate, user.ToResponse()),
+		Consumers: c.subscriptions.Consumers(actionCreate, user.CountryID)})
 
 	var respbody struct {
 		ID int `json:"id"`
 	}
 
-	respbody.ID = id
+	respbody.ID = user.ID
 
 	c.resp.Created(ctx).WithBody(ctx, respbody)
 }
+
+// notifyStored queues notification of a stored change, change and it's notification are committed into outbox
+// already, so notification, that is not queued, is delivered by outbox relay, and request still succeeds
+func notifyStored(ctx context.Context, resp *web.Response, n notifier, message entity.NotifierMessage) {
+	err := n.Add(ctx, message)
+	if err != nil {
+		resp.Warning(ctx, fmt.Errorf("notification is left for outbox relay, %w", err))
+	}
+}
+
+// conflict answers with 409 and a field, that is taken, if err is *entity.UserExistError
+// false is returned, if err is an other error
+func conflict(ctx context.Context, resp *web.Response, err error) bool {
+	var exist *entity.UserExistError
+	if !errors.As(err, &exist) {
+		return false
+	}
+
+	resp.Conflict(ctx, err).WithBody(ctx, entity.ConflictResponse{Error: exist.Error(), Field: exist.Field})
+
+	return true
+}
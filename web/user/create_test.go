@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -23,7 +24,7 @@ const (
 
 var (
 	testUserID    = 1
-	testConsumers = []string{}
+	testConsumers = []string{"http://consumer.test"}
 )
 
 type testCaseCreate struct {
@@ -51,6 +52,51 @@ func (tc testCaseCreate) checkresult(t *testing.T, w *httptest.ResponseRecorder)
 	}
 }
 
+// userEventMatcher matches notifier message with a user event, ignoring event's time
+type userEventMatcher struct {
+	event     entity.CloudEvent
+	consumers []string
+}
+
+// createdUser returns user, that is created from input
+func createdUser(input entity.UserRequest) entity.User {
+	user := input.ToUser()
+	user.ID = testUserID
+
+	return user
+}
+
+func userEvent(action string, user entity.UserResponse, consumers []string) gomock.Matcher {
+	return userEventMatcher{event: entity.NewUserEvent("", action, user), consumers: consumers}
+}
+
+func userChangeEvent(change entity.UserChange, consumers []string) gomock.Matcher {
+	return userEventMatcher{event: entity.NewUserChangeEvent("", change), consumers: consumers}
+}
+
+func (m userEventMatcher) Matches(x interface{}) bool {
+	msg, ok := x.(entity.NotifierMessage)
+	if !ok {
+		return false
+	}
+
+	event, ok := msg.Message.(entity.CloudEvent)
+	if !ok || event.Time.IsZero() {
+		return false
+	}
+
+	expected := m.event
+	expected.Time = event.Time
+
+	// messages are ordered by user id
+	return msg.Key == expected.Subject &&
+		assert.ObjectsAreEqual(m.consumers, msg.Consumers) && assert.ObjectsAreEqual(expected, event)
+}
+
+func (m userEventMatcher) String() string {
+	return fmt.Sprintf("is %s event %#v for %v", m.event.Type, m.event.Data, m.consumers)
+}
+
 func TestCreate(t *testing.T) {
 	t.Run("positive_200", func(t *testing.T) {
 		tc := testCaseCreate{
@@ -77,19 +123,59 @@ func TestCreate(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientCreate := mock_user.NewMockcreate(ctr)
-		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(testUserID, nil)
+		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(createdUser(tc.input), nil)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionCreate, tc.input.CountryID).Return(tc.consumers)
 
 		userNotify := tc.input
 		userNotify.ID = testUserID
-		mockNotifier.EXPECT().Add(entity.NotifierMessage{
-			Message: entity.UserNotification{
-				User:   userNotify.ToUser().ToResponse(),
-				Action: actionCreate,
+		mockNotifier.EXPECT().Add(ctx, userEvent(actionCreate, userNotify.ToUser().ToResponse(), tc.consumers)).Return(nil)
+
+		b, err := json.Marshal(tc.input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("positive_201_queue_full", func(t *testing.T) {
+		tc := testCaseCreate{
+			url:    createURL,
+			method: http.MethodPost,
+			input: entity.UserRequest{
+				FirstName: "David",
+				LastName:  "Bovie",
+				NickName:  "Prince",
+				Email:     "test@test.go",
+				Password:  "qwertyui",
+				CountryID: 1,
 			},
-			Consumers: tc.consumers,
-		}).Times(1)
+			consumers:          testConsumers,
+			expectedStatusCode: http.StatusCreated,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+		logger := logger.New(mockLogger)
+
+		mockClientCreate := mock_user.NewMockcreate(ctr)
+		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(createdUser(tc.input), nil)
+
+		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionCreate, tc.input.CountryID).Return(tc.consumers)
+
+		mockNotifier.EXPECT().Add(ctx, gomock.Any()).Return(entity.ErrQueueFull)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -97,7 +183,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
@@ -129,6 +215,7 @@ func TestCreate(t *testing.T) {
 		mockClientCreate := mock_user.NewMockcreate(ctr)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -136,7 +223,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
@@ -168,6 +255,7 @@ func TestCreate(t *testing.T) {
 		mockClientCreate := mock_user.NewMockcreate(ctr)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -175,7 +263,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
@@ -207,6 +295,7 @@ func TestCreate(t *testing.T) {
 		mockClientCreate := mock_user.NewMockcreate(ctr)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -214,7 +303,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
@@ -246,6 +335,7 @@ func TestCreate(t *testing.T) {
 		mockClientCreate := mock_user.NewMockcreate(ctr)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -253,7 +343,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
@@ -283,9 +373,92 @@ func TestCreate(t *testing.T) {
 		logger := logger.New(mockLogger)
 
 		mockClientCreate := mock_user.NewMockcreate(ctr)
-		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(0, errTest)
+		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(entity.User{}, errTest)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+
+		b, err := json.Marshal(tc.input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+	})
+
+	t.Run("negative_409_user_exist", func(t *testing.T) {
+		tc := testCaseCreate{
+			url:    createURL,
+			method: http.MethodPost,
+			input: entity.UserRequest{
+				FirstName: "David",
+				LastName:  "Bovie",
+				NickName:  "Prince",
+				Email:     "test@test.go",
+				Password:  "qwertyui",
+				CountryID: 1,
+			},
+			expectedStatusCode: http.StatusConflict,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+		logger := logger.New(mockLogger)
+
+		exist := &entity.UserExistError{Field: entity.FieldEmail}
+
+		mockClientCreate := mock_user.NewMockcreate(ctr)
+		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(entity.User{}, fmt.Errorf("%w, rollback failed", exist))
+
+		b, err := json.Marshal(tc.input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+		tc.checkresult(t, w)
+
+		var resp entity.ConflictResponse
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ConflictResponse{Error: "user exist, email is taken", Field: entity.FieldEmail}, resp)
+	})
+
+	t.Run("negative_400_country_not_found", func(t *testing.T) {
+		tc := testCaseCreate{
+			url:    createURL,
+			method: http.MethodPost,
+			input: entity.UserRequest{
+				FirstName: "David",
+				LastName:  "Bovie",
+				NickName:  "Prince",
+				Email:     "test@test.go",
+				Password:  "qwertyui",
+				CountryID: 1000,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+		logger := logger.New(mockLogger)
+
+		mockClientCreate := mock_user.NewMockcreate(ctr)
+		mockClientCreate.EXPECT().Create(ctx, tc.input.ToUser()).Return(entity.User{}, entity.ErrValidationFailed)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -293,7 +466,7 @@ func TestCreate(t *testing.T) {
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
 		w := httptest.NewRecorder()
 
-		newCreate(web.NewResponse(w, logger), mockClientCreate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newCreate(web.NewResponse(w, logger), mockClientCreate, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
 	})
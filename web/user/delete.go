@@ -5,33 +5,35 @@ package user
 import (
 	"context"
 	"errors"
+	"strconv"
 
+	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/web"
 )
 
 const (
-	actionDelete = "DELETE"
+	actionDelete = entity.ActionDelete
 )
 
 type delete interface {
-	Delete(ctx context.Context, user entity.User) error
+	Delete(ctx context.Context, user entity.User) (entity.User, error)
 }
 
 // Delete is a delete users endpoint struct
 type Delete struct {
-	do        delete
-	resp      *web.Response
-	notify    notifier
-	consumers []string
+	do            delete
+	resp          *web.Response
+	notify        notifier
+	subscriptions subscriptions
 }
 
-func newDelete(r *web.Response, d delete, n notifier, consumers []string) *Delete {
+func newDelete(r *web.Response, d delete, n notifier, s subscriptions) *Delete {
 	return &Delete{
-		do:        d,
-		resp:      r,
-		notify:    n,
-		consumers: consumers,
+		do:            d,
+		resp:          r,
+		notify:        n,
+		subscriptions: s,
 	}
 }
 
@@ -56,7 +58,7 @@ func (d *Delete) Do(r *web.Request) {
 	deleteUser := reqBody.ToUser()
 	deleteUser.ID = *id
 
-	err = d.do.Delete(ctx, deleteUser)
+	deleted, err := d.do.Delete(ctx, deleteUser)
 	if errors.Is(err, entity.ErrNotFound) {
 		d.resp.NotFound(ctx, err)
 		return
@@ -70,11 +72,11 @@ func (d *Delete) Do(r *web.Request) {
 		return
 	}
 
-	d.notify.Add(entity.NotifierMessage{
-		Message: entity.UserNotification{
-			User:   deleteUser.ToResponse(),
-			Action: actionDelete},
-		Consumers: d.consumers})
+	notifyStored(ctx, d.resp, d.notify, entity.NotifierMessage{
+		ID:        cont.ProcessID(ctx),
+		Key:       strconv.Itoa(deleted.ID),
+		Message:   entity.NewUserEvent(cont.ProcessID(ctx), actionDelete, deleted.ToResponse()),
+		Consumers: d.subscriptions.Consumers(actionDelete, deleted.CountryID)})
 
 	d.resp.Ok(ctx)
 }
@@ -60,19 +60,24 @@ func TestDelete(t *testing.T) {
 		deleteUser := tc.input
 		deleteUser.ID = testUserID
 
-		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(nil)
+		// deleted user's snapshot is notified instead of request payload
+		deleted := entity.User{
+			ID:        testUserID,
+			FirstName: "David",
+			LastName:  "Bovie",
+			NickName:  "Prince",
+			Email:     "test@test.go",
+			Country:   "UK",
+			CountryID: 1,
+		}
+
+		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(deleted, nil)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionDelete, deleted.CountryID).Return(tc.consumers)
 
-		userNotify := tc.input
-		userNotify.ID = testUserID
-		mockNotifier.EXPECT().Add(entity.NotifierMessage{
-			Message: entity.UserNotification{
-				User:   deleteUser.ToUser().ToResponse(),
-				Action: actionDelete,
-			},
-			Consumers: tc.consumers,
-		}).Times(1)
+		mockNotifier.EXPECT().Add(ctx, userEvent(actionDelete, deleted.ToResponse(), tc.consumers)).Return(nil)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -81,7 +86,7 @@ func TestDelete(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -112,9 +117,10 @@ func TestDelete(t *testing.T) {
 		deleteUser := tc.input
 		deleteUser.ID = testUserID
 
-		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(entity.ErrNotFound)
+		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(entity.User{}, entity.ErrNotFound)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -123,7 +129,7 @@ func TestDelete(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -154,9 +160,10 @@ func TestDelete(t *testing.T) {
 		deleteUser := tc.input
 		deleteUser.ID = testUserID
 
-		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(entity.ErrInvalidPassword)
+		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(entity.User{}, entity.ErrInvalidPassword)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -165,7 +172,7 @@ func TestDelete(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -197,6 +204,7 @@ func TestDelete(t *testing.T) {
 		deleteUser.ID = testUserID
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -205,7 +213,7 @@ func TestDelete(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -236,9 +244,10 @@ func TestDelete(t *testing.T) {
 		deleteUser := tc.input
 		deleteUser.ID = testUserID
 
-		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(errTest)
+		mockClientDelete.EXPECT().Delete(ctx, deleteUser.ToUser()).Return(entity.User{}, errTest)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
@@ -247,7 +256,7 @@ func TestDelete(t *testing.T) {
 
 		w := httptest.NewRecorder()
 
-		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newDelete(web.NewResponse(w, logger), mockClientDelete, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
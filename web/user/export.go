@@ -0,0 +1,160 @@
+//go:generate mockgen -source ../user/export.go -destination ../user/mock/mock_export.go
+
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+// export formats
+const (
+	queryParamFormatConst = "format"
+
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeCSV    = "text/csv; charset=UTF-8"
+)
+
+// csvFormulaPrefixes are first charecters of cell, that spreadsheets read as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvHeader is a header row of csv export, columns are in the same order as in userRecord
+var csvHeader = []string{"id", "firstName", "lastName", "nickName", "email", "country", "createdAt", "updatedAt"}
+
+type export interface {
+	Export(ctx context.Context, filter entity.UserFilter, fn func(entity.User) error) error
+}
+
+// encoder writes users into export body
+type encoder interface {
+	Encode(u entity.User) error
+	Flush() error
+}
+
+// Export is a users export endpoint struct
+type Export struct {
+	do   export
+	resp *web.Response
+}
+
+func newExport(r *web.Response, e export) *Export {
+	return &Export{
+		do:   e,
+		resp: r,
+	}
+}
+
+// Do streams all users, that match filters, in format, set by format query parameter, ndjson is used by default.
+// users are written as they are read from store, so memory does not depend on number of users.
+// body is gzip encoded, if client accepts it
+func (e *Export) Do(r *web.Request) {
+	ctx := r.Context()
+
+	format := r.GetQueryParamsString(queryParamFormatConst)
+	if format == "" {
+		format = exportFormatNDJSON
+	}
+
+	contentType, ok := map[string]string{
+		exportFormatNDJSON: contentTypeNDJSON,
+		exportFormatCSV:    contentTypeCSV,
+	}[format]
+	if !ok {
+		e.resp.BadRequest(ctx, fmt.Errorf("%w, format must be %s or %s", entity.ErrValidationFailed, exportFormatNDJSON, exportFormatCSV))
+		return
+	}
+
+	filter, err := newUserFilter(r)
+	if err != nil {
+		e.resp.BadRequest(ctx, err)
+		return
+	}
+
+	stream := e.resp.Stream(ctx, contentType, r.AcceptsGzip())
+
+	enc, err := newEncoder(format, stream)
+	if err == nil {
+		err = e.do.Export(ctx, filter, enc.Encode)
+	}
+
+	if err == nil {
+		err = enc.Flush()
+	}
+
+	stream.Close(ctx, err)
+}
+
+// newEncoder returns encoder of format, that writes into w
+func newEncoder(format string, w io.Writer) (encoder, error) {
+	if format == exportFormatCSV {
+		return newCSVEncoder(w)
+	}
+
+	return ndjsonEncoder{json.NewEncoder(w)}, nil
+}
+
+// ndjsonEncoder writes every user as JSON object on it's own line
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+// Encode writes user, json.Encoder ends every value with a new line
+func (n ndjsonEncoder) Encode(u entity.User) error {
+	return n.enc.Encode(u.ToResponse())
+}
+
+// Flush does nothing, as json.Encoder does not buffer
+func (n ndjsonEncoder) Flush() error {
+	return nil
+}
+
+// csvEncoder writes every user as csv row
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+// newCSVEncoder creates csvEncoder and writes header row
+func newCSVEncoder(w io.Writer) (csvEncoder, error) {
+	c := csvEncoder{csv.NewWriter(w)}
+
+	return c, c.w.Write(csvHeader)
+}
+
+// Encode writes user row
+func (c csvEncoder) Encode(u entity.User) error {
+	return c.w.Write(userRecord(u.ToResponse()))
+}
+
+// Flush writes buffered rows
+func (c csvEncoder) Flush() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// userRecord returns csv record of user, text cells are escaped, as they are set by users
+func userRecord(u entity.UserResponse) []string {
+	return []string{strconv.Itoa(u.ID), csvCell(u.FirstName), csvCell(u.LastName), csvCell(u.NickName), csvCell(u.Email),
+		csvCell(u.Country), u.CreatedAt.Format(time.RFC3339Nano), u.UpdatedAt.Format(time.RFC3339Nano)}
+}
+
+// csvCell prefixes cell, that would be read as a formula by spreadsheet, with a quote, so it is read as a text
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune(csvFormulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
@@ -0,0 +1,190 @@
+package user
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	exportURL = "http://localhost:8080/v1/admin/users/export"
+)
+
+var (
+	testExportUsers = []entity.User{
+		testUser,
+		{ID: 2, FirstName: "Amy", LastName: "Lee", NickName: "Gothic, princess", Email: "amy@test.go", Country: "US",
+			CreatedAt: time.Date(2021, 7, 10, 12, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 7, 11, 12, 30, 0, 0, time.UTC)},
+	}
+)
+
+type testCaseExport struct {
+	url                 string
+	gzip                bool
+	filter              entity.UserFilter
+	err                 error
+	expectedContentType string
+	expectedBody        string
+	expectedTrailer     string
+	expectedStatusCode  int
+}
+
+func (tc testCaseExport) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
+	resp := w.Result()
+
+	assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+
+	if tc.expectedStatusCode != http.StatusOK {
+		return
+	}
+
+	assert.Equal(t, tc.expectedContentType, resp.Header.Get("Content-Type"))
+
+	var body io.Reader = resp.Body
+
+	if tc.gzip {
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+		gz, err := gzip.NewReader(resp.Body)
+		assert.Nil(t, err)
+
+		body = gz
+	}
+
+	data, err := ioutil.ReadAll(body)
+	assert.Nil(t, err)
+	assert.Equal(t, tc.expectedBody, string(data))
+	assert.Equal(t, tc.expectedTrailer, resp.Trailer.Get(web.StreamErrorTrailer))
+}
+
+func TestExport(t *testing.T) {
+	ndjson := `{"id":1,"firstName":"David","lastName":"Bovie","nickName":"Prince","email":"test@test.go","country":"UK","createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z"}` + "\n" +
+		`{"id":2,"firstName":"Amy","lastName":"Lee","nickName":"Gothic, princess","email":"amy@test.go","country":"US","createdAt":"2021-07-10T12:00:00Z","updatedAt":"2021-07-11T12:30:00Z"}` + "\n"
+
+	csv := "id,firstName,lastName,nickName,email,country,createdAt,updatedAt\n" +
+		"1,David,Bovie,Prince,test@test.go,UK,0001-01-01T00:00:00Z,0001-01-01T00:00:00Z\n" +
+		"2,Amy,Lee,\"Gothic, princess\",amy@test.go,US,2021-07-10T12:00:00Z,2021-07-11T12:30:00Z\n"
+
+	for name, tc := range map[string]testCaseExport{
+		"positive_200_ndjson_default": {
+			url:                 exportURL,
+			expectedContentType: contentTypeNDJSON,
+			expectedBody:        ndjson,
+			expectedStatusCode:  http.StatusOK,
+		},
+		"positive_200_csv": {
+			url:                 exportURL + "?format=csv",
+			expectedContentType: contentTypeCSV,
+			expectedBody:        csv,
+			expectedStatusCode:  http.StatusOK,
+		},
+		"positive_200_gzip": {
+			url:                 exportURL + "?format=ndjson",
+			gzip:                true,
+			expectedContentType: contentTypeNDJSON,
+			expectedBody:        ndjson,
+			expectedStatusCode:  http.StatusOK,
+		},
+		"positive_200_filters": {
+			url: exportURL + "?format=csv&country=us&sort=-lastName",
+			filter: entity.UserFilter{
+				Conditions: []entity.Condition{{Field: entity.FieldCountry, Op: entity.FilterEq, Value: "US"}},
+				Sort:       []entity.Sort{{Field: entity.FieldLastName, Desc: true}},
+			},
+			expectedContentType: contentTypeCSV,
+			expectedBody:        csv,
+			expectedStatusCode:  http.StatusOK,
+		},
+		"negative_200_interrupted": {
+			url:                 exportURL,
+			err:                 errTest,
+			expectedContentType: contentTypeNDJSON,
+			expectedBody:        ndjson,
+			expectedTrailer:     errTest.Error(),
+			expectedStatusCode:  http.StatusOK,
+		},
+		"negative_400_format": {
+			url:                exportURL + "?format=xml",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_filter": {
+			url:                exportURL + "?country.icontains=u",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientExport := mock_user.NewMockexport(ctr)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				mockClientExport.EXPECT().Export(ctx, tc.filter, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ entity.UserFilter, fn func(entity.User) error) error {
+						for _, u := range testExportUsers {
+							if err := fn(u); err != nil {
+								return err
+							}
+						}
+
+						return tc.err
+					})
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil).WithContext(ctx)
+			if tc.gzip {
+				req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+			}
+
+			w := httptest.NewRecorder()
+
+			newExport(web.NewResponse(w, logger), mockClientExport).Do(web.NewRequest(req))
+
+			tc.checkresult(t, w)
+		})
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cell     string
+		expected string
+	}{
+		"positive_text":            {cell: "David", expected: "David"},
+		"positive_empty":           {cell: "", expected: ""},
+		"positive_inner_formula":   {cell: "a=1+2", expected: "a=1+2"},
+		"negative_formula":         {cell: "=HYPERLINK(\"http://evil.test\")", expected: "'=HYPERLINK(\"http://evil.test\")"},
+		"negative_plus":            {cell: "+1+2", expected: "'+1+2"},
+		"negative_minus":           {cell: "-1+2", expected: "'-1+2"},
+		"negative_at":              {cell: "@SUM(A1:A2)", expected: "'@SUM(A1:A2)"},
+		"negative_tab":             {cell: "\t=1+2", expected: "'\t=1+2"},
+		"negative_carriage_return": {cell: "\r=1+2", expected: "'\r=1+2"},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, csvCell(tc.cell))
+		})
+	}
+}
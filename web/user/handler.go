@@ -2,14 +2,18 @@ package user
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/faceit/test/config"
 	"github.com/faceit/test/logger"
 	"github.com/faceit/test/queue"
+	"github.com/faceit/test/services/audit"
 	"github.com/faceit/test/services/country"
 	"github.com/faceit/test/services/hasher"
 	"github.com/faceit/test/services/password"
+	"github.com/faceit/test/services/subscription"
 	"github.com/faceit/test/services/user"
+	"github.com/faceit/test/services/userimport"
 	"github.com/faceit/test/web"
 	"github.com/faceit/test/web/middleware"
 
@@ -18,29 +22,43 @@ import (
 
 // Handler is a web events handler struct
 type Handler struct {
-	router      *mux.Router
-	notifierCFG config.Notifier
-	log         logger.Logger
-	middleware  middleware.Middleware
-	queue       queue.Queue
-	user        *user.User
-	country     *country.Country
-	password    *password.Password
-	hssher      *hasher.Hasher
+	router        *mux.Router
+	subscriptions *subscription.Subscription
+	log           logger.Logger
+	middleware    middleware.Middleware
+	queue         queue.Backend
+	user          *user.User
+	country       *country.Country
+	password      *password.Password
+	hssher        *hasher.Hasher
+	imports       *userimport.Import
+	importMaxRows int
+	importMaxLine int
+	importMaxBody int64
+	importTimeout time.Duration
+	audit         *audit.Audit
 }
 
 // NewHandler creates new user handler instancce
-func NewHandler(r *mux.Router, l logger.Logger, m middleware.Middleware,
-	u *user.User, c *country.Country, p *password.Password, hash *hasher.Hasher, q queue.Queue) {
+func NewHandler(r *mux.Router, l logger.Logger, m middleware.Middleware, s *subscription.Subscription,
+	u *user.User, c *country.Country, p *password.Password, hash *hasher.Hasher, q queue.Backend,
+	i *userimport.Import, cfg config.Import, a *audit.Audit) {
 	h := Handler{
-		router:     r,
-		log:        l,
-		middleware: m,
-		queue:      q,
-		user:       u,
-		country:    c,
-		password:   p,
-		hssher:     hash,
+		router:        r,
+		subscriptions: s,
+		log:           l,
+		middleware:    m,
+		queue:         q,
+		user:          u,
+		country:       c,
+		password:      p,
+		hssher:        hash,
+		imports:       i,
+		importMaxRows: cfg.MaxRows,
+		importMaxLine: cfg.MaxLineSize,
+		importMaxBody: cfg.MaxBodySize(),
+		importTimeout: time.Duration(cfg.Timeout) * time.Second,
+		audit:         a,
 	}
 
 	apiV1 := h.router.PathPrefix("/v1").Subrouter()
@@ -50,14 +68,33 @@ func NewHandler(r *mux.Router, l logger.Logger, m middleware.Middleware,
 	apiV1.HandleFunc("/user", h.middleware.SetContextHeader(http.HandlerFunc(h.Create))).
 		Methods(http.MethodPost)
 
+	// search is registered before /user/{id}, so it is not matched as user id
+	apiV1.HandleFunc("/user/search", h.middleware.SetContextHeader(http.HandlerFunc(h.Search))).
+		Methods(http.MethodGet)
+
 	apiV1.HandleFunc("/user/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.One))).
 		Methods(http.MethodGet)
 	apiV1.HandleFunc("/user/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Update))).
 		Methods(http.MethodPut)
+	apiV1.HandleFunc("/user/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Patch))).
+		Methods(http.MethodPatch)
 	apiV1.HandleFunc("/user/{id}/password", h.middleware.SetContextHeader(http.HandlerFunc(h.UpdatePassword))).
 		Methods(http.MethodPut)
 	apiV1.HandleFunc("/user/{id}", h.middleware.SetContextHeader(http.HandlerFunc(h.Delete))).
 		Methods(http.MethodDelete)
+
+	admin := h.router.PathPrefix("/v1/admin").Subrouter()
+
+	// export streams until all users are written, so it has no deadline, and stops, when client goes away
+	admin.HandleFunc("/users/export", h.middleware.SetContextHeaderTimeout(0, http.HandlerFunc(h.Export))).
+		Methods(http.MethodGet)
+	// import of MaxRows rows takes longer, than any other request, so it has it's own timeout
+	admin.HandleFunc("/users/import", h.middleware.SetContextHeaderTimeout(h.importTimeout, http.HandlerFunc(h.Import))).
+		Methods(http.MethodPost)
+	admin.HandleFunc("/user/{id}/restore", h.middleware.SetContextHeader(http.HandlerFunc(h.Restore))).
+		Methods(http.MethodPost)
+	admin.HandleFunc("/user/{id}/history", h.middleware.SetContextHeader(http.HandlerFunc(h.History))).
+		Methods(http.MethodGet)
 }
 
 // All handles Get All users requests
@@ -67,6 +104,27 @@ func (h *Handler) All(w http.ResponseWriter, r *http.Request) {
 	newAll(web.NewResponse(w, h.log), h.user).Do(web.NewRequest(r))
 }
 
+// Search handles Get user search requests
+// it returns users, that match search query, ordered by relevance
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	newSearch(web.NewResponse(w, h.log), h.user).Do(web.NewRequest(r))
+}
+
+// Export handles Get users export requests
+// it streams all users, that match filters, as NDJSON or CSV
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	newExport(web.NewResponse(w, h.log), h.user).Do(web.NewRequest(r))
+}
+
+// Import handles POST users import requests
+// it creates users from NDJSON or CSV body and reports result of every row,
+// body is limited, so it is never read into memory beyond size of the biggest allowed import
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBody)
+
+	newImport(web.NewResponse(w, h.log), h.imports, h.importMaxRows, h.importMaxLine).Do(web.NewRequest(r))
+}
+
 // One handles Get One user by userID requests
 func (h *Handler) One(w http.ResponseWriter, r *http.Request) {
 	newOne(web.NewResponse(w, h.log), h.user).Do(web.NewRequest(r))
@@ -74,7 +132,7 @@ func (h *Handler) One(w http.ResponseWriter, r *http.Request) {
 
 // Create handles POST Create user requests
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
-	newCreate(web.NewResponse(w, h.log), h.user, h.queue, h.notifierCFG.OnCreate()).Do(web.NewRequest(r))
+	newCreate(web.NewResponse(w, h.log), h.user, h.queue, h.subscriptions).Do(web.NewRequest(r))
 }
 
 // Update handles PUT Update user requests
@@ -84,7 +142,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 // to perform update user must send his current password
 // for authorization
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
-	newUpdate(web.NewResponse(w, h.log), h.user, h.queue, h.notifierCFG.OnUpdate()).Do(web.NewRequest(r))
+	newUpdate(web.NewResponse(w, h.log), h.user, h.queue, h.subscriptions).Do(web.NewRequest(r))
+}
+
+// Patch handles PATCH user requests with JSON merge patch (RFC 7396) body
+// only fields, that are set by patch, are changed, user's current password
+// must be sent in patch for authorization, like in Update
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	newPatch(web.NewResponse(w, h.log), h.user, h.queue, h.subscriptions).Do(web.NewRequest(r))
 }
 
 // UpdatePassword handles PUT UpdatePassword user requests
@@ -92,8 +157,20 @@ func (h *Handler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	newUpdatePassword(web.NewResponse(w, h.log), h.password).Do(web.NewRequest(r))
 }
 
+// Restore handles POST restore deleted user requests
+// user could be restored until it is purged
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	newRestore(web.NewResponse(w, h.log), h.user, h.queue, h.subscriptions).Do(web.NewRequest(r))
+}
+
+// History handles Get user's history requests
+// it returns a page of user's changes, the latest first
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	newHistory(web.NewResponse(w, h.log), h.audit).Do(web.NewRequest(r))
+}
+
 // Delete handles delete request
 // it requires users password in order to delete user
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
-	newDelete(web.NewResponse(w, h.log), h.user, h.queue, h.notifierCFG.OnDelete()).Do(web.NewRequest(r))
+	newDelete(web.NewResponse(w, h.log), h.user, h.queue, h.subscriptions).Do(web.NewRequest(r))
 }
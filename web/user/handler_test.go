@@ -8,16 +8,23 @@ import (
 	mock_logger "github.com/faceit/test/logger/mock"
 	"github.com/faceit/test/queue"
 	queue_mock "github.com/faceit/test/queue/mock"
+	"github.com/faceit/test/services/audit"
+	mock_audit "github.com/faceit/test/services/audit/mock"
 	"github.com/faceit/test/services/country"
 	mock_country "github.com/faceit/test/services/country/mock"
 	"github.com/faceit/test/services/hasher"
 	"github.com/faceit/test/services/password"
 	mock_password "github.com/faceit/test/services/password/mock"
+	"github.com/faceit/test/services/subscription"
+	mock_subscription "github.com/faceit/test/services/subscription/mock"
 	"github.com/faceit/test/services/user"
 	mock_user "github.com/faceit/test/services/user/mock"
+	"github.com/faceit/test/services/userimport"
+	mock_userimport "github.com/faceit/test/services/userimport/mock"
 	"github.com/faceit/test/web/middleware"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewhandler(t *testing.T) {
@@ -36,20 +43,25 @@ func TestNewhandler(t *testing.T) {
 
 	mockUserClient := mock_user.NewMockclient(ctr)
 	mockUserHasher := mock_user.NewMockhasher(ctr)
-	mockUser := user.New(mockUserClient, mockUserHasher, mockPasswordClient)
+	mockUser := user.New(mockUserClient, mockUserHasher, mockPasswordClient, nil)
 
 	hasher := hasher.New()
 	mockNotifier := queue_mock.NewMocknotifier(ctr)
-	queue := queue.New(config.Queue{}, mockNotifier)
+	queue, err := queue.New(config.Queue{}, mockNotifier, nil)
+	assert.Nil(t, err)
 
 	NewHandler(
 		mux.NewRouter().StrictSlash(true),
 		logger,
-		middleware.New(logger),
+		middleware.New(logger, nil),
+		subscription.New(mock_subscription.NewMockclient(ctr), config.Notifier{}, logger),
 		mockUser,
 		mockCountry,
 		mockPassword,
 		hasher,
-		*queue,
+		queue,
+		userimport.New(config.Import{}, mock_userimport.NewMockclient(ctr), hasher, logger),
+		config.Import{},
+		audit.New(mock_audit.NewMockclient(ctr)),
 	)
 }
@@ -0,0 +1,58 @@
+//go:generate mockgen -source ../user/history.go -destination ../user/mock/mock_history.go
+
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type history interface {
+	History(ctx context.Context, userID int, page entity.Page) ([]entity.Audit, string, error)
+}
+
+// History is a user's history endpoint struct
+type History struct {
+	do   history
+	resp *web.Response
+}
+
+func newHistory(r *web.Response, h history) *History {
+	return &History{
+		do:   h,
+		resp: r,
+	}
+}
+
+// Do is getting user's id from URL and returning a page of changes of user with that ID, the latest first
+// history is kept after user is purged
+func (h *History) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamUserID)
+	if id == nil {
+		h.resp.BadRequest(ctx, entity.ErrUserIDIsMissing)
+		return
+	}
+
+	page, err := newPage(r)
+	if err != nil {
+		h.resp.BadRequest(ctx, err)
+		return
+	}
+
+	records, next, err := h.do.History(ctx, *id, page)
+	if errors.Is(err, entity.ErrValidationFailed) {
+		h.resp.BadRequest(ctx, err)
+		return
+	}
+	if err != nil {
+		h.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	h.resp.Ok(ctx).WithBody(ctx, entity.AuditPage{Records: records, NextCursor: next})
+}
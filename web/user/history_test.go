@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	historyURL = "http://localhost:8080/v1/admin/user/%d/history"
+)
+
+var testAudit = []entity.Audit{
+	{
+		ID:        2,
+		UserID:    testUserID,
+		Action:    entity.ActionUpdate,
+		Actor:     "admin",
+		ProcessID: "process_id",
+		SourceIP:  "10.0.0.1",
+		Old:       json.RawMessage(`{"id":1,"nickName":"Freddy"}`),
+		New:       json.RawMessage(`{"id":1,"nickName":"Prince"}`),
+	},
+	{
+		ID:       1,
+		UserID:   testUserID,
+		Action:   entity.AuditActionPassword,
+		Actor:    "anonymous",
+		SourceIP: "10.0.0.2",
+	},
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+		mockClientHistory := mock_user.NewMockhistory(ctr)
+		mockClientHistory.EXPECT().History(ctx, testUserID, entity.Page{After: 3, Limit: 2}).Return(testAudit, "next", nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(historyURL, testUserID)+"?limit=2&cursor="+entity.Cursor(3), nil).
+			WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newHistory(web.NewResponse(w, logger.New(mockLogger)), mockClientHistory).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp entity.AuditPage
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.AuditPage{Records: testAudit, NextCursor: "next"}, resp)
+	})
+
+	t.Run("negative_400_invalid_cursor", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(historyURL, testUserID)+"?cursor=!", nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newHistory(web.NewResponse(w, logger.New(mockLogger)), mock_user.NewMockhistory(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("negative_400_missing_userID", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.Background()
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(historyURL, testUserID), nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newHistory(web.NewResponse(w, logger.New(mockLogger)), mock_user.NewMockhistory(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("negative_500_client_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+
+		mockClientHistory := mock_user.NewMockhistory(ctr)
+		mockClientHistory.EXPECT().History(ctx, testUserID, entity.Page{Limit: entity.PageLimitDefault}).Return(nil, "", errTest)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(historyURL, testUserID), nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newHistory(web.NewResponse(w, logger.New(mockLogger)), mockClientHistory).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,251 @@
+//go:generate mockgen -source ../user/import.go -destination ../user/mock/mock_import.go
+
+package user
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+// csv import columns, they are matched by header row, so their order does not matter
+const (
+	importColumnFirstName = "firstName"
+	importColumnLastName  = "lastName"
+	importColumnNickName  = "nickName"
+	importColumnEmail     = "email"
+	importColumnPassword  = "password"
+	importColumnCountry   = "country"
+)
+
+var importColumns = []string{
+	importColumnFirstName,
+	importColumnLastName,
+	importColumnNickName,
+	importColumnEmail,
+	importColumnPassword,
+	importColumnCountry,
+}
+
+type importer interface {
+	Do(ctx context.Context, rows []entity.ImportRow) entity.ImportReport
+}
+
+// bodyTooLarge is a message of http.MaxBytesReader error, it has no error type to match
+const bodyTooLarge = "request body too large"
+
+// Import is a users import endpoint struct
+type Import struct {
+	do          importer
+	resp        *web.Response
+	maxRows     int
+	maxLineSize int
+}
+
+func newImport(r *web.Response, i importer, maxRows, maxLineSize int) *Import {
+	return &Import{
+		do:          i,
+		resp:        r,
+		maxRows:     maxRows,
+		maxLineSize: maxLineSize,
+	}
+}
+
+// Do reads users from request body in format, set by format query parameter, ndjson is used by default,
+// and creates them. Result of every row is returned, import succeeds even if some rows failed
+func (i *Import) Do(r *web.Request) {
+	ctx := r.Context()
+
+	format := r.GetQueryParamsString(queryParamFormatConst)
+
+	var (
+		rows []entity.ImportRow
+		err  error
+	)
+
+	switch format {
+	case "", exportFormatNDJSON:
+		rows, err = readNDJSON(r.Body(), i.maxRows, i.maxLineSize)
+	case exportFormatCSV:
+		rows, err = readCSV(&lineLimitReader{r: r.Body(), max: i.maxLineSize}, i.maxRows)
+	default:
+		err = fmt.Errorf("%w, format must be %s or %s", entity.ErrValidationFailed, exportFormatNDJSON, exportFormatCSV)
+	}
+
+	if err != nil && strings.Contains(err.Error(), bodyTooLarge) {
+		i.resp.RequestEntityTooLarge(ctx, err)
+		return
+	}
+
+	if err != nil {
+		i.resp.BadRequest(ctx, err)
+		return
+	}
+
+	if len(rows) == 0 {
+		i.resp.BadRequest(ctx, fmt.Errorf("%w, no users to import", entity.ErrValidationFailed))
+		return
+	}
+
+	i.resp.Ok(ctx).WithBody(ctx, i.do.Do(ctx, rows))
+}
+
+// readNDJSON reads one user per line, empty lines are ignored
+// line, that is not a valid user, is returned as a row with error, line longer than maxLineSize fails the whole import
+func readNDJSON(r io.Reader, maxRows, maxLineSize int) ([]entity.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+
+	// line is scanned together with it's new line character
+	size := maxLineSize + 1
+	if size > bufio.MaxScanTokenSize {
+		size = bufio.MaxScanTokenSize
+	}
+
+	scanner.Buffer(make([]byte, 0, size), maxLineSize+1)
+
+	rows := []entity.ImportRow{}
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(rows) == maxRows {
+			return nil, tooManyRows(maxRows)
+		}
+
+		row := entity.ImportRow{Row: len(rows) + 1}
+
+		if err := json.Unmarshal(line, &row.User); err != nil {
+			row.Err = fmt.Errorf("%w, invalid json", entity.ErrValidationFailed)
+		}
+
+		rows = append(rows, row)
+	}
+
+	err := scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return nil, lineTooLong(maxLineSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body, %w", err)
+	}
+
+	return rows, nil
+}
+
+// readCSV reads header row and one user per row after it
+// row, that is not a valid user, is returned as a row with error
+func readCSV(r io.Reader, maxRows int) ([]entity.ImportRow, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w, invalid csv header, %s", entity.ErrValidationFailed, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for n, c := range header {
+		columns[strings.TrimSpace(c)] = n
+	}
+
+	for _, c := range importColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("%w, csv header must have %s column", entity.ErrValidationFailed, c)
+		}
+	}
+
+	rows := []entity.ImportRow{}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("failed to read body, %w", err)
+		}
+
+		if len(rows) == maxRows {
+			return nil, tooManyRows(maxRows)
+		}
+
+		row := entity.ImportRow{Row: len(rows) + 1}
+
+		if err != nil {
+			row.Err = fmt.Errorf("%w, invalid csv row, %s", entity.ErrValidationFailed, parseErr.Err)
+		} else {
+			row.User, row.Err = csvUser(record, columns)
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+// csvUser returns user from csv record, columns are indexes of user fields in record
+func csvUser(record []string, columns map[string]int) (entity.UserRequest, error) {
+	country, err := strconv.Atoi(record[columns[importColumnCountry]])
+	if err != nil {
+		return entity.UserRequest{}, fmt.Errorf("%w, country must be a number", entity.ErrValidationFailed)
+	}
+
+	return entity.UserRequest{
+		FirstName: record[columns[importColumnFirstName]],
+		LastName:  record[columns[importColumnLastName]],
+		NickName:  record[columns[importColumnNickName]],
+		Email:     record[columns[importColumnEmail]],
+		Password:  record[columns[importColumnPassword]],
+		CountryID: country,
+	}, nil
+}
+
+func tooManyRows(maxRows int) error {
+	return fmt.Errorf("%w, import is limited to %d users", entity.ErrValidationFailed, maxRows)
+}
+
+func lineTooLong(maxLineSize int) error {
+	return fmt.Errorf("%w, line is longer than %d bytes", entity.ErrValidationFailed, maxLineSize)
+}
+
+// lineLimitReader fails, once a line of r is longer than max bytes,
+// so csv reader never buffers a longer one
+type lineLimitReader struct {
+	r    io.Reader
+	max  int
+	line int
+}
+
+func (l *lineLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+
+	for _, b := range p[:n] {
+		if b == '\n' {
+			l.line = 0
+			continue
+		}
+
+		l.line++
+
+		if l.line > l.max {
+			return 0, lineTooLong(l.max)
+		}
+	}
+
+	return n, err
+}
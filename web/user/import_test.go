@@ -0,0 +1,160 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	importURL = "http://localhost:8080/v1/admin/users/import"
+
+	testImportMaxRows = 3
+	testImportMaxLine = 256
+)
+
+var (
+	testImportUser = entity.UserRequest{
+		FirstName: "David",
+		LastName:  "Bowie",
+		NickName:  "star man",
+		Email:     "david@bowie.com",
+		Password:  "ziggy stardust",
+		CountryID: 1,
+	}
+
+	testImportReport = entity.ImportReport{
+		Created: 1,
+		Rows:    []entity.ImportResult{{Row: 1, Status: entity.ImportCreated, ID: 1}},
+	}
+)
+
+type testCaseImport struct {
+	url                string
+	body               string
+	maxBody            int64
+	rows               []entity.ImportRow
+	expectedStatusCode int
+}
+
+func (tc testCaseImport) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
+	assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+	if tc.expectedStatusCode == http.StatusOK {
+		var resp entity.ImportReport
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, testImportReport, resp)
+	}
+}
+
+func TestImport(t *testing.T) {
+	ndjsonRow, err := json.Marshal(testImportUser)
+	assert.Nil(t, err)
+
+	invalidJSON := fmt.Errorf("%w, invalid json", entity.ErrValidationFailed)
+
+	for name, tc := range map[string]testCaseImport{
+		"positive_200_ndjson": {
+			url:  importURL,
+			body: string(ndjsonRow) + "\n\n{\"nickName\":\n" + string(ndjsonRow),
+			rows: []entity.ImportRow{
+				{Row: 1, User: testImportUser},
+				{Row: 2, Err: invalidJSON},
+				{Row: 3, User: testImportUser},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		"positive_200_csv": {
+			url: importURL + "?format=csv",
+			body: "country,email,firstName,lastName,nickName,password\n" +
+				"1,david@bowie.com,David,Bowie,star man,ziggy stardust\n" +
+				"GB,david@bowie.com,David,Bowie,star man,ziggy stardust\n" +
+				"1,david@bowie.com\n",
+			rows: []entity.ImportRow{
+				{Row: 1, User: testImportUser},
+				{Row: 2, Err: fmt.Errorf("%w, country must be a number", entity.ErrValidationFailed)},
+				{Row: 3, Err: fmt.Errorf("%w, invalid csv row, %s", entity.ErrValidationFailed, "wrong number of fields")},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		"negative_400_format": {
+			url:                importURL + "?format=xml",
+			body:               string(ndjsonRow),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_empty": {
+			url:                importURL,
+			body:               "\n\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_csv_header": {
+			url:                importURL + "?format=csv",
+			body:               "firstName,lastName,nickName,email,country\nDavid,Bowie,star man,david@bowie.com,1\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_too_many_rows": {
+			url:                importURL,
+			body:               strings.Repeat(string(ndjsonRow)+"\n", testImportMaxRows+1),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_ndjson_line_too_long": {
+			url:                importURL,
+			body:               string(ndjsonRow) + "\n{\"nickName\":\"" + strings.Repeat("a", testImportMaxLine) + "\"}\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_csv_line_too_long": {
+			url: importURL + "?format=csv",
+			body: "country,email,firstName,lastName,nickName,password\n" +
+				"1,david@bowie.com,David,Bowie," + strings.Repeat("a", testImportMaxLine) + ",ziggy stardust\n",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_413_body_too_large": {
+			url:                importURL,
+			body:               strings.Repeat(string(ndjsonRow)+"\n", testImportMaxRows),
+			maxBody:            int64(len(ndjsonRow)),
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientImport := mock_user.NewMockimporter(ctr)
+			if tc.rows != nil {
+				mockClientImport.EXPECT().Do(ctx, tc.rows).Return(testImportReport)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body)).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			if tc.maxBody > 0 {
+				req.Body = http.MaxBytesReader(w, req.Body, tc.maxBody)
+			}
+
+			newImport(web.NewResponse(w, logger), mockClientImport, testImportMaxRows, testImportMaxLine).Do(web.NewRequest(req))
+
+			tc.checkresult(t, w)
+		})
+	}
+}
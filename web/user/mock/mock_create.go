@@ -35,10 +35,10 @@ func (m *Mockcreate) EXPECT() *MockcreateMockRecorder {
 }
 
 // Create mocks base method
-func (m *Mockcreate) Create(ctx context.Context, u entity.User) (int, error) {
+func (m *Mockcreate) Create(ctx context.Context, u entity.User) (entity.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, u)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -73,13 +73,52 @@ func (m *Mocknotifier) EXPECT() *MocknotifierMockRecorder {
 }
 
 // Add mocks base method
-func (m *Mocknotifier) Add(message entity.NotifierMessage) {
+func (m *Mocknotifier) Add(ctx context.Context, message entity.NotifierMessage) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Add", message)
+	ret := m.ctrl.Call(m, "Add", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Add indicates an expected call of Add
-func (mr *MocknotifierMockRecorder) Add(message interface{}) *gomock.Call {
+func (mr *MocknotifierMockRecorder) Add(ctx, message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*Mocknotifier)(nil).Add), message)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*Mocknotifier)(nil).Add), ctx, message)
+}
+
+// Mocksubscriptions is a mock of subscriptions interface
+type Mocksubscriptions struct {
+	ctrl     *gomock.Controller
+	recorder *MocksubscriptionsMockRecorder
+}
+
+// MocksubscriptionsMockRecorder is the mock recorder for Mocksubscriptions
+type MocksubscriptionsMockRecorder struct {
+	mock *Mocksubscriptions
+}
+
+// NewMocksubscriptions creates a new mock instance
+func NewMocksubscriptions(ctrl *gomock.Controller) *Mocksubscriptions {
+	mock := &Mocksubscriptions{ctrl: ctrl}
+	mock.recorder = &MocksubscriptionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Mocksubscriptions) EXPECT() *MocksubscriptionsMockRecorder {
+	return m.recorder
+}
+
+// Consumers mocks base method
+func (m *Mocksubscriptions) Consumers(action string, countryID int) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consumers", action, countryID)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Consumers indicates an expected call of Consumers
+func (mr *MocksubscriptionsMockRecorder) Consumers(action, countryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consumers", reflect.TypeOf((*Mocksubscriptions)(nil).Consumers), action, countryID)
 }
@@ -35,11 +35,12 @@ func (m *Mockdelete) EXPECT() *MockdeleteMockRecorder {
 }
 
 // Delete mocks base method
-func (m *Mockdelete) Delete(ctx context.Context, user entity.User) error {
+func (m *Mockdelete) Delete(ctx context.Context, user entity.User) (entity.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Delete", ctx, user)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete
@@ -35,11 +35,12 @@ func (m *Mockupdate) EXPECT() *MockupdateMockRecorder {
 }
 
 // Update mocks base method
-func (m *Mockupdate) Update(ctx context.Context, u entity.User) error {
+func (m *Mockupdate) Update(ctx context.Context, u entity.User) (entity.UserChange, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Update", ctx, u)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.UserChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
@@ -48,5 +48,5 @@ func (o *One) Do(r *web.Request) {
 		return
 	}
 
-	o.resp.Ok(ctx).WithBody(ctx, user.ToResponse())
+	o.resp.ETag(ctx, etag(user.Version)).Ok(ctx).WithBody(ctx, user.ToResponse())
 }
@@ -69,6 +69,7 @@ func TestOne(t *testing.T) {
 		newOne(web.NewResponse(w, logger), mockClientOne).Do(web.NewRequest(req))
 
 		tc.checkresult(t, w)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
 	})
 
 	t.Run("negative_400_missing_userId", func(t *testing.T) {
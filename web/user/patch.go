@@ -0,0 +1,110 @@
+//go:generate mockgen -source ../user/patch.go -destination ../user/mock/mock_patch.go
+
+package user
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strconv"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type patch interface {
+	Patch(ctx context.Context, p entity.UserPatch) (entity.User, entity.UserChange, error)
+}
+
+// Patch is a patch user endpoint struct
+type Patch struct {
+	do            patch
+	resp          *web.Response
+	notify        notifier
+	subscriptions subscriptions
+}
+
+func newPatch(r *web.Response, p patch, n notifier, s subscriptions) *Patch {
+	return &Patch{
+		do:            p,
+		resp:          r,
+		notify:        n,
+		subscriptions: s,
+	}
+}
+
+// Do is getting user's id from URL and it's version from If-Match header, applies merge patch from body to user,
+// if it was not changed since that version, and sending a notification with user's change set.
+// Patched user is returned with it's new version as ETag, empty patch changes nothing and sends no notification
+func (p *Patch) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamUserID)
+	if id == nil {
+		p.resp.BadRequest(ctx, entity.ErrUserIDIsMissing)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if errors.Is(err, entity.ErrVersionIsMissing) {
+		p.resp.PreconditionRequired(ctx, err)
+		return
+	}
+	if err != nil {
+		p.resp.BadRequest(ctx, err)
+		return
+	}
+
+	doc, err := ioutil.ReadAll(r.Body())
+	if err != nil {
+		p.resp.BadRequest(ctx, err)
+		return
+	}
+
+	userPatch, err := entity.NewUserPatch(doc)
+	if err != nil {
+		p.resp.BadRequest(ctx, err)
+		return
+	}
+
+	err = userPatch.Validate()
+	if err != nil {
+		p.resp.BadRequest(ctx, err)
+		return
+	}
+
+	userPatch.ID = *id
+	userPatch.Version = version
+
+	user, change, err := p.do.Patch(ctx, userPatch)
+	if errors.Is(err, entity.ErrNotFound) {
+		p.resp.NotFound(ctx, err)
+		return
+	}
+	if errors.Is(err, entity.ErrInvalidPassword) || errors.Is(err, entity.ErrValidationFailed) {
+		p.resp.BadRequest(ctx, err)
+		return
+	}
+	if errors.Is(err, entity.ErrVersionConflict) {
+		p.resp.PreconditionFailed(ctx, err)
+		return
+	}
+	if conflict(ctx, p.resp, err) {
+		return
+	}
+	if err != nil {
+		p.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	if !userPatch.Empty() {
+		notifyStored(ctx, p.resp, p.notify, entity.NotifierMessage{
+			ID:        cont.ProcessID(ctx),
+			Key:       strconv.Itoa(user.ID),
+			Message:   entity.NewUserChangeEvent(cont.ProcessID(ctx), change),
+			Consumers: p.subscriptions.Consumers(actionUpdate, user.CountryID)})
+	}
+
+	p.resp.ETag(ctx, etag(user.Version)).Ok(ctx).WithBody(ctx, user.ToResponse())
+}
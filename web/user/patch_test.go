@@ -0,0 +1,210 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	patchURL = "http://localhost:8080/v1/user"
+)
+
+func TestPatch(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+		logger := logger.New(mockLogger)
+
+		nickName := "Freddy"
+		userPatch := entity.UserPatch{ID: testUserID, Version: testUserVersion, Password: "qwerty", NickName: &nickName}
+
+		patched := testUser
+		patched.NickName = nickName
+		patched.Version = testUserVersion + 1
+
+		change := entity.NewUserChange(testUser.ToResponse(), patched.ToResponse())
+
+		mockClientPatch := mock_user.NewMockpatch(ctr)
+		mockClientPatch.EXPECT().Patch(ctx, userPatch).Return(patched, change, nil)
+
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionUpdate, patched.CountryID).Return(testConsumers)
+
+		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Add(ctx, userChangeEvent(change, testConsumers)).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d", patchURL, testUserID),
+			strings.NewReader(`{"nickName":"Freddy","password":"qwerty"}`)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newPatch(web.NewResponse(w, logger), mockClientPatch, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+		var resp entity.UserResponse
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, patched.ToResponse(), resp)
+	})
+
+	t.Run("positive_200_empty_patch", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+		logger := logger.New(mockLogger)
+
+		userPatch := entity.UserPatch{ID: testUserID, Version: testUserVersion, Password: "qwerty"}
+
+		mockClientPatch := mock_user.NewMockpatch(ctr)
+		mockClientPatch.EXPECT().Patch(ctx, userPatch).
+			Return(testUser, entity.NewUserChange(testUser.ToResponse(), testUser.ToResponse()), nil)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d", patchURL, testUserID),
+			strings.NewReader(`{"password":"qwerty"}`)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newPatch(web.NewResponse(w, logger), mockClientPatch, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).
+			Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testUserVersionTag, w.Header().Get("ETag"))
+	})
+
+	for name, tc := range map[string]struct {
+		err                error
+		expectedStatusCode int
+	}{
+		"negative_404_user_not_found":    {entity.ErrNotFound, http.StatusNotFound},
+		"negative_400_invalid_password":  {entity.ErrInvalidPassword, http.StatusBadRequest},
+		"negative_400_country_not_found": {entity.ErrValidationFailed, http.StatusBadRequest},
+		"negative_409_user_exist":        {&entity.UserExistError{Field: entity.FieldEmail}, http.StatusConflict},
+		"negative_412_version_conflict":  {&entity.VersionConflictError{ID: testUserID, Version: testUserVersion}, http.StatusPreconditionFailed},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), "id", testUserID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+			logger := logger.New(mockLogger)
+
+			email := "freddy@test.go"
+			country := 2
+			userPatch := entity.UserPatch{ID: testUserID, Version: testUserVersion, Password: "qwerty", Email: &email, CountryID: &country}
+
+			mockClientPatch := mock_user.NewMockpatch(ctr)
+			mockClientPatch.EXPECT().Patch(ctx, userPatch).Return(entity.User{}, entity.UserChange{}, tc.err)
+
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d", patchURL, testUserID),
+				strings.NewReader(`{"email":"freddy@test.go","country":2,"password":"qwerty"}`)).WithContext(ctx)
+			req.Header.Set("If-Match", testUserVersionTag)
+
+			w := httptest.NewRecorder()
+
+			newPatch(web.NewResponse(w, logger), mockClientPatch, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).
+				Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+
+	t.Run("negative_500_client_error", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+
+		logger := logger.New(mockLogger)
+
+		mockClientPatch := mock_user.NewMockpatch(ctr)
+		mockClientPatch.EXPECT().Patch(ctx, gomock.Any()).Return(entity.User{}, entity.UserChange{}, errTest)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d", patchURL, testUserID),
+			strings.NewReader(`{"lastName":"Jones","password":"qwerty"}`)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newPatch(web.NewResponse(w, logger), mockClientPatch, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).
+			Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	for name, tc := range map[string]struct {
+		body               string
+		ifMatch            string
+		expectedStatusCode int
+	}{
+		"negative_428_missing_if_match":  {`{"nickName":"Freddy"}`, "", http.StatusPreconditionRequired},
+		"negative_400_not_a_version":     {`{"nickName":"Freddy"}`, `"abc"`, http.StatusBadRequest},
+		"negative_400_not_an_object":     {`["nickName"]`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_null_patch":        {`null`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_invalid_json":      {`{"nickName":`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_remove_field":      {`{"nickName":null}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_empty_field":       {`{"firstName":""}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_invalid_email":     {`{"email":"freddy"}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_invalid_type":      {`{"country":"GB"}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_read_only_field":   {`{"id":2}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_unknown_field":     {`{"age":42}`, testUserVersionTag, http.StatusBadRequest},
+		"negative_400_request_name_typo": {`{"firstNmae":"Fred"}`, testUserVersionTag, http.StatusBadRequest},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), "id", testUserID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+			logger := logger.New(mockLogger)
+
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d", patchURL, testUserID), strings.NewReader(tc.body)).
+				WithContext(ctx)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+
+			newPatch(web.NewResponse(w, logger), mock_user.NewMockpatch(ctr), mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).
+				Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
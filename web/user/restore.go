@@ -0,0 +1,67 @@
+//go:generate mockgen -source ../user/restore.go -destination ../user/mock/mock_restore.go
+
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	cont "github.com/faceit/test/contextvalue"
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+type restore interface {
+	Restore(ctx context.Context, id int) (entity.User, error)
+}
+
+// Restore is a restore deleted user endpoint struct
+type Restore struct {
+	do            restore
+	resp          *web.Response
+	notify        notifier
+	subscriptions subscriptions
+}
+
+func newRestore(r *web.Response, re restore, n notifier, s subscriptions) *Restore {
+	return &Restore{
+		do:            re,
+		resp:          r,
+		notify:        n,
+		subscriptions: s,
+	}
+}
+
+// Do is getting user's id from URL, restores deleted user with that ID and sending a notification with restored user
+// restored user is returned with it's new version as ETag
+func (re *Restore) Do(r *web.Request) {
+	ctx := r.Context()
+
+	id := r.GetPathParamsInt(pathParamUserID)
+	if id == nil {
+		re.resp.BadRequest(ctx, entity.ErrUserIDIsMissing)
+		return
+	}
+
+	user, err := re.do.Restore(ctx, *id)
+	if errors.Is(err, entity.ErrNotFound) {
+		re.resp.NotFound(ctx, err)
+		return
+	}
+	if conflict(ctx, re.resp, err) {
+		return
+	}
+	if err != nil {
+		re.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	notifyStored(ctx, re.resp, re.notify, entity.NotifierMessage{
+		ID:        cont.ProcessID(ctx),
+		Key:       strconv.Itoa(user.ID),
+		Message:   entity.NewUserRestoreEvent(cont.ProcessID(ctx), user.ToResponse()),
+		Consumers: re.subscriptions.Consumers(actionCreate, user.CountryID)})
+
+	re.resp.ETag(ctx, etag(user.Version)).Ok(ctx).WithBody(ctx, user.ToResponse())
+}
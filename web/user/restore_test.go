@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	restoreURL = "http://localhost:8080/v1/admin/user/%d/restore"
+)
+
+func userRestoreEvent(user entity.UserResponse, consumers []string) gomock.Matcher {
+	return userEventMatcher{event: entity.NewUserRestoreEvent("", user), consumers: consumers}
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("positive_200", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+		mockClientRestore := mock_user.NewMockrestore(ctr)
+		mockClientRestore.EXPECT().Restore(ctx, testUserID).Return(testUser, nil)
+
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionCreate, testUser.CountryID).Return(testConsumers)
+
+		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Add(ctx, userRestoreEvent(testUser.ToResponse(), testConsumers)).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(restoreURL, testUserID), nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newRestore(web.NewResponse(w, logger.New(mockLogger)), mockClientRestore, mockNotifier, mockSubscriptions).
+			Do(web.NewRequest(req))
+
+		testCaseOne{expectedResponse: &testUserResponse, expectedStatusCode: http.StatusOK}.checkresult(t, w)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("positive_200_queue_full", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+		mockClientRestore := mock_user.NewMockrestore(ctr)
+		mockClientRestore.EXPECT().Restore(ctx, testUserID).Return(testUser, nil)
+
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionCreate, testUser.CountryID).Return(testConsumers)
+
+		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Add(ctx, gomock.Any()).Return(entity.ErrQueueFull)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(restoreURL, testUserID), nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		newRestore(web.NewResponse(w, logger.New(mockLogger)), mockClientRestore, mockNotifier, mockSubscriptions).
+			Do(web.NewRequest(req))
+
+		testCaseOne{expectedResponse: &testUserResponse, expectedStatusCode: http.StatusOK}.checkresult(t, w)
+	})
+
+	for name, tc := range map[string]struct {
+		err                error
+		expectedStatusCode int
+	}{
+		"negative_404_not_deleted":  {entity.ErrNotFound, http.StatusNotFound},
+		"negative_409_email_taken":  {&entity.UserExistError{Field: entity.FieldEmail}, http.StatusConflict},
+		"negative_500_client_error": {errTest, http.StatusInternalServerError},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), "id", testUserID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+
+			mockClientRestore := mock_user.NewMockrestore(ctr)
+			mockClientRestore.EXPECT().Restore(ctx, testUserID).Return(entity.User{}, tc.err)
+
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(restoreURL, testUserID), nil).WithContext(ctx)
+
+			w := httptest.NewRecorder()
+
+			newRestore(web.NewResponse(w, logger.New(mockLogger)), mockClientRestore, mock_user.NewMocknotifier(ctr),
+				mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
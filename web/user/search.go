@@ -0,0 +1,64 @@
+//go:generate mockgen -source ../user/search.go -destination ../user/mock/mock_search.go
+
+package user
+
+import (
+	"context"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/web"
+)
+
+// queryParamQueryConst is a search query parameter
+const queryParamQueryConst = "q"
+
+type search interface {
+	Search(ctx context.Context, s entity.UserSearch) ([]entity.User, error)
+}
+
+// Search is a user search endpoint struct
+type Search struct {
+	do   search
+	resp *web.Response
+}
+
+func newSearch(r *web.Response, s search) *Search {
+	return &Search{
+		do:   s,
+		resp: r,
+	}
+}
+
+// Do returns users, that match q query parameter by nick name, first or last name and email,
+// the most relevant users come first. Number of users is set by limit query parameter
+func (s *Search) Do(r *web.Request) {
+	ctx := r.Context()
+
+	limit, err := queryLimit(r)
+	if err != nil {
+		s.resp.BadRequest(ctx, err)
+		return
+	}
+
+	req, err := entity.NewUserSearch(r.GetQueryParamsString(queryParamQueryConst), limit)
+	if err != nil {
+		s.resp.BadRequest(ctx, err)
+		return
+	}
+
+	users, err := s.do.Search(ctx, req)
+	if err != nil {
+		s.resp.InternalServerError(ctx, err)
+		return
+	}
+
+	resp := entity.UserPage{
+		Users: make([]entity.UserResponse, len(users)),
+	}
+
+	for i := range users {
+		resp.Users[i] = users[i].ToResponse()
+	}
+
+	s.resp.Ok(ctx).WithBody(ctx, resp)
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faceit/test/entity"
+	"github.com/faceit/test/logger"
+	mock_logger "github.com/faceit/test/logger/mock"
+	"github.com/faceit/test/web"
+	mock_user "github.com/faceit/test/web/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	searchURL = "http://localhost:8080/v1/user/search"
+)
+
+type testCaseSearch struct {
+	url                string
+	search             entity.UserSearch
+	users              []entity.User
+	err                error
+	expectedResponse   *entity.UserPage
+	expectedStatusCode int
+}
+
+func (tc testCaseSearch) checkresult(t *testing.T, w *httptest.ResponseRecorder) {
+	assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+	if tc.expectedResponse != nil {
+		var resp entity.UserPage
+
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, *tc.expectedResponse, resp)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	for name, tc := range map[string]testCaseSearch{
+		"positive_200": {
+			url:                searchURL + "?q=%20prin%20",
+			search:             entity.UserSearch{Query: "prin", Limit: entity.SearchLimitDefault},
+			users:              []entity.User{testUser},
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{testUser.ToResponse()}},
+			expectedStatusCode: http.StatusOK,
+		},
+		"positive_200_limit": {
+			url:                searchURL + "?q=prin&limit=5",
+			search:             entity.UserSearch{Query: "prin", Limit: 5},
+			users:              []entity.User{},
+			expectedResponse:   &entity.UserPage{Users: []entity.UserResponse{}},
+			expectedStatusCode: http.StatusOK,
+		},
+		"negative_400_empty_query": {
+			url:                searchURL + "?q=%20",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_long_query": {
+			url:                searchURL + "?q=" + strings.Repeat("a", 101),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_invalid_limit": {
+			url:                searchURL + "?q=prin&limit=few",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_400_limit_too_large": {
+			url:                fmt.Sprintf("%s?q=prin&limit=%d", searchURL, entity.SearchLimitMax+1),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"negative_500_client_error": {
+			url:                searchURL + "?q=prin",
+			search:             entity.UserSearch{Query: "prin", Limit: entity.SearchLimitDefault},
+			err:                errTest,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.Background()
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			logger := logger.New(mockLogger)
+
+			mockClientSearch := mock_user.NewMocksearch(ctr)
+			if tc.search.Query != "" {
+				mockClientSearch.EXPECT().Search(ctx, tc.search).Return(tc.users, tc.err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			newSearch(web.NewResponse(w, logger), mockClientSearch).Do(web.NewRequest(req))
+
+			tc.checkresult(t, w)
+		})
+	}
+}
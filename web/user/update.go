@@ -5,38 +5,42 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 
+	cont "github.com/faceit/test/contextvalue"
 	"github.com/faceit/test/entity"
 	"github.com/faceit/test/web"
 )
 
 const (
 	pathParamUserID = "id"
-	actionUpdate    = "UPDATE"
+	actionUpdate    = entity.ActionUpdate
 )
 
 type update interface {
-	Update(ctx context.Context, u entity.User) error
+	Update(ctx context.Context, u entity.User) (entity.UserChange, error)
 }
 
 // Update is a update user endpoint struct
 type Update struct {
-	do        update
-	resp      *web.Response
-	notify    notifier
-	consumers []string
+	do            update
+	resp          *web.Response
+	notify        notifier
+	subscriptions subscriptions
 }
 
-func newUpdate(r *web.Response, u update, n notifier, consumers []string) *Update {
+func newUpdate(r *web.Response, u update, n notifier, s subscriptions) *Update {
 	return &Update{
-		do:        u,
-		resp:      r,
-		notify:    n,
-		consumers: consumers,
+		do:            u,
+		resp:          r,
+		notify:        n,
+		subscriptions: s,
 	}
 }
 
-// Do is getting user's id from URL, updates user and sending a notification
+// Do is getting user's id from URL and it's version from If-Match header, updates user, if it was not changed
+// since that version, and sending a notification with user's change set. New version is returned as ETag
 func (u *Update) Do(r *web.Request) {
 	ctx := r.Context()
 
@@ -46,9 +50,25 @@ func (u *Update) Do(r *web.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if errors.Is(err, entity.ErrVersionIsMissing) {
+		u.resp.PreconditionRequired(ctx, err)
+		return
+	}
+	if err != nil {
+		u.resp.BadRequest(ctx, err)
+		return
+	}
+
 	var reqBody entity.UserRequest
 
-	err := r.UnmarshalBodyJSON(&reqBody)
+	err = r.UnmarshalBodyJSON(&reqBody)
+	if err != nil {
+		u.resp.BadRequest(ctx, err)
+		return
+	}
+
+	err = reqBody.Validate()
 	if err != nil {
 		u.resp.BadRequest(ctx, err)
 		return
@@ -56,26 +76,59 @@ func (u *Update) Do(r *web.Request) {
 
 	user := reqBody.ToUser()
 	user.ID = *id
+	user.Version = version
 
-	err = u.do.Update(ctx, user)
+	change, err := u.do.Update(ctx, user)
 	if errors.Is(err, entity.ErrNotFound) {
 		u.resp.NotFound(ctx, err)
 		return
 	}
-	if errors.Is(err, entity.ErrInvalidPassword) {
+	if errors.Is(err, entity.ErrInvalidPassword) || errors.Is(err, entity.ErrValidationFailed) {
 		u.resp.BadRequest(ctx, err)
 		return
 	}
+	if errors.Is(err, entity.ErrVersionConflict) {
+		u.resp.PreconditionFailed(ctx, err)
+		return
+	}
+	if conflict(ctx, u.resp, err) {
+		return
+	}
 	if err != nil {
 		u.resp.InternalServerError(ctx, err)
 		return
 	}
 
-	u.notify.Add(entity.NotifierMessage{
-		Message: entity.UserNotification{
-			User:   user.ToResponse(),
-			Action: actionUpdate},
-		Consumers: u.consumers})
+	notifyStored(ctx, u.resp, u.notify, entity.NotifierMessage{
+		ID:        cont.ProcessID(ctx),
+		Key:       strconv.Itoa(user.ID),
+		Message:   entity.NewUserChangeEvent(cont.ProcessID(ctx), change),
+		Consumers: u.subscriptions.Consumers(actionUpdate, user.CountryID)})
+
+	u.resp.ETag(ctx, etag(version+1)).Ok(ctx)
+}
+
+// etag returns entity tag of user's version
+func etag(version int) string {
+	return strconv.Itoa(version)
+}
+
+// ifMatchVersion returns user's version, that is expected by request, from If-Match header
+// the only tag, that is user's version, is accepted, as update is checked against a single version
+func ifMatchVersion(r *web.Request) (int, error) {
+	tags := r.IfMatch()
+	if len(tags) == 0 {
+		return 0, fmt.Errorf("%w, If-Match header is required", entity.ErrVersionIsMissing)
+	}
+
+	if len(tags) > 1 {
+		return 0, fmt.Errorf("%w, If-Match must have a single user version", entity.ErrValidationFailed)
+	}
+
+	version, err := strconv.Atoi(tags[0])
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w, If-Match must be a user version, returned as ETag", entity.ErrValidationFailed)
+	}
 
-	u.resp.Ok(ctx)
+	return version, nil
 }
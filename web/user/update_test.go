@@ -19,7 +19,9 @@ import (
 )
 
 const (
-	updateURL = "http://localhost:8080/v1/users"
+	updateURL          = "http://localhost:8080/v1/users"
+	testUserVersion    = 1
+	testUserVersionTag = `"1"`
 )
 
 type testCaseUpdate struct {
@@ -41,7 +43,7 @@ func TestUpdate(t *testing.T) {
 				LastName:  "Bovie",
 				NickName:  "Prince",
 				Email:     "test@test.go",
-				Password:  "qwerty",
+				Password:  "qwertyui",
 				CountryID: 1,
 			},
 			consumers:          testConsumers,
@@ -59,30 +61,31 @@ func TestUpdate(t *testing.T) {
 		mockClientUpdate := mock_user.NewMockupdate(ctr)
 		userUpdate := tc.input.ToUser()
 		userUpdate.ID = testUserID
-		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(nil)
+		userUpdate.Version = testUserVersion
+		previous := userUpdate.ToResponse()
+		previous.NickName = "Freddy"
+
+		change := entity.NewUserChange(previous, userUpdate.ToResponse())
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(change, nil)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionUpdate, tc.input.CountryID).Return(tc.consumers)
 
-		userNotify := tc.input
-		userNotify.ID = testUserID
-		mockNotifier.EXPECT().Add(entity.NotifierMessage{
-			Message: entity.UserNotification{
-				User:   userNotify.ToUser().ToResponse(),
-				Action: actionUpdate,
-			},
-			Consumers: tc.consumers,
-		}).Times(1)
+		mockNotifier.EXPECT().Add(ctx, userChangeEvent(change, tc.consumers)).Return(nil)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
 
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
 
 		w := httptest.NewRecorder()
 
-		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
 	})
 
 	t.Run("negative_400_user_not_found", func(t *testing.T) {
@@ -95,7 +98,7 @@ func TestUpdate(t *testing.T) {
 				LastName:  "Bovie",
 				NickName:  "Prince",
 				Email:     "test@test.go",
-				Password:  "qwerty",
+				Password:  "qwertyui",
 				CountryID: 1,
 			},
 			consumers:          testConsumers,
@@ -114,18 +117,21 @@ func TestUpdate(t *testing.T) {
 		mockClientUpdate := mock_user.NewMockupdate(ctr)
 		userUpdate := tc.input.ToUser()
 		userUpdate.ID = testUserID
-		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.ErrNotFound)
+		userUpdate.Version = testUserVersion
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.UserChange{}, entity.ErrNotFound)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
 
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
 
 		w := httptest.NewRecorder()
 
-		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -140,7 +146,7 @@ func TestUpdate(t *testing.T) {
 				LastName:  "Bovie",
 				NickName:  "Prince",
 				Email:     "test@test.go",
-				Password:  "qwerty",
+				Password:  "qwertyui",
 				CountryID: 1,
 			},
 			consumers:          testConsumers,
@@ -159,18 +165,21 @@ func TestUpdate(t *testing.T) {
 		mockClientUpdate := mock_user.NewMockupdate(ctr)
 		userUpdate := tc.input.ToUser()
 		userUpdate.ID = testUserID
-		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.ErrInvalidPassword)
+		userUpdate.Version = testUserVersion
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.UserChange{}, entity.ErrInvalidPassword)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
 
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
 
 		w := httptest.NewRecorder()
 
-		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -185,7 +194,7 @@ func TestUpdate(t *testing.T) {
 				LastName:  "Bovie",
 				NickName:  "Prince",
 				Email:     "test@test.go",
-				Password:  "qwerty",
+				Password:  "qwertyui",
 				CountryID: 1,
 			},
 			consumers:          testConsumers,
@@ -204,17 +213,20 @@ func TestUpdate(t *testing.T) {
 		mockClientUpdate := mock_user.NewMockupdate(ctr)
 		userUpdate := tc.input.ToUser()
 		userUpdate.ID = testUserID
+		userUpdate.Version = testUserVersion
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
 
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
 
 		w := httptest.NewRecorder()
 
-		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
@@ -229,7 +241,7 @@ func TestUpdate(t *testing.T) {
 				LastName:  "Bovie",
 				NickName:  "Prince",
 				Email:     "test@test.go",
-				Password:  "qwerty",
+				Password:  "qwertyui",
 				CountryID: 1,
 			},
 			consumers:          testConsumers,
@@ -248,19 +260,200 @@ func TestUpdate(t *testing.T) {
 		mockClientUpdate := mock_user.NewMockupdate(ctr)
 		userUpdate := tc.input.ToUser()
 		userUpdate.ID = testUserID
-		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(errTest)
+		userUpdate.Version = testUserVersion
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.UserChange{}, errTest)
 
 		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
 
 		b, err := json.Marshal(tc.input)
 		assert.Nil(t, err)
 
 		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
 
 		w := httptest.NewRecorder()
 
-		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, testConsumers).Do(web.NewRequest(req))
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
 
 		assert.Equal(t, tc.expectedStatusCode, w.Code)
 	})
+
+	t.Run("negative_409_user_exist", func(t *testing.T) {
+		tc := testCaseUpdate{
+			url: fmt.Sprintf("%s/%d", updateURL, testUserID),
+
+			method: http.MethodPut,
+			input: entity.UserRequest{
+				FirstName: "David",
+				LastName:  "Bovie",
+				NickName:  "Prince",
+				Email:     "test@test.go",
+				Password:  "qwertyui",
+				CountryID: 1,
+			},
+			expectedStatusCode: http.StatusConflict,
+		}
+
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+		logger := logger.New(mockLogger)
+
+		mockClientUpdate := mock_user.NewMockupdate(ctr)
+		userUpdate := tc.input.ToUser()
+		userUpdate.ID = testUserID
+		userUpdate.Version = testUserVersion
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(entity.UserChange{}, &entity.UserExistError{Field: entity.FieldNickName})
+
+		b, err := json.Marshal(tc.input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(tc.method, tc.url, bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+		var resp entity.ConflictResponse
+
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.FieldNickName, resp.Field)
+	})
+
+	t.Run("negative_412_version_conflict", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+		mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+		logger := logger.New(mockLogger)
+
+		input := entity.UserRequest{FirstName: "David", LastName: "Bovie", NickName: "Prince", Email: "test@test.go", Password: "qwertyui", CountryID: 1}
+
+		mockClientUpdate := mock_user.NewMockupdate(ctr)
+		userUpdate := input.ToUser()
+		userUpdate.ID = testUserID
+		userUpdate.Version = testUserVersion
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).
+			Return(entity.UserChange{}, &entity.VersionConflictError{ID: testUserID, Version: testUserVersion})
+
+		b, err := json.Marshal(input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", updateURL, testUserID), bytes.NewReader(b)).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	for name, body := range map[string]string{
+		"negative_400_empty_first_name": `{"firstName":"","lastName":"Bovie","nickName":"Prince","email":"test@test.go","password":"qwertyui","country":1}`,
+		"negative_400_invalid_email":    `{"firstName":"David","lastName":"Bovie","nickName":"Prince","email":"test","password":"qwertyui","country":1}`,
+		"negative_400_missing_country":  `{"firstName":"David","lastName":"Bovie","nickName":"Prince","email":"test@test.go","password":"qwertyui"}`,
+	} {
+		body := body
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), "id", testUserID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+			logger := logger.New(mockLogger)
+
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", updateURL, testUserID), bytes.NewReader([]byte(body))).WithContext(ctx)
+			req.Header.Set("If-Match", testUserVersionTag)
+
+			w := httptest.NewRecorder()
+
+			newUpdate(web.NewResponse(w, logger), mock_user.NewMockupdate(ctr), mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	t.Run("positive_200_legacy_first_name", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		ctx := context.WithValue(context.Background(), "id", testUserID)
+
+		mockLogger := mock_logger.NewMocklog(ctr)
+		mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+
+		logger := logger.New(mockLogger)
+
+		userUpdate := entity.User{ID: testUserID, FirstName: "David", LastName: "Bovie", NickName: "Prince", Email: "test@test.go",
+			Password: "qwertyui", CountryID: 1, Version: testUserVersion}
+		change := entity.NewUserChange(userUpdate.ToResponse(), userUpdate.ToResponse())
+
+		mockClientUpdate := mock_user.NewMockupdate(ctr)
+		mockClientUpdate.EXPECT().Update(ctx, userUpdate).Return(change, nil)
+
+		mockSubscriptions := mock_user.NewMocksubscriptions(ctr)
+		mockSubscriptions.EXPECT().Consumers(actionUpdate, 1).Return(testConsumers)
+
+		mockNotifier := mock_user.NewMocknotifier(ctr)
+		mockNotifier.EXPECT().Add(ctx, userChangeEvent(change, testConsumers)).Return(nil)
+
+		body := `{"firstNmae":"David","lastName":"Bovie","nickName":"Prince","email":"test@test.go","password":"qwertyui","country":1}`
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", updateURL, testUserID), bytes.NewReader([]byte(body))).WithContext(ctx)
+		req.Header.Set("If-Match", testUserVersionTag)
+
+		w := httptest.NewRecorder()
+
+		newUpdate(web.NewResponse(w, logger), mockClientUpdate, mockNotifier, mockSubscriptions).Do(web.NewRequest(req))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	for name, tc := range map[string]struct {
+		ifMatch            string
+		expectedStatusCode int
+	}{
+		"negative_428_missing_if_match": {"", http.StatusPreconditionRequired},
+		"negative_400_not_a_version":    {`"abc"`, http.StatusBadRequest},
+		"negative_400_weak_tag":         {`W/"1"`, http.StatusBadRequest},
+		"negative_400_several_versions": {`"1", "2"`, http.StatusBadRequest},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			ctx := context.WithValue(context.Background(), "id", testUserID)
+
+			mockLogger := mock_logger.NewMocklog(ctr)
+			mockLogger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warningf(gomock.Any(), gomock.Any())
+
+			logger := logger.New(mockLogger)
+
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", updateURL, testUserID), bytes.NewReader([]byte(`{}`))).WithContext(ctx)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+
+			newUpdate(web.NewResponse(w, logger), mock_user.NewMockupdate(ctr), mock_user.NewMocknotifier(ctr), mock_user.NewMocksubscriptions(ctr)).Do(web.NewRequest(req))
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
 }